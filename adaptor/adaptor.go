@@ -0,0 +1,128 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package adaptor implements Schnorr adaptor signatures over ristretto255.
+//
+// A PreSignature on a message is bound to an adaptor point T = t * G. Anyone
+// can check it against the signer's public key and T, but it only becomes a
+// valid schnorrsig.Signature once it is adapted with the secret t. Conversely,
+// given the PreSignature and the adapted Signature, anyone can extract t.
+// This is the building block of scriptless atomic swaps: publishing the
+// Signature on one chain reveals the secret needed to complete the other.
+package adaptor
+
+import (
+	"errors"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/schnorrsig"
+)
+
+// PreSignatureSize is the size in bytes of an encoded PreSignature.
+const PreSignatureSize = 64
+
+var (
+	errVerification = errors.New("adaptor: pre-signature verification failed")
+	errAdaptorPoint = errors.New("adaptor: invalid adaptor point")
+)
+
+// PreSignature is a pre-signature (R, s') with R = k * G + T and
+// s' = k + c * x, where c = schnorrsig.Challenge(R, P, msg). The
+// adapted signature is (R, s' + t).
+type PreSignature struct {
+	R *ristretto255.Element
+	S *ristretto255.Scalar
+}
+
+// PreSign returns a pre-signature on msg by the secret key x, for the adaptor
+// point T, which must not be the identity.
+func PreSign(x *ristretto255.Scalar, msg []byte, T *ristretto255.Element) (*PreSignature, error) {
+	if T.Equal(ristretto255.NewIdentityElement()) == 1 {
+		return nil, errAdaptorPoint
+	}
+	k, err := scalar.Random(nil)
+	if err != nil {
+		return nil, err
+	}
+	P := ristretto255.NewIdentityElement().ScalarBaseMult(x)
+	R := ristretto255.NewIdentityElement().ScalarBaseMult(k)
+	R.Add(R, T)
+	c := schnorrsig.Challenge(R, P, msg)
+	s := ristretto255.NewScalar().Multiply(c, x)
+	return &PreSignature{R: R, S: s.Add(s, k)}, nil
+}
+
+// Verify returns nil if pre is a valid pre-signature on msg by the public key
+// P for the adaptor point T, that is, if s' * G = R - T + c * P, and an error
+// otherwise. A valid pre-signature guarantees that Adapt with the discrete
+// log of T produces a valid signature.
+func (pre *PreSignature) Verify(P *ristretto255.Element, msg []byte, T *ristretto255.Element) error {
+	identity := ristretto255.NewIdentityElement()
+	if T.Equal(identity) == 1 {
+		return errAdaptorPoint
+	}
+	if P.Equal(identity) == 1 {
+		return errors.New("adaptor: invalid public key")
+	}
+	if pre == nil || pre.R == nil || pre.S == nil {
+		return errVerification
+	}
+	c := schnorrsig.Challenge(pre.R, P, msg)
+	check := ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(c.Negate(c), P, pre.S)
+	check.Add(check, T)
+	if check.Equal(pre.R) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+// Adapt returns the signature (R, s' + t) completed with the secret t of the
+// adaptor point.
+func (pre *PreSignature) Adapt(t *ristretto255.Scalar) *schnorrsig.Signature {
+	return &schnorrsig.Signature{
+		R: ristretto255.NewIdentityElement().Set(pre.R),
+		S: ristretto255.NewScalar().Add(pre.S, t),
+	}
+}
+
+// Extract returns the secret t = s - s' of the adaptor point T, given the
+// signature sig obtained by adapting pre. It returns an error if sig was not
+// adapted from pre with the discrete log of T.
+func (pre *PreSignature) Extract(sig *schnorrsig.Signature, T *ristretto255.Element) (*ristretto255.Scalar, error) {
+	if sig == nil || sig.R == nil || sig.S == nil || sig.R.Equal(pre.R) != 1 {
+		return nil, errors.New("adaptor: signature does not match pre-signature")
+	}
+	t := ristretto255.NewScalar().Subtract(sig.S, pre.S)
+	if ristretto255.NewIdentityElement().ScalarBaseMult(t).Equal(T) != 1 {
+		return nil, errors.New("adaptor: extracted secret does not match adaptor point")
+	}
+	return t, nil
+}
+
+// Bytes returns the 64-byte encoding of the pre-signature, R || s'.
+func (pre *PreSignature) Bytes() []byte {
+	out := make([]byte, 0, PreSignatureSize)
+	out = append(out, pre.R.Bytes()...)
+	return append(out, pre.S.Bytes()...)
+}
+
+// SetBytes sets pre to the decoded 64-byte encoding b, and returns pre. If b
+// is not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (pre *PreSignature) SetBytes(b []byte) (*PreSignature, error) {
+	if len(b) != PreSignatureSize {
+		return nil, errors.New("adaptor: invalid pre-signature length")
+	}
+	R, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[:32])
+	if err != nil {
+		return nil, errors.New("adaptor: invalid pre-signature encoding")
+	}
+	s, err := ristretto255.NewScalar().SetCanonicalBytes(b[32:])
+	if err != nil {
+		return nil, errors.New("adaptor: invalid pre-signature encoding")
+	}
+	pre.R, pre.S = R, s
+	return pre, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package adaptor
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/schnorrsig"
+)
+
+func keyPair() (*ristretto255.Scalar, *ristretto255.Element) {
+	x := scalar.MustRandom()
+	return x, ristretto255.NewIdentityElement().ScalarBaseMult(x)
+}
+
+func TestAtomicSwap(t *testing.T) {
+	// Alice knows the secret t; Bob only knows T.
+	secret, T := keyPair()
+	xA, PA := keyPair()
+	xB, PB := keyPair()
+	msgA := []byte("chain A: Alice pays Bob")
+	msgB := []byte("chain B: Bob pays Alice")
+
+	// Both sides pre-sign their payment with the same adaptor point.
+	preA, err := PreSign(xA, msgA, T)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preB, err := PreSign(xB, msgB, T)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := preA.Verify(PA, msgA, T); err != nil {
+		t.Fatal(err)
+	}
+	if err := preB.Verify(PB, msgB, T); err != nil {
+		t.Fatal(err)
+	}
+
+	// A pre-signature is not a valid signature.
+	if err := schnorrsig.Verify(PB, msgB, &schnorrsig.Signature{R: preB.R, S: preB.S}); err == nil {
+		t.Fatal("pre-signature verified as a signature")
+	}
+
+	// Alice claims Bob's payment, revealing the adapted signature.
+	sigB := preB.Adapt(secret)
+	if err := schnorrsig.Verify(PB, msgB, sigB); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bob extracts t and claims Alice's payment.
+	extracted, err := preB.Extract(sigB, T)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extracted.Equal(secret) != 1 {
+		t.Fatal("extracted the wrong secret")
+	}
+	if err := schnorrsig.Verify(PA, msgA, preA.Adapt(extracted)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	_, T := keyPair()
+	_, U := keyPair()
+	x, P := keyPair()
+	_, Q := keyPair()
+	msg := []byte("msg")
+	pre, err := PreSign(x, msg, T)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pre.Verify(P, msg, U); err == nil {
+		t.Error("pre-signature verified for another adaptor point")
+	}
+	if err := pre.Verify(Q, msg, T); err == nil {
+		t.Error("pre-signature verified for another key")
+	}
+	if err := pre.Verify(P, []byte("other"), T); err == nil {
+		t.Error("pre-signature verified for another message")
+	}
+	if err := (*PreSignature)(nil).Verify(P, msg, T); err == nil {
+		t.Error("nil pre-signature verified")
+	}
+	if err := (&PreSignature{R: pre.R}).Verify(P, msg, T); err == nil {
+		t.Error("pre-signature without a response verified")
+	}
+	if _, err := pre.Extract(&schnorrsig.Signature{}, T); err == nil {
+		t.Error("extracted a secret from a zero signature")
+	}
+	if _, err := PreSign(x, msg, ristretto255.NewIdentityElement()); err == nil {
+		t.Error("pre-signed with the identity adaptor point")
+	}
+
+	// Adapting with the wrong secret gives an invalid signature, from which
+	// no secret can be extracted.
+	sig := pre.Adapt(scalar.MustRandom())
+	if err := schnorrsig.Verify(P, msg, sig); err == nil {
+		t.Error("signature adapted with the wrong secret verified")
+	}
+	if _, err := pre.Extract(sig, T); err == nil {
+		t.Error("extracted a secret from a wrongly adapted signature")
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	_, T := keyPair()
+	x, P := keyPair()
+	pre, _ := PreSign(x, []byte("msg"), T)
+	decoded, err := new(PreSignature).SetBytes(pre.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.Verify(P, []byte("msg"), T); err != nil {
+		t.Error(err)
+	}
+	b := pre.Bytes()
+	for i := range b[32:] {
+		b[32+i] = 0xff
+	}
+	if _, err := new(PreSignature).SetBytes(b); err == nil {
+		t.Error("accepted a non-canonical scalar")
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package blindschnorr implements the Clause Blind Schnorr signatures of
+// Fuchsbauer and Wolf, "Concurrently Secure Blind Schnorr Signatures"
+// (EUROCRYPT 2024), over ristretto255.
+//
+// Plain blind Schnorr signatures are forgeable when the signer runs many
+// sessions concurrently, by solving the ROS problem. In Clause Blind Schnorr,
+// the signer commits to two nonces per session, the user blinds a challenge
+// for each, and the signer answers only one of them, chosen at random. This
+// makes the ROS attack infeasible, while the resulting signatures remain
+// ordinary schnorrsig.Signatures, verified with schnorrsig.Verify.
+//
+// A session runs in three moves:
+//
+//	signer: session, commitment := signer.NewSession()         → commitment
+//	user:   user, challenge := Blind(X, msg, commitment)        ← challenge
+//	signer: response := session.Respond(challenge)              → response
+//	user:   sig := user.Unblind(response)
+package blindschnorr
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync/atomic"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/schnorrsig"
+)
+
+const (
+	// CommitmentSize is the size in bytes of an encoded Commitment.
+	CommitmentSize = 64
+	// ChallengeSize is the size in bytes of an encoded Challenge.
+	ChallengeSize = 64
+	// ResponseSize is the size in bytes of an encoded Response.
+	ResponseSize = 33
+)
+
+var errFormat = errors.New("blindschnorr: malformed encoding")
+
+// Signer holds the signing key. It is safe for concurrent use, and can run
+// any number of concurrent sessions.
+type Signer struct {
+	x *ristretto255.Scalar
+	X *ristretto255.Element
+}
+
+// NewSigner returns a Signer for the secret key x, which must not be zero.
+func NewSigner(x *ristretto255.Scalar) (*Signer, error) {
+	if scalar.IsZero(x) {
+		return nil, errors.New("blindschnorr: zero secret key")
+	}
+	return &Signer{
+		x: ristretto255.NewScalar().Set(x),
+		X: ristretto255.NewIdentityElement().ScalarBaseMult(x),
+	}, nil
+}
+
+// Commitment is the signer's first message, the nonce commitments
+// R_0 = r_0 * G and R_1 = r_1 * G.
+type Commitment struct {
+	R [2]*ristretto255.Element
+}
+
+// Challenge is the user's blinded challenges c_0 and c_1, one per nonce.
+type Challenge struct {
+	C [2]*ristretto255.Scalar
+}
+
+// Response is the signer's answer s = r_b + c_b * x to the challenge c_b
+// for the randomly chosen bit b.
+type Response struct {
+	B int
+	S *ristretto255.Scalar
+}
+
+// SignerSession is the signer's state for a single session.
+type SignerSession struct {
+	signer *Signer
+	r      [2]*ristretto255.Scalar
+	used   atomic.Bool
+}
+
+// NewSession starts a signing session, and returns its Commitment for the
+// user.
+func (s *Signer) NewSession() (*SignerSession, *Commitment, error) {
+	ss := &SignerSession{signer: s}
+	cm := &Commitment{}
+	for i := range ss.r {
+		var err error
+		if ss.r[i], err = scalar.Random(nil); err != nil {
+			return nil, nil, err
+		}
+		cm.R[i] = ristretto255.NewIdentityElement().ScalarBaseMult(ss.r[i])
+	}
+	return ss, cm, nil
+}
+
+// Respond answers one of the user's challenges, chosen at random. A session
+// can be responded to only once, even if Respond is called concurrently;
+// later calls return an error.
+func (ss *SignerSession) Respond(ch *Challenge) (*Response, error) {
+	if ss.used.Swap(true) {
+		return nil, errors.New("blindschnorr: session already completed")
+	}
+	r := ss.r
+	ss.r = [2]*ristretto255.Scalar{}
+
+	var bit [1]byte
+	if _, err := rand.Read(bit[:]); err != nil {
+		return nil, err
+	}
+	b := int(bit[0] & 1)
+	s := ristretto255.NewScalar().Multiply(ch.C[b], ss.signer.x)
+	return &Response{B: b, S: s.Add(s, r[b])}, nil
+}
+
+// UserSession is the user's state for a single session.
+type UserSession struct {
+	X          *ristretto255.Element
+	nonces     [2]*ristretto255.Element // R_i, from the signer
+	c          [2]*ristretto255.Scalar  // the blinded challenges
+	alpha      [2]*ristretto255.Scalar
+	blindNonce [2]*ristretto255.Element // R'_i = R_i + alpha_i * G + beta_i * X
+}
+
+// Blind returns the user's state and blinded challenges for a signature on
+// msg by the public key X, given the signer's Commitment.
+//
+// For each nonce, the user picks alpha_i and beta_i, and computes
+// R'_i = R_i + alpha_i * G + beta_i * X and
+// c_i = schnorrsig.Challenge(R'_i, X, msg) + beta_i.
+func Blind(X *ristretto255.Element, msg []byte, cm *Commitment) (*UserSession, *Challenge, error) {
+	if X.Equal(ristretto255.NewIdentityElement()) == 1 {
+		return nil, nil, errors.New("blindschnorr: invalid public key")
+	}
+	us := &UserSession{X: X, nonces: cm.R}
+	ch := &Challenge{}
+	for i := range cm.R {
+		alpha, err := scalar.Random(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		beta, err := scalar.Random(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		Ri := ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(beta, X, alpha)
+		Ri.Add(Ri, cm.R[i])
+		c := schnorrsig.Challenge(Ri, X, msg)
+		ch.C[i] = c.Add(c, beta)
+		us.alpha[i], us.blindNonce[i], us.c[i] = alpha, Ri, ch.C[i]
+	}
+	return us, ch, nil
+}
+
+// Unblind checks the signer's Response and returns the unblinded signature
+// (R'_b, s + alpha_b).
+func (us *UserSession) Unblind(resp *Response) (*schnorrsig.Signature, error) {
+	if resp.B != 0 && resp.B != 1 {
+		return nil, errors.New("blindschnorr: invalid response bit")
+	}
+	b := resp.B
+	// Check that s * G = R_b + c_b * X.
+	negC := ristretto255.NewScalar().Negate(us.c[b])
+	check := ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(negC, us.X, resp.S)
+	if check.Equal(us.nonces[b]) != 1 {
+		return nil, errors.New("blindschnorr: invalid signer response")
+	}
+	return &schnorrsig.Signature{
+		R: ristretto255.NewIdentityElement().Set(us.blindNonce[b]),
+		S: ristretto255.NewScalar().Add(resp.S, us.alpha[b]),
+	}, nil
+}
+
+// Bytes returns the 64-byte encoding of the commitment, R_0 || R_1.
+func (cm *Commitment) Bytes() []byte {
+	return append(cm.R[0].Bytes(), cm.R[1].Bytes()...)
+}
+
+// SetBytes sets cm to the decoded 64-byte encoding b, and returns cm. If b is
+// not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (cm *Commitment) SetBytes(b []byte) (*Commitment, error) {
+	if len(b) != CommitmentSize {
+		return nil, errFormat
+	}
+	R0, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[:32])
+	if err != nil {
+		return nil, errFormat
+	}
+	R1, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[32:])
+	if err != nil {
+		return nil, errFormat
+	}
+	cm.R = [2]*ristretto255.Element{R0, R1}
+	return cm, nil
+}
+
+// Bytes returns the 64-byte encoding of the challenge, c_0 || c_1.
+func (ch *Challenge) Bytes() []byte {
+	return append(ch.C[0].Bytes(), ch.C[1].Bytes()...)
+}
+
+// SetBytes sets ch to the decoded 64-byte encoding b, and returns ch. If b is
+// not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (ch *Challenge) SetBytes(b []byte) (*Challenge, error) {
+	if len(b) != ChallengeSize {
+		return nil, errFormat
+	}
+	c0, err := ristretto255.NewScalar().SetCanonicalBytes(b[:32])
+	if err != nil {
+		return nil, errFormat
+	}
+	c1, err := ristretto255.NewScalar().SetCanonicalBytes(b[32:])
+	if err != nil {
+		return nil, errFormat
+	}
+	ch.C = [2]*ristretto255.Scalar{c0, c1}
+	return ch, nil
+}
+
+// Bytes returns the 33-byte encoding of the response, b || s.
+func (resp *Response) Bytes() []byte {
+	return append([]byte{byte(resp.B)}, resp.S.Bytes()...)
+}
+
+// SetBytes sets resp to the decoded 33-byte encoding b, and returns resp. If
+// b is not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (resp *Response) SetBytes(b []byte) (*Response, error) {
+	if len(b) != ResponseSize || b[0] > 1 {
+		return nil, errFormat
+	}
+	s, err := ristretto255.NewScalar().SetCanonicalBytes(b[1:])
+	if err != nil {
+		return nil, errFormat
+	}
+	resp.B, resp.S = int(b[0]), s
+	return resp, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package blindschnorr
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/schnorrsig"
+)
+
+func runSession(t *testing.T, signer *Signer, msg []byte) (*schnorrsig.Signature, *Commitment) {
+	session, cm, err := signer.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, ch, err := Blind(signer.X, msg, cm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := session.Respond(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := user.Unblind(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig, cm
+}
+
+func TestBlindSignature(t *testing.T) {
+	signer, err := NewSigner(scalar.MustRandom())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("token serial 1234")
+	for i := 0; i < 20; i++ {
+		sig, cm := runSession(t, signer, msg)
+		if err := schnorrsig.Verify(signer.X, msg, sig); err != nil {
+			t.Fatal(err)
+		}
+		if err := schnorrsig.Verify(signer.X, []byte("other"), sig); err == nil {
+			t.Fatal("signature verified for another message")
+		}
+		// The signer's view doesn't contain the signature's nonce.
+		if sig.R.Equal(cm.R[0]) == 1 || sig.R.Equal(cm.R[1]) == 1 {
+			t.Fatal("signature nonce is not blinded")
+		}
+	}
+}
+
+func TestConcurrentSessions(t *testing.T) {
+	signer, _ := NewSigner(scalar.MustRandom())
+	const n = 32
+	type state struct {
+		session *SignerSession
+		user    *UserSession
+		ch      *Challenge
+	}
+	states := make([]state, n)
+	for i := range states {
+		session, cm, err := signer.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		user, ch, err := Blind(signer.X, []byte(fmt.Sprint(i)), cm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		states[i] = state{session, user, ch}
+	}
+
+	// Complete all the open sessions concurrently, each twice.
+	var wg sync.WaitGroup
+	resps := make([][2]*Response, n)
+	for i := range states {
+		for j := 0; j < 2; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resps[i][j], _ = states[i].session.Respond(states[i].ch)
+			}()
+		}
+	}
+	wg.Wait()
+
+	bits := [2]int{}
+	for i := range states {
+		resp := resps[i][0]
+		if (resps[i][0] == nil) == (resps[i][1] == nil) {
+			t.Fatalf("session %d was not completed exactly once", i)
+		}
+		if resp == nil {
+			resp = resps[i][1]
+		}
+		bits[resp.B]++
+		sig, err := states[i].user.Unblind(resp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := schnorrsig.Verify(signer.X, []byte(fmt.Sprint(i)), sig); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if bits[0] == 0 || bits[1] == 0 {
+		t.Errorf("signer always answered the same clause: %v", bits)
+	}
+}
+
+func TestUnblindRejectsBadResponse(t *testing.T) {
+	signer, _ := NewSigner(scalar.MustRandom())
+	session, cm, _ := signer.NewSession()
+	user, ch, _ := Blind(signer.X, []byte("msg"), cm)
+	resp, err := session.Respond(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bad := range []*Response{
+		{B: 1 - resp.B, S: resp.S},
+		{B: resp.B, S: ristretto255.NewScalar().Add(resp.S, scalar.One())},
+		{B: 2, S: resp.S},
+	} {
+		if _, err := user.Unblind(bad); err == nil {
+			t.Errorf("Unblind accepted an invalid response with bit %d", bad.B)
+		}
+	}
+
+	if _, err := NewSigner(ristretto255.NewScalar()); err == nil {
+		t.Error("NewSigner accepted a zero key")
+	}
+	if _, _, err := Blind(ristretto255.NewIdentityElement(), []byte("msg"), cm); err == nil {
+		t.Error("Blind accepted the identity public key")
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	signer, _ := NewSigner(scalar.MustRandom())
+	session, cm, _ := signer.NewSession()
+
+	cm2, err := new(Commitment).SetBytes(cm.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, ch, _ := Blind(signer.X, []byte("msg"), cm2)
+	ch2, err := new(Challenge).SetBytes(ch.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, _ := session.Respond(ch2)
+	resp2, err := new(Response).SetBytes(resp.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := user.Unblind(resp2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schnorrsig.Verify(signer.X, []byte("msg"), sig); err != nil {
+		t.Fatal(err)
+	}
+
+	b := resp.Bytes()
+	b[0] = 2
+	if _, err := new(Response).SetBytes(b); err == nil {
+		t.Error("accepted an invalid response bit")
+	}
+	if _, err := new(Challenge).SetBytes(ch.Bytes()[:63]); err == nil {
+		t.Error("accepted a short challenge")
+	}
+}
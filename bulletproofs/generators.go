@@ -0,0 +1,80 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bulletproofs provides the generators shared by the Bulletproofs
+// proof systems over ristretto255.
+//
+// The proof systems themselves live in subpackages, following the structure
+// of the dalek-cryptography bulletproofs library.
+package bulletproofs
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+
+	"github.com/gtank/ristretto255"
+)
+
+// PedersenGens is a pair of generators used for Pedersen commitments
+// to a value and a blinding factor.
+type PedersenGens struct {
+	// B is the generator for the committed value.
+	B *ristretto255.Element
+	// BBlinding is the generator for the blinding factor.
+	BBlinding *ristretto255.Element
+}
+
+// DefaultPedersenGens returns the default Pedersen generators: B is the
+// ristretto255 canonical generator, and BBlinding is derived from the
+// encoding of B with hash-to-group, so its discrete log is unknown.
+func DefaultPedersenGens() *PedersenGens {
+	B := ristretto255.NewGeneratorElement()
+	h := sha512.Sum512(B.Bytes())
+	BBlinding, _ := ristretto255.NewIdentityElement().SetUniformBytes(h[:])
+	return &PedersenGens{B: B, BBlinding: BBlinding}
+}
+
+// Commit returns value * B + blinding * BBlinding.
+func (pc *PedersenGens) Commit(value, blinding *ristretto255.Scalar) *ristretto255.Element {
+	return ristretto255.NewIdentityElement().MultiScalarMult(
+		[]*ristretto255.Scalar{value, blinding},
+		[]*ristretto255.Element{pc.B, pc.BBlinding})
+}
+
+// BulletproofGens contains the vectors of generators used by the inner
+// product argument. The discrete log relations between all of them are
+// unknown.
+type BulletproofGens struct {
+	// G and H have length Capacity.
+	G, H []*ristretto255.Element
+}
+
+// NewBulletproofGens returns capacity generators in each of the G and H
+// vectors. Generators are derived deterministically, so any two
+// BulletproofGens agree on their common prefix.
+func NewBulletproofGens(capacity int) *BulletproofGens {
+	return &BulletproofGens{
+		G: generatorChain("G", capacity),
+		H: generatorChain("H", capacity),
+	}
+}
+
+// Capacity returns the number of generators in each of the G and H vectors.
+func (gens *BulletproofGens) Capacity() int {
+	return len(gens.G)
+}
+
+func generatorChain(label string, n int) []*ristretto255.Element {
+	out := make([]*ristretto255.Element, n)
+	var idx [4]byte
+	for i := range out {
+		binary.LittleEndian.PutUint32(idx[:], uint32(i))
+		h := sha512.New()
+		h.Write([]byte("ristretto255 bulletproofs generators "))
+		h.Write([]byte(label))
+		h.Write(idx[:])
+		out[i], _ = ristretto255.NewIdentityElement().SetUniformBytes(h.Sum(nil))
+	}
+	return out
+}
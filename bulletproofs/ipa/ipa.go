@@ -0,0 +1,389 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ipa implements the Bulletproofs inner product argument over
+// ristretto255 with caller-provided generators.
+//
+// A Proof shows knowledge of vectors a and b of length n such that
+//
+//	P = <a, G'> + <b, H'> + <a, b> * Q
+//
+// where G' and H' are the generators G and H multiplied element-wise by
+// optional public factors. Proofs have 2*lg(n) Elements and two Scalars, and
+// verification is a single VarTimeMultiScalarMult of size 2*n + 2*lg(n) + 2.
+//
+// Prove and Verify absorb P and Q into the transcript before the first
+// challenge, so a proof is bound to the commitment it was made for.
+//
+// ProveUnbound and VerificationScalars don't, and are only for protocols that
+// embed the argument in a larger proof. Those protocols must have bound the
+// transcript to P, Q, and the generators, for example by absorbing the
+// commitments P is computed from, before calling them. Otherwise, a prover
+// can pick P after seeing the challenges, and forge a proof for any P.
+package ipa
+
+import (
+	"errors"
+	"math/bits"
+	"slices"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+var (
+	errVerification = errors.New("ipa: proof verification failed")
+	errFormat       = errors.New("ipa: malformed proof encoding")
+)
+
+// Proof is an inner product argument.
+type Proof struct {
+	// L and R are the cross-term commitments of each folding round.
+	L, R []*ristretto255.Element
+	// A and B are the folded vectors, of length one.
+	A, B *ristretto255.Scalar
+}
+
+// Prove returns a proof for the vectors a and b against the generators G, H
+// and Q, absorbing the commitment P, Q, and the proof into t. gFactors and
+// hFactors are multiplied element-wise into G and H, and can be nil if they
+// are all ones.
+//
+// All vectors must have the same length n, which must be a power of two.
+func Prove(t *transcript.Transcript, Q *ristretto255.Element,
+	gFactors, hFactors []*ristretto255.Scalar,
+	G, H []*ristretto255.Element, a, b []*ristretto255.Scalar) (*Proof, error) {
+	return prove(t, true, Q, gFactors, hFactors, G, H, a, b)
+}
+
+// ProveUnbound is like Prove, but doesn't absorb P and Q into t. The caller
+// must have already bound t to them, as described in the package
+// documentation. The proof must be checked with VerificationScalars.
+func ProveUnbound(t *transcript.Transcript, Q *ristretto255.Element,
+	gFactors, hFactors []*ristretto255.Scalar,
+	G, H []*ristretto255.Element, a, b []*ristretto255.Scalar) (*Proof, error) {
+	return prove(t, false, Q, gFactors, hFactors, G, H, a, b)
+}
+
+func prove(t *transcript.Transcript, bind bool, Q *ristretto255.Element,
+	gFactors, hFactors []*ristretto255.Scalar,
+	G, H []*ristretto255.Element, a, b []*ristretto255.Scalar) (*Proof, error) {
+
+	n := len(G)
+	if len(H) != n || len(a) != n || len(b) != n ||
+		(gFactors != nil && len(gFactors) != n) || (hFactors != nil && len(hFactors) != n) {
+		return nil, errors.New("ipa: mismatched vector lengths")
+	}
+	if n == 0 || n&(n-1) != 0 {
+		return nil, errors.New("ipa: vector length is not a power of two")
+	}
+	if gFactors == nil {
+		gFactors = ones(n)
+	}
+	if hFactors == nil {
+		hFactors = ones(n)
+	}
+
+	domainSeparator(t, n)
+	if bind {
+		// P = <a o g, G> + <b o h, H> + <a, b> * Q
+		scalars := make([]*ristretto255.Scalar, 0, 2*n+1)
+		for i := range a {
+			scalars = append(scalars, ristretto255.NewScalar().Multiply(a[i], gFactors[i]))
+		}
+		for i := range b {
+			scalars = append(scalars, ristretto255.NewScalar().Multiply(b[i], hFactors[i]))
+		}
+		scalars = append(scalars, scalar.InnerProduct(a, b))
+		points := slices.Concat(G, H, []*ristretto255.Element{Q})
+		P := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(scalars, points)
+		appendStatement(t, P, Q)
+	}
+
+	G = append([]*ristretto255.Element(nil), G...)
+	H = append([]*ristretto255.Element(nil), H...)
+	a = cloneScalars(a)
+	b = cloneScalars(b)
+
+	proof := &Proof{}
+	first := true
+	for n != 1 {
+		n = n / 2
+		aL, aR := a[:n], a[n:]
+		bL, bR := b[:n], b[n:]
+		GL, GR := G[:n], G[n:]
+		HL, HR := H[:n], H[n:]
+
+		cL := scalar.InnerProduct(aL, bR)
+		cR := scalar.InnerProduct(aR, bL)
+
+		scalars := make([]*ristretto255.Scalar, 0, 2*n+1)
+		points := make([]*ristretto255.Element, 0, 2*n+1)
+		for i := 0; i < n; i++ {
+			s := ristretto255.NewScalar().Set(aL[i])
+			if first {
+				s.Multiply(s, gFactors[n+i])
+			}
+			scalars = append(scalars, s)
+			points = append(points, GR[i])
+		}
+		for i := 0; i < n; i++ {
+			s := ristretto255.NewScalar().Set(bR[i])
+			if first {
+				s.Multiply(s, hFactors[i])
+			}
+			scalars = append(scalars, s)
+			points = append(points, HL[i])
+		}
+		scalars = append(scalars, cL)
+		points = append(points, Q)
+		L := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(scalars, points)
+
+		scalars = scalars[:0]
+		points = points[:0]
+		for i := 0; i < n; i++ {
+			s := ristretto255.NewScalar().Set(aR[i])
+			if first {
+				s.Multiply(s, gFactors[i])
+			}
+			scalars = append(scalars, s)
+			points = append(points, GL[i])
+		}
+		for i := 0; i < n; i++ {
+			s := ristretto255.NewScalar().Set(bL[i])
+			if first {
+				s.Multiply(s, hFactors[n+i])
+			}
+			scalars = append(scalars, s)
+			points = append(points, HR[i])
+		}
+		scalars = append(scalars, cR)
+		points = append(points, Q)
+		R := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(scalars, points)
+
+		proof.L = append(proof.L, L)
+		proof.R = append(proof.R, R)
+		t.AppendElement("L", L)
+		t.AppendElement("R", R)
+
+		u := t.ChallengeScalar("u")
+		uInv := ristretto255.NewScalar().Invert(u)
+
+		for i := 0; i < n; i++ {
+			tmp := ristretto255.NewScalar()
+			aL[i].Add(aL[i].Multiply(aL[i], u), tmp.Multiply(uInv, aR[i]))
+			bL[i].Add(bL[i].Multiply(bL[i], uInv), tmp.Multiply(u, bR[i]))
+
+			gl, gr := ristretto255.NewScalar().Set(uInv), ristretto255.NewScalar().Set(u)
+			hl, hr := ristretto255.NewScalar().Set(u), ristretto255.NewScalar().Set(uInv)
+			if first {
+				gl.Multiply(gl, gFactors[i])
+				gr.Multiply(gr, gFactors[n+i])
+				hl.Multiply(hl, hFactors[i])
+				hr.Multiply(hr, hFactors[n+i])
+			}
+			GL[i] = ristretto255.NewIdentityElement().VarTimeMultiScalarMult(
+				[]*ristretto255.Scalar{gl, gr}, []*ristretto255.Element{GL[i], GR[i]})
+			HL[i] = ristretto255.NewIdentityElement().VarTimeMultiScalarMult(
+				[]*ristretto255.Scalar{hl, hr}, []*ristretto255.Element{HL[i], HR[i]})
+		}
+
+		a, b, G, H = aL, bL, GL, HL
+		first = false
+	}
+
+	proof.A, proof.B = a[0], b[0]
+	return proof, nil
+}
+
+func domainSeparator(t *transcript.Transcript, n int) {
+	t.AppendMessage("dom-sep", []byte("ipp v1"))
+	t.AppendUint64("n", uint64(n))
+}
+
+func appendStatement(t *transcript.Transcript, P, Q *ristretto255.Element) {
+	t.AppendElement("P", P)
+	t.AppendElement("Q", Q)
+}
+
+// VerificationScalars replays the transcript of a proof made by ProveUnbound
+// for vectors of length n, and returns the squares of the challenges, the
+// squares of their inverses, and the vector s such that the fully folded
+// generators are <s, G> and <s', H>, where s' is s reversed.
+//
+// It is exposed so that protocols embedding the argument can merge its
+// verification into a larger multi-scalar multiplication. Like ProveUnbound,
+// it doesn't absorb P and Q into t: the caller must have already bound t to
+// them, as described in the package documentation.
+func (proof *Proof) VerificationScalars(n int, t *transcript.Transcript) (uSq, uInvSq, s []*ristretto255.Scalar, err error) {
+	return proof.verificationScalars(n, t, nil, nil)
+}
+
+// verificationScalars implements VerificationScalars, absorbing P and Q into
+// t after the domain separator if they are not nil.
+func (proof *Proof) verificationScalars(n int, t *transcript.Transcript, P, Q *ristretto255.Element) (uSq, uInvSq, s []*ristretto255.Scalar, err error) {
+	lgN := len(proof.L)
+	if lgN >= 32 || len(proof.R) != lgN || n != 1<<lgN {
+		return nil, nil, nil, errVerification
+	}
+	if slices.Contains(proof.L, nil) || slices.Contains(proof.R, nil) || proof.A == nil || proof.B == nil {
+		return nil, nil, nil, errVerification
+	}
+
+	domainSeparator(t, n)
+	if P != nil {
+		appendStatement(t, P, Q)
+	}
+
+	u := make([]*ristretto255.Scalar, lgN)
+	for i := range proof.L {
+		if err := t.ValidateAndAppendElement("L", proof.L[i]); err != nil {
+			return nil, nil, nil, errVerification
+		}
+		if err := t.ValidateAndAppendElement("R", proof.R[i]); err != nil {
+			return nil, nil, nil, errVerification
+		}
+		u[i] = t.ChallengeScalar("u")
+	}
+
+	allInv := scalar.One()
+	uSq = make([]*ristretto255.Scalar, lgN)
+	uInvSq = make([]*ristretto255.Scalar, lgN)
+	for i := range u {
+		uInv := ristretto255.NewScalar().Invert(u[i])
+		allInv.Multiply(allInv, uInv)
+		uSq[i] = ristretto255.NewScalar().Multiply(u[i], u[i])
+		uInvSq[i] = ristretto255.NewScalar().Multiply(uInv, uInv)
+	}
+
+	s = make([]*ristretto255.Scalar, n)
+	s[0] = allInv
+	for i := 1; i < n; i++ {
+		lgI := bits.Len(uint(i)) - 1
+		k := 1 << lgI
+		// The challenges are stored in "creation order" as [u_k, ..., u_1],
+		// so u_{lg(i)+1} is indexed by (lgN-1) - lgI.
+		s[i] = ristretto255.NewScalar().Multiply(s[i-k], uSq[(lgN-1)-lgI])
+	}
+	return uSq, uInvSq, s, nil
+}
+
+// Verify checks a proof made by Prove against the commitment P and the
+// generators G, H and Q, with the same factors used by the prover (or nil),
+// replaying the transcript t.
+func (proof *Proof) Verify(t *transcript.Transcript, P, Q *ristretto255.Element,
+	gFactors, hFactors []*ristretto255.Scalar, G, H []*ristretto255.Element) error {
+
+	n := len(G)
+	if len(H) != n || (gFactors != nil && len(gFactors) != n) || (hFactors != nil && len(hFactors) != n) {
+		return errors.New("ipa: mismatched vector lengths")
+	}
+	uSq, uInvSq, s, err := proof.verificationScalars(n, t, P, Q)
+	if err != nil {
+		return err
+	}
+
+	// Check that
+	//
+	//   <a * s o g, G> + <b / s o h, H> + a * b * Q
+	//     - P - sum(u_j^2 * L_j + u_j^-2 * R_j) = 0
+	scalars := make([]*ristretto255.Scalar, 0, 2*n+2*len(uSq)+2)
+	points := make([]*ristretto255.Element, 0, 2*n+2*len(uSq)+2)
+	for i := 0; i < n; i++ {
+		x := ristretto255.NewScalar().Multiply(proof.A, s[i])
+		if gFactors != nil {
+			x.Multiply(x, gFactors[i])
+		}
+		scalars = append(scalars, x)
+	}
+	points = append(points, G...)
+	for i := 0; i < n; i++ {
+		x := ristretto255.NewScalar().Multiply(proof.B, s[n-1-i])
+		if hFactors != nil {
+			x.Multiply(x, hFactors[i])
+		}
+		scalars = append(scalars, x)
+	}
+	points = append(points, H...)
+	for i := range uSq {
+		scalars = append(scalars, ristretto255.NewScalar().Negate(uSq[i]))
+		points = append(points, proof.L[i])
+	}
+	for i := range uInvSq {
+		scalars = append(scalars, ristretto255.NewScalar().Negate(uInvSq[i]))
+		points = append(points, proof.R[i])
+	}
+	scalars = append(scalars, ristretto255.NewScalar().Multiply(proof.A, proof.B),
+		ristretto255.NewScalar().Negate(scalar.One()))
+	points = append(points, Q, P)
+
+	check := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(scalars, points)
+	if check.Equal(ristretto255.NewIdentityElement()) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+// Bytes returns the encoding of the proof, as the L and R Elements of each
+// round interleaved, followed by A and B, for a total of (2*lg(n) + 2) * 32
+// bytes.
+func (proof *Proof) Bytes() []byte {
+	out := make([]byte, 0, (2*len(proof.L)+2)*32)
+	for i := range proof.L {
+		out = append(out, proof.L[i].Bytes()...)
+		out = append(out, proof.R[i].Bytes()...)
+	}
+	out = append(out, proof.A.Bytes()...)
+	out = append(out, proof.B.Bytes()...)
+	return out
+}
+
+// SetBytes sets proof to the decoded encoding b, and returns proof. If b is
+// not a canonical proof encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (proof *Proof) SetBytes(b []byte) (*Proof, error) {
+	if len(b)%64 != 0 || len(b) < 64 || len(b)/64-1 >= 32 {
+		return nil, errFormat
+	}
+	p := &Proof{}
+	for len(b) > 64 {
+		L, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[:32])
+		if err != nil {
+			return nil, errFormat
+		}
+		R, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[32:64])
+		if err != nil {
+			return nil, errFormat
+		}
+		p.L = append(p.L, L)
+		p.R = append(p.R, R)
+		b = b[64:]
+	}
+	var err error
+	if p.A, err = ristretto255.NewScalar().SetCanonicalBytes(b[:32]); err != nil {
+		return nil, errFormat
+	}
+	if p.B, err = ristretto255.NewScalar().SetCanonicalBytes(b[32:]); err != nil {
+		return nil, errFormat
+	}
+	*proof = *p
+	return proof, nil
+}
+
+func ones(n int) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, n)
+	for i := range out {
+		out[i] = scalar.One()
+	}
+	return out
+}
+
+func cloneScalars(v []*ristretto255.Scalar) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, len(v))
+	for i := range v {
+		out[i] = ristretto255.NewScalar().Set(v[i])
+	}
+	return out
+}
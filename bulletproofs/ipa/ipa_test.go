@@ -0,0 +1,182 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ipa
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/bulletproofs"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+func randomScalars(n int) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, n)
+	for i := range out {
+		out[i] = scalar.MustRandom()
+	}
+	return out
+}
+
+// commit returns <a o g, G> + <b o h, H> + <a, b> Q.
+func commit(Q *ristretto255.Element, gFactors, hFactors []*ristretto255.Scalar,
+	G, H []*ristretto255.Element, a, b []*ristretto255.Scalar) *ristretto255.Element {
+	var scalars []*ristretto255.Scalar
+	var points []*ristretto255.Element
+	for i := range a {
+		x := ristretto255.NewScalar().Set(a[i])
+		if gFactors != nil {
+			x.Multiply(x, gFactors[i])
+		}
+		scalars = append(scalars, x)
+	}
+	for i := range b {
+		x := ristretto255.NewScalar().Set(b[i])
+		if hFactors != nil {
+			x.Multiply(x, hFactors[i])
+		}
+		scalars = append(scalars, x)
+	}
+	scalars = append(scalars, scalar.InnerProduct(a, b))
+	points = append(append(append(points, G...), H...), Q)
+	return ristretto255.NewIdentityElement().MultiScalarMult(scalars, points)
+}
+
+func TestInnerProductProof(t *testing.T) {
+	gens := bulletproofs.NewBulletproofGens(64)
+	Q := bulletproofs.DefaultPedersenGens().BBlinding
+
+	for _, factors := range []bool{false, true} {
+		for n := 1; n <= 64; n *= 2 {
+			G, H := gens.G[:n], gens.H[:n]
+			a, b := randomScalars(n), randomScalars(n)
+			var gFactors, hFactors []*ristretto255.Scalar
+			if factors {
+				gFactors = randomScalars(n)
+				hFactors = scalar.Powers(scalar.MustRandom(), n)
+			}
+			P := commit(Q, gFactors, hFactors, G, H, a, b)
+
+			proof, err := Prove(transcript.New("IPATest"), Q, gFactors, hFactors, G, H, a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(proof.L) != len(proof.R) || 1<<len(proof.L) != n {
+				t.Fatalf("n = %d: unexpected proof size %d", n, len(proof.L))
+			}
+			if err := proof.Verify(transcript.New("IPATest"), P, Q, gFactors, hFactors, G, H); err != nil {
+				t.Errorf("n = %d: valid proof failed to verify: %v", n, err)
+			}
+
+			decoded, err := new(Proof).SetBytes(proof.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := decoded.Verify(transcript.New("IPATest"), P, Q, gFactors, hFactors, G, H); err != nil {
+				t.Errorf("n = %d: decoded proof failed to verify: %v", n, err)
+			}
+
+			wrongP := ristretto255.NewIdentityElement().Add(P, Q)
+			if err := proof.Verify(transcript.New("IPATest"), wrongP, Q, gFactors, hFactors, G, H); err == nil {
+				t.Errorf("n = %d: proof verified against the wrong commitment", n)
+			}
+			if n > 1 {
+				// With n = 1 there are no challenges to bind the transcript.
+				if err := proof.Verify(transcript.New("OtherTest"), P, Q, gFactors, hFactors, G, H); err == nil {
+					t.Errorf("n = %d: proof verified with a different transcript", n)
+				}
+				if err := proof.Verify(transcript.New("IPATest"), P, Q, gFactors, hFactors, H, G); err == nil {
+					t.Errorf("n = %d: proof verified with swapped generators", n)
+				}
+			}
+		}
+	}
+}
+
+// TestCommitmentBinding checks that Verify rejects a proof for a commitment
+// chosen after the challenges, which would verify if P was not absorbed into
+// the transcript.
+func TestCommitmentBinding(t *testing.T) {
+	const n = 8
+	gens := bulletproofs.NewBulletproofGens(n)
+	G, H := gens.G, gens.H
+	Q := bulletproofs.DefaultPedersenGens().BBlinding
+
+	proof := &Proof{A: scalar.MustRandom(), B: scalar.MustRandom()}
+	for i := 0; i < 3; i++ {
+		L := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.MustRandom())
+		R := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.MustRandom())
+		proof.L = append(proof.L, L)
+		proof.R = append(proof.R, R)
+	}
+
+	// Solve the verification equation for P, with the unbound challenges.
+	uSq, uInvSq, s, err := proof.VerificationScalars(n, transcript.New("IPATest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scalars []*ristretto255.Scalar
+	var points []*ristretto255.Element
+	for i := 0; i < n; i++ {
+		scalars = append(scalars, ristretto255.NewScalar().Multiply(proof.A, s[i]),
+			ristretto255.NewScalar().Multiply(proof.B, s[n-1-i]))
+		points = append(points, G[i], H[i])
+	}
+	for i := range uSq {
+		scalars = append(scalars, ristretto255.NewScalar().Negate(uSq[i]),
+			ristretto255.NewScalar().Negate(uInvSq[i]))
+		points = append(points, proof.L[i], proof.R[i])
+	}
+	scalars = append(scalars, ristretto255.NewScalar().Multiply(proof.A, proof.B))
+	points = append(points, Q)
+	P := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(scalars, points)
+
+	if err := proof.Verify(transcript.New("IPATest"), P, Q, nil, nil, G, H); err == nil {
+		t.Error("proof verified for a commitment chosen after the challenges")
+	}
+}
+
+func TestIncompleteProof(t *testing.T) {
+	gens := bulletproofs.NewBulletproofGens(2)
+	Q := bulletproofs.DefaultPedersenGens().BBlinding
+	P := ristretto255.NewGeneratorElement()
+	if err := new(Proof).Verify(transcript.New("IPATest"), P, Q, nil, nil, gens.G[:1], gens.H[:1]); err == nil {
+		t.Error("zero proof verified")
+	}
+	proof := &Proof{L: []*ristretto255.Element{nil}, R: []*ristretto255.Element{nil},
+		A: scalar.MustRandom(), B: scalar.MustRandom()}
+	if err := proof.Verify(transcript.New("IPATest"), P, Q, nil, nil, gens.G, gens.H); err == nil {
+		t.Error("proof with missing elements verified")
+	}
+}
+
+func TestProveErrors(t *testing.T) {
+	gens := bulletproofs.NewBulletproofGens(4)
+	Q := bulletproofs.DefaultPedersenGens().BBlinding
+	if _, err := Prove(transcript.New("IPATest"), Q, nil, nil,
+		gens.G[:3], gens.H[:3], randomScalars(3), randomScalars(3)); err == nil {
+		t.Error("accepted a length that is not a power of two")
+	}
+	if _, err := Prove(transcript.New("IPATest"), Q, nil, nil,
+		gens.G, gens.H[:2], randomScalars(4), randomScalars(4)); err == nil {
+		t.Error("accepted mismatched lengths")
+	}
+}
+
+func TestProofEncodingErrors(t *testing.T) {
+	for _, l := range []int{0, 32, 96, 33 * 64} {
+		if _, err := new(Proof).SetBytes(make([]byte, l)); err == nil {
+			t.Errorf("accepted a proof of %d bytes", l)
+		}
+	}
+	b := make([]byte, 64)
+	for i := range b {
+		b[i] = 0xff
+	}
+	if _, err := new(Proof).SetBytes(b); err == nil {
+		t.Error("accepted non-canonical scalars")
+	}
+}
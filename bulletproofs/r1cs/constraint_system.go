@@ -0,0 +1,144 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package r1cs implements Bulletproofs proofs of satisfiability of rank-1
+// constraint systems over ristretto255 Scalars, modeled on the r1cs module of
+// the dalek-cryptography bulletproofs library.
+//
+// A gadget is written once against the ConstraintSystem interfaces, and run
+// both by a Prover, which knows the assignments of all variables, and by a
+// Verifier, which only knows the commitments to the high-level inputs.
+//
+// Constraints that depend on verifier challenges can be added in a second
+// phase with SpecifyRandomizedConstraints. The challenges are bound to
+// all the variables allocated in the first phase.
+package r1cs
+
+import (
+	"errors"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+// ConstraintSystem is the interface shared by the prover and verifier to
+// build a constraint system.
+type ConstraintSystem interface {
+	// Transcript returns the Fiat-Shamir transcript of the proof, so that
+	// gadgets can absorb public parameters.
+	Transcript() *transcript.Transcript
+
+	// Multiply allocates a multiplication gate, constrains its left and
+	// right inputs to equal left and right, and returns the Variables for
+	// the left input, right input and output of the gate.
+	Multiply(left, right LinearCombination) (l, r, o Variable)
+
+	// Allocate allocates a single new variable as the left input of a
+	// multiplication gate. The prover must provide the assignment, while the
+	// verifier must pass nil.
+	Allocate(assignment *ristretto255.Scalar) (Variable, error)
+
+	// AllocateMultiplier allocates a multiplication gate with the given
+	// left and right input assignments, and returns the Variables for the
+	// left input, right input and output of the gate. The prover must
+	// provide the assignments, while the verifier must pass nil.
+	AllocateMultiplier(left, right *ristretto255.Scalar) (l, r, o Variable, err error)
+
+	// Constrain adds the constraint lc = 0.
+	Constrain(lc LinearCombination)
+}
+
+// RandomizableConstraintSystem is a ConstraintSystem that can defer
+// constraints until after the first phase variables are committed to.
+type RandomizableConstraintSystem interface {
+	ConstraintSystem
+
+	// SpecifyRandomizedConstraints registers callback to be invoked after
+	// all first phase variables are committed to. The callback can obtain
+	// challenges bound to them with ChallengeScalar.
+	SpecifyRandomizedConstraints(callback func(RandomizedConstraintSystem) error) error
+}
+
+// RandomizedConstraintSystem is the ConstraintSystem passed to the callbacks
+// registered with SpecifyRandomizedConstraints.
+type RandomizedConstraintSystem interface {
+	ConstraintSystem
+
+	// ChallengeScalar returns a challenge bound to all the first phase
+	// variables and to any challenge previously returned.
+	ChallengeScalar(label string) *ristretto255.Scalar
+}
+
+var (
+	errMissingAssignment       = errors.New("r1cs: prover is missing a variable assignment")
+	errUnexpectedAssignment    = errors.New("r1cs: verifier was given a variable assignment")
+	errInvalidGeneratorsLength = errors.New("r1cs: not enough Bulletproofs generators")
+	errVerification            = errors.New("r1cs: proof verification failed")
+	errFormat                  = errors.New("r1cs: malformed proof encoding")
+)
+
+// randomizedConstraints holds the deferred second phase callbacks.
+type randomizedConstraints struct {
+	callbacks []func(RandomizedConstraintSystem) error
+	// phase2 is set once the callbacks are being run.
+	phase2 bool
+}
+
+func (rc *randomizedConstraints) specify(callback func(RandomizedConstraintSystem) error) error {
+	if rc.phase2 {
+		return errors.New("r1cs: randomized constraints cannot be specified in the second phase")
+	}
+	rc.callbacks = append(rc.callbacks, callback)
+	return nil
+}
+
+func (rc *randomizedConstraints) run(cs RandomizedConstraintSystem) error {
+	rc.phase2 = true
+	callbacks := rc.callbacks
+	rc.callbacks = nil
+	for _, callback := range callbacks {
+		if err := callback(cs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenConstraints computes the weights of the constraints compressed
+// with powers of z, such that the constraints hold iff
+//
+//	<wL, aL> + <wR, aR> + <wO, aO> = <wV, v> + wc
+func flattenConstraints(constraints []LinearCombination, n, m int, z *ristretto255.Scalar) (wL, wR, wO, wV []*ristretto255.Scalar, wc *ristretto255.Scalar) {
+	newVec := func(l int) []*ristretto255.Scalar {
+		v := make([]*ristretto255.Scalar, l)
+		for i := range v {
+			v[i] = ristretto255.NewScalar()
+		}
+		return v
+	}
+	wL, wR, wO, wV = newVec(n), newVec(n), newVec(n), newVec(m)
+	wc = ristretto255.NewScalar()
+
+	expZ := ristretto255.NewScalar().Set(z)
+	t := ristretto255.NewScalar()
+	for _, lc := range constraints {
+		for _, tm := range lc.terms {
+			t.Multiply(expZ, tm.c)
+			switch tm.v.kind {
+			case kindMultiplierLeft:
+				wL[tm.v.index].Add(wL[tm.v.index], t)
+			case kindMultiplierRight:
+				wR[tm.v.index].Add(wR[tm.v.index], t)
+			case kindMultiplierOutput:
+				wO[tm.v.index].Add(wO[tm.v.index], t)
+			case kindCommitted:
+				wV[tm.v.index].Subtract(wV[tm.v.index], t)
+			case kindOne:
+				wc.Subtract(wc, t)
+			}
+		}
+		expZ.Multiply(expZ, z)
+	}
+	return
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r1cs
+
+import (
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+type variableKind int
+
+const (
+	kindOne variableKind = iota
+	kindCommitted
+	kindMultiplierLeft
+	kindMultiplierRight
+	kindMultiplierOutput
+)
+
+// A Variable represents a high-level variable in the constraint system: a
+// committed value, one of the wires of a multiplication gate, or the
+// constant One.
+type Variable struct {
+	kind  variableKind
+	index int
+}
+
+// One is the Variable holding the constant value 1.
+var One = Variable{kind: kindOne}
+
+// LC returns the linear combination 1 * v.
+func (v Variable) LC() LinearCombination {
+	return LinearCombination{terms: []term{{v, scalar.One()}}}
+}
+
+type term struct {
+	v Variable
+	c *ristretto255.Scalar
+}
+
+// A LinearCombination is a sum of Variables with Scalar coefficients. The
+// zero value is the empty linear combination, which evaluates to zero.
+//
+// LinearCombination values are immutable: all methods return new values.
+type LinearCombination struct {
+	terms []term
+}
+
+// Constant returns the linear combination c * One.
+func Constant(c *ristretto255.Scalar) LinearCombination {
+	return LinearCombination{terms: []term{{One, ristretto255.NewScalar().Set(c)}}}
+}
+
+// Term returns the linear combination c * v.
+func Term(v Variable, c *ristretto255.Scalar) LinearCombination {
+	return LinearCombination{terms: []term{{v, ristretto255.NewScalar().Set(c)}}}
+}
+
+// Add returns lc + other.
+func (lc LinearCombination) Add(other LinearCombination) LinearCombination {
+	terms := make([]term, 0, len(lc.terms)+len(other.terms))
+	terms = append(terms, lc.terms...)
+	terms = append(terms, other.terms...)
+	return LinearCombination{terms: terms}
+}
+
+// Sub returns lc - other.
+func (lc LinearCombination) Sub(other LinearCombination) LinearCombination {
+	return lc.Add(other.Neg())
+}
+
+// Neg returns -lc.
+func (lc LinearCombination) Neg() LinearCombination {
+	terms := make([]term, len(lc.terms))
+	for i, t := range lc.terms {
+		terms[i] = term{t.v, ristretto255.NewScalar().Negate(t.c)}
+	}
+	return LinearCombination{terms: terms}
+}
+
+// Scale returns s * lc.
+func (lc LinearCombination) Scale(s *ristretto255.Scalar) LinearCombination {
+	terms := make([]term, len(lc.terms))
+	for i, t := range lc.terms {
+		terms[i] = term{t.v, ristretto255.NewScalar().Multiply(t.c, s)}
+	}
+	return LinearCombination{terms: terms}
+}
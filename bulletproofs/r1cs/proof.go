@@ -0,0 +1,95 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r1cs
+
+import (
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/bulletproofs/ipa"
+)
+
+// Proof is a proof that a constraint system is satisfied.
+type Proof struct {
+	// Commitments to the first and second phase low-level variables and
+	// their blinding vectors.
+	AI1, AO1, S1 *ristretto255.Element
+	AI2, AO2, S2 *ristretto255.Element
+	// Commitments to the coefficients of t(x), except t_2.
+	T1, T3, T4, T5, T6 *ristretto255.Element
+	// Evaluation of t(x) at the challenge point x and its blinding.
+	TX, TXBlinding *ristretto255.Scalar
+	// Blinding factor for the synthetic commitment to l(x) and r(x).
+	EBlinding *ristretto255.Scalar
+
+	ipp *ipa.Proof
+}
+
+// complete reports whether all the fields of p are set, as they are for any
+// Proof returned by Prove or SetBytes.
+func (p *Proof) complete() bool {
+	for _, e := range []*ristretto255.Element{p.AI1, p.AO1, p.S1, p.AI2, p.AO2, p.S2,
+		p.T1, p.T3, p.T4, p.T5, p.T6} {
+		if e == nil {
+			return false
+		}
+	}
+	return p.TX != nil && p.TXBlinding != nil && p.EBlinding != nil && p.ipp != nil
+}
+
+// Bytes returns the encoding of the proof: 11 Elements, 3 Scalars, and the
+// inner product argument, for a total of (16 + 2*lg(n)) * 32 bytes, where n
+// is the number of multiplication gates rounded up to a power of two.
+//
+// p must be a Proof returned by Prove or SetBytes. Bytes panics if any of
+// its fields are unset.
+func (p *Proof) Bytes() []byte {
+	out := make([]byte, 0, (16+2*len(p.ipp.L))*32)
+	for _, e := range []*ristretto255.Element{p.AI1, p.AO1, p.S1, p.AI2, p.AO2, p.S2,
+		p.T1, p.T3, p.T4, p.T5, p.T6} {
+		out = append(out, e.Bytes()...)
+	}
+	out = append(out, p.TX.Bytes()...)
+	out = append(out, p.TXBlinding.Bytes()...)
+	out = append(out, p.EBlinding.Bytes()...)
+	return append(out, p.ipp.Bytes()...)
+}
+
+// SetBytes sets p to the decoded proof b, and returns p. If b is not a
+// canonical proof encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (p *Proof) SetBytes(b []byte) (*Proof, error) {
+	if len(b)%32 != 0 || len(b) < 16*32 || (len(b)/32)%2 != 0 {
+		return nil, errFormat
+	}
+	chunks := make([][]byte, len(b)/32)
+	for i := range chunks {
+		chunks[i] = b[i*32 : (i+1)*32]
+	}
+	elem := func(c []byte) (*ristretto255.Element, error) {
+		return ristretto255.NewIdentityElement().SetCanonicalBytes(c)
+	}
+	scal := func(c []byte) (*ristretto255.Scalar, error) {
+		return ristretto255.NewScalar().SetCanonicalBytes(c)
+	}
+
+	var err error
+	q := &Proof{}
+	for i, e := range []**ristretto255.Element{&q.AI1, &q.AO1, &q.S1, &q.AI2, &q.AO2, &q.S2,
+		&q.T1, &q.T3, &q.T4, &q.T5, &q.T6} {
+		if *e, err = elem(chunks[i]); err != nil {
+			return nil, errFormat
+		}
+	}
+	for i, s := range []**ristretto255.Scalar{&q.TX, &q.TXBlinding, &q.EBlinding} {
+		if *s, err = scal(chunks[11+i]); err != nil {
+			return nil, errFormat
+		}
+	}
+	if q.ipp, err = new(ipa.Proof).SetBytes(b[14*32:]); err != nil {
+		return nil, errFormat
+	}
+
+	*p = *q
+	return p, nil
+}
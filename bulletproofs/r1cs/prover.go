@@ -0,0 +1,365 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r1cs
+
+import (
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/bulletproofs"
+	"github.com/gtank/ristretto255/bulletproofs/ipa"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+// Prover builds a constraint system with full knowledge of the variable
+// assignments, and produces a Proof of its satisfiability.
+type Prover struct {
+	t  *transcript.Transcript
+	pc *bulletproofs.PedersenGens
+
+	constraints []LinearCombination
+
+	// Secret assignments of the low-level variables.
+	aL, aR, aO []*ristretto255.Scalar
+	// High-level witness values and their commitment blinding factors.
+	v, vBlinding []*ristretto255.Scalar
+
+	// pendingMultiplier is the index of a multiplier that has its left
+	// input allocated with Allocate, but not its right input.
+	pendingMultiplier int
+	hasPending        bool
+
+	deferred randomizedConstraints
+}
+
+var _ RandomizableConstraintSystem = &Prover{}
+
+// NewProver returns a Prover that will use the Pedersen generators pc for the
+// high-level commitments, and absorb the proof into t.
+func NewProver(pc *bulletproofs.PedersenGens, t *transcript.Transcript) *Prover {
+	t.AppendMessage("dom-sep", []byte("r1cs v1"))
+	return &Prover{t: t, pc: pc}
+}
+
+// Commit commits to the high-level value v with the blinding factor
+// vBlinding, and returns the commitment, which must be sent to the
+// verifier, and the Variable representing v.
+func (p *Prover) Commit(v, vBlinding *ristretto255.Scalar) (*ristretto255.Element, Variable) {
+	i := len(p.v)
+	p.v = append(p.v, ristretto255.NewScalar().Set(v))
+	p.vBlinding = append(p.vBlinding, ristretto255.NewScalar().Set(vBlinding))
+
+	V := p.pc.Commit(v, vBlinding)
+	p.t.AppendElement("V", V)
+	return V, Variable{kind: kindCommitted, index: i}
+}
+
+// Transcript implements ConstraintSystem.
+func (p *Prover) Transcript() *transcript.Transcript {
+	return p.t
+}
+
+// Multiply implements ConstraintSystem.
+func (p *Prover) Multiply(left, right LinearCombination) (l, r, o Variable) {
+	lv, rv := p.eval(left), p.eval(right)
+	ov := ristretto255.NewScalar().Multiply(lv, rv)
+
+	l, r, o = p.appendMultiplier(lv, rv, ov)
+	p.constraints = append(p.constraints, left.Sub(l.LC()), right.Sub(r.LC()))
+	return l, r, o
+}
+
+// Allocate implements ConstraintSystem.
+func (p *Prover) Allocate(assignment *ristretto255.Scalar) (Variable, error) {
+	if assignment == nil {
+		return Variable{}, errMissingAssignment
+	}
+	if p.hasPending {
+		i := p.pendingMultiplier
+		p.hasPending = false
+		p.aR[i] = ristretto255.NewScalar().Set(assignment)
+		p.aO[i] = ristretto255.NewScalar().Multiply(p.aL[i], p.aR[i])
+		return Variable{kind: kindMultiplierRight, index: i}, nil
+	}
+	l, _, _ := p.appendMultiplier(assignment, ristretto255.NewScalar(), ristretto255.NewScalar())
+	p.pendingMultiplier = l.index
+	p.hasPending = true
+	return l, nil
+}
+
+// AllocateMultiplier implements ConstraintSystem.
+func (p *Prover) AllocateMultiplier(left, right *ristretto255.Scalar) (l, r, o Variable, err error) {
+	if left == nil || right == nil {
+		return Variable{}, Variable{}, Variable{}, errMissingAssignment
+	}
+	l, r, o = p.appendMultiplier(left, right, ristretto255.NewScalar().Multiply(left, right))
+	return l, r, o, nil
+}
+
+func (p *Prover) appendMultiplier(l, r, o *ristretto255.Scalar) (Variable, Variable, Variable) {
+	i := len(p.aL)
+	p.aL = append(p.aL, ristretto255.NewScalar().Set(l))
+	p.aR = append(p.aR, ristretto255.NewScalar().Set(r))
+	p.aO = append(p.aO, ristretto255.NewScalar().Set(o))
+	return Variable{kind: kindMultiplierLeft, index: i},
+		Variable{kind: kindMultiplierRight, index: i},
+		Variable{kind: kindMultiplierOutput, index: i}
+}
+
+// Constrain implements ConstraintSystem.
+func (p *Prover) Constrain(lc LinearCombination) {
+	p.constraints = append(p.constraints, lc)
+}
+
+// SpecifyRandomizedConstraints implements RandomizableConstraintSystem.
+func (p *Prover) SpecifyRandomizedConstraints(callback func(RandomizedConstraintSystem) error) error {
+	return p.deferred.specify(callback)
+}
+
+// randomizingProver is the view of a Prover given to second phase callbacks.
+type randomizingProver struct {
+	*Prover
+}
+
+func (p randomizingProver) ChallengeScalar(label string) *ristretto255.Scalar {
+	return p.t.ChallengeScalar(label)
+}
+
+func (p *Prover) eval(lc LinearCombination) *ristretto255.Scalar {
+	out := ristretto255.NewScalar()
+	t := ristretto255.NewScalar()
+	for _, tm := range lc.terms {
+		var v *ristretto255.Scalar
+		switch tm.v.kind {
+		case kindMultiplierLeft:
+			v = p.aL[tm.v.index]
+		case kindMultiplierRight:
+			v = p.aR[tm.v.index]
+		case kindMultiplierOutput:
+			v = p.aO[tm.v.index]
+		case kindCommitted:
+			v = p.v[tm.v.index]
+		case kindOne:
+			out.Add(out, tm.c)
+			continue
+		}
+		out.Add(out, t.Multiply(tm.c, v))
+	}
+	return out
+}
+
+// Prove runs the second phase of the constraint system, and returns a proof
+// that it is satisfied. The generators in bp must have at least as many
+// elements as the number of multiplication gates, rounded up to the next
+// power of two.
+//
+// Prove does not check that the constraints are satisfied: if they are not,
+// the returned proof will fail to verify.
+func (p *Prover) Prove(bp *bulletproofs.BulletproofGens) (*Proof, error) {
+	p.t.AppendUint64("m", uint64(len(p.v)))
+
+	n1 := len(p.aL)
+	if bp.Capacity() < n1 {
+		return nil, errInvalidGeneratorsLength
+	}
+	iBlinding1, oBlinding1, sBlinding1 := scalar.MustRandom(), scalar.MustRandom(), scalar.MustRandom()
+	sL := randomScalars(n1)
+	sR := randomScalars(n1)
+
+	proof := &Proof{}
+	proof.AI1 = commitVectors(p.aL, bp.G[:n1], p.aR, bp.H[:n1], iBlinding1, p.pc.BBlinding)
+	proof.AO1 = commitVectors(p.aO, bp.G[:n1], nil, nil, oBlinding1, p.pc.BBlinding)
+	proof.S1 = commitVectors(sL, bp.G[:n1], sR, bp.H[:n1], sBlinding1, p.pc.BBlinding)
+	p.t.AppendElement("A_I1", proof.AI1)
+	p.t.AppendElement("A_O1", proof.AO1)
+	p.t.AppendElement("S1", proof.S1)
+
+	// Process the remaining constraints, which can allocate more variables.
+	p.t.AppendMessage("dom-sep", []byte("r1cs-2phase"))
+	if err := p.deferred.run(randomizingProver{p}); err != nil {
+		return nil, err
+	}
+
+	n := len(p.aL)
+	n2 := n - n1
+	paddedN := nextPowerOfTwo(n)
+	if bp.Capacity() < paddedN {
+		return nil, errInvalidGeneratorsLength
+	}
+
+	iBlinding2, oBlinding2, sBlinding2 := ristretto255.NewScalar(), ristretto255.NewScalar(), ristretto255.NewScalar()
+	if n2 > 0 {
+		iBlinding2, oBlinding2, sBlinding2 = scalar.MustRandom(), scalar.MustRandom(), scalar.MustRandom()
+		sL = append(sL, randomScalars(n2)...)
+		sR = append(sR, randomScalars(n2)...)
+		proof.AI2 = commitVectors(p.aL[n1:], bp.G[n1:n], p.aR[n1:], bp.H[n1:n], iBlinding2, p.pc.BBlinding)
+		proof.AO2 = commitVectors(p.aO[n1:], bp.G[n1:n], nil, nil, oBlinding2, p.pc.BBlinding)
+		proof.S2 = commitVectors(sL[n1:], bp.G[n1:n], sR[n1:], bp.H[n1:n], sBlinding2, p.pc.BBlinding)
+	} else {
+		proof.AI2 = ristretto255.NewIdentityElement()
+		proof.AO2 = ristretto255.NewIdentityElement()
+		proof.S2 = ristretto255.NewIdentityElement()
+	}
+	p.t.AppendElement("A_I2", proof.AI2)
+	p.t.AppendElement("A_O2", proof.AO2)
+	p.t.AppendElement("S2", proof.S2)
+
+	y := p.t.ChallengeScalar("y")
+	z := p.t.ChallengeScalar("z")
+
+	wL, wR, wO, wV, _ := flattenConstraints(p.constraints, n, len(p.v), z)
+
+	// l(x) = aL x + aO x^2 + sL x^3 + y^-n o (wR x)
+	// r(x) = y^n o (aR x - 1) + wL x + wO + y^n o sR x^3
+	var l, r [4][]*ristretto255.Scalar
+	for d := range l {
+		l[d] = zeroScalars(n)
+		r[d] = zeroScalars(n)
+	}
+	yInv := ristretto255.NewScalar().Invert(y)
+	expY, expYInv := scalar.One(), scalar.One()
+	tmp := ristretto255.NewScalar()
+	for i := 0; i < n; i++ {
+		l[1][i].Add(p.aL[i], tmp.Multiply(expYInv, wR[i]))
+		l[2][i].Set(p.aO[i])
+		l[3][i].Set(sL[i])
+		r[0][i].Subtract(wO[i], expY)
+		r[1][i].Add(tmp.Multiply(expY, p.aR[i]), wL[i])
+		r[3][i].Multiply(expY, sR[i])
+
+		expY.Multiply(expY, y)
+		expYInv.Multiply(expYInv, yInv)
+	}
+
+	// t(x) = <l(x), r(x)>, of degree 6.
+	var tPoly [7]*ristretto255.Scalar
+	for d := range tPoly {
+		tPoly[d] = ristretto255.NewScalar()
+	}
+	for i := range l {
+		for j := range r {
+			tPoly[i+j].Add(tPoly[i+j], scalar.InnerProduct(l[i], r[j]))
+		}
+	}
+
+	var tBlinding [7]*ristretto255.Scalar
+	for _, d := range []int{1, 3, 4, 5, 6} {
+		tBlinding[d] = scalar.MustRandom()
+	}
+	proof.T1 = p.pc.Commit(tPoly[1], tBlinding[1])
+	proof.T3 = p.pc.Commit(tPoly[3], tBlinding[3])
+	proof.T4 = p.pc.Commit(tPoly[4], tBlinding[4])
+	proof.T5 = p.pc.Commit(tPoly[5], tBlinding[5])
+	proof.T6 = p.pc.Commit(tPoly[6], tBlinding[6])
+	p.t.AppendElement("T_1", proof.T1)
+	p.t.AppendElement("T_3", proof.T3)
+	p.t.AppendElement("T_4", proof.T4)
+	p.t.AppendElement("T_5", proof.T5)
+	p.t.AppendElement("T_6", proof.T6)
+
+	u := p.t.ChallengeScalar("u")
+	x := p.t.ChallengeScalar("x")
+
+	tBlinding[0] = ristretto255.NewScalar()
+	tBlinding[2] = scalar.InnerProduct(wV, p.vBlinding)
+
+	proof.TX = evalPoly(tPoly[:], x)
+	proof.TXBlinding = evalPoly(tBlinding[:], x)
+
+	lVec := make([]*ristretto255.Scalar, 0, paddedN)
+	rVec := make([]*ristretto255.Scalar, 0, paddedN)
+	for i := 0; i < n; i++ {
+		lVec = append(lVec, evalPoly([]*ristretto255.Scalar{l[0][i], l[1][i], l[2][i], l[3][i]}, x))
+		rVec = append(rVec, evalPoly([]*ristretto255.Scalar{r[0][i], r[1][i], r[2][i], r[3][i]}, x))
+	}
+	for i := n; i < paddedN; i++ {
+		lVec = append(lVec, ristretto255.NewScalar())
+		rVec = append(rVec, ristretto255.NewScalar().Negate(expY))
+		expY.Multiply(expY, y)
+	}
+
+	iBlinding := ristretto255.NewScalar().Add(iBlinding1, tmp.Multiply(u, iBlinding2))
+	oBlinding := ristretto255.NewScalar().Add(oBlinding1, tmp.Multiply(u, oBlinding2))
+	sBlinding := ristretto255.NewScalar().Add(sBlinding1, tmp.Multiply(u, sBlinding2))
+	proof.EBlinding = evalPoly([]*ristretto255.Scalar{ristretto255.NewScalar(), iBlinding, oBlinding, sBlinding}, x)
+
+	p.t.AppendScalar("t_x", proof.TX)
+	p.t.AppendScalar("t_x_blinding", proof.TXBlinding)
+	p.t.AppendScalar("e_blinding", proof.EBlinding)
+
+	w := p.t.ChallengeScalar("w")
+	Q := ristretto255.NewIdentityElement().ScalarMult(w, p.pc.B)
+
+	gFactors, hFactors := generatorFactors(n1, paddedN, u, yInv)
+	ipp, err := ipa.ProveUnbound(p.t, Q, gFactors, hFactors, bp.G[:paddedN], bp.H[:paddedN], lVec, rVec)
+	if err != nil {
+		return nil, err
+	}
+	proof.ipp = ipp
+
+	return proof, nil
+}
+
+// generatorFactors returns the factors applied to the G and H generators in
+// the inner product argument: the second phase generators are scaled by u,
+// and H is additionally scaled by y^-i.
+func generatorFactors(n1, paddedN int, u, yInv *ristretto255.Scalar) (gFactors, hFactors []*ristretto255.Scalar) {
+	gFactors = make([]*ristretto255.Scalar, paddedN)
+	hFactors = make([]*ristretto255.Scalar, paddedN)
+	expYInv := scalar.One()
+	for i := 0; i < paddedN; i++ {
+		if i < n1 {
+			gFactors[i] = scalar.One()
+		} else {
+			gFactors[i] = ristretto255.NewScalar().Set(u)
+		}
+		hFactors[i] = ristretto255.NewScalar().Multiply(gFactors[i], expYInv)
+		expYInv.Multiply(expYInv, yInv)
+	}
+	return gFactors, hFactors
+}
+
+// commitVectors returns <a, G> + <b, H> + blinding * B.
+func commitVectors(a []*ristretto255.Scalar, G []*ristretto255.Element,
+	b []*ristretto255.Scalar, H []*ristretto255.Element,
+	blinding *ristretto255.Scalar, B *ristretto255.Element) *ristretto255.Element {
+	scalars := make([]*ristretto255.Scalar, 0, len(a)+len(b)+1)
+	points := make([]*ristretto255.Element, 0, len(a)+len(b)+1)
+	scalars = append(append(append(scalars, a...), b...), blinding)
+	points = append(append(append(points, G...), H...), B)
+	return ristretto255.NewIdentityElement().MultiScalarMult(scalars, points)
+}
+
+func evalPoly(coeffs []*ristretto255.Scalar, x *ristretto255.Scalar) *ristretto255.Scalar {
+	out := ristretto255.NewScalar()
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		out.Multiply(out, x)
+		out.Add(out, coeffs[i])
+	}
+	return out
+}
+
+func randomScalars(n int) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, n)
+	for i := range out {
+		out[i] = scalar.MustRandom()
+	}
+	return out
+}
+
+func zeroScalars(n int) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, n)
+	for i := range out {
+		out[i] = ristretto255.NewScalar()
+	}
+	return out
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
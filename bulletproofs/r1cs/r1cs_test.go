@@ -0,0 +1,263 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r1cs
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/bulletproofs"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+// shuffleGadget constrains y to be a permutation of x, following the
+// example in the dalek r1cs documentation.
+func shuffleGadget(cs RandomizableConstraintSystem, x, y []Variable) error {
+	if len(x) != len(y) {
+		panic("mismatched lengths")
+	}
+	k := len(x)
+	if k == 1 {
+		cs.Constrain(y[0].LC().Sub(x[0].LC()))
+		return nil
+	}
+	return cs.SpecifyRandomizedConstraints(func(cs RandomizedConstraintSystem) error {
+		z := cs.ChallengeScalar("shuffle challenge")
+		zLC := Constant(z)
+
+		// Make last x multiplier for i = k-1 and k-2
+		_, _, lastMulX := cs.Multiply(x[k-1].LC().Sub(zLC), x[k-2].LC().Sub(zLC))
+		for i := k - 3; i >= 0; i-- {
+			_, _, lastMulX = cs.Multiply(lastMulX.LC(), x[i].LC().Sub(zLC))
+		}
+		_, _, lastMulY := cs.Multiply(y[k-1].LC().Sub(zLC), y[k-2].LC().Sub(zLC))
+		for i := k - 3; i >= 0; i-- {
+			_, _, lastMulY = cs.Multiply(lastMulY.LC(), y[i].LC().Sub(zLC))
+		}
+		cs.Constrain(lastMulX.LC().Sub(lastMulY.LC()))
+		return nil
+	})
+}
+
+func proveShuffle(t *testing.T, pc *bulletproofs.PedersenGens, bp *bulletproofs.BulletproofGens,
+	input, output []uint64) (*Proof, []*ristretto255.Element, []*ristretto255.Element) {
+	prover := NewProver(pc, transcript.New("ShuffleTest"))
+	var xVars, yVars []Variable
+	var xComm, yComm []*ristretto255.Element
+	for _, v := range input {
+		C, V := prover.Commit(scalar.FromUint64(v), scalar.MustRandom())
+		xComm, xVars = append(xComm, C), append(xVars, V)
+	}
+	for _, v := range output {
+		C, V := prover.Commit(scalar.FromUint64(v), scalar.MustRandom())
+		yComm, yVars = append(yComm, C), append(yVars, V)
+	}
+	if err := shuffleGadget(prover, xVars, yVars); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := prover.Prove(bp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof, xComm, yComm
+}
+
+func verifyShuffle(pc *bulletproofs.PedersenGens, bp *bulletproofs.BulletproofGens,
+	proof *Proof, xComm, yComm []*ristretto255.Element) error {
+	verifier := NewVerifier(transcript.New("ShuffleTest"))
+	var xVars, yVars []Variable
+	for _, C := range xComm {
+		xVars = append(xVars, verifier.Commit(C))
+	}
+	for _, C := range yComm {
+		yVars = append(yVars, verifier.Commit(C))
+	}
+	if err := shuffleGadget(verifier, xVars, yVars); err != nil {
+		return err
+	}
+	return verifier.Verify(proof, pc, bp)
+}
+
+func TestShuffleGadget(t *testing.T) {
+	pc := bulletproofs.DefaultPedersenGens()
+	bp := bulletproofs.NewBulletproofGens(128)
+
+	for k := 1; k <= 8; k++ {
+		input := make([]uint64, k)
+		output := make([]uint64, k)
+		for i := range input {
+			input[i] = uint64(i*7 + 3)
+			output[(i+3)%k] = input[i]
+		}
+		proof, xComm, yComm := proveShuffle(t, pc, bp, input, output)
+		if err := verifyShuffle(pc, bp, proof, xComm, yComm); err != nil {
+			t.Errorf("k = %d: valid shuffle failed to verify: %v", k, err)
+		}
+
+		encoded := proof.Bytes()
+		decoded, err := new(Proof).SetBytes(encoded)
+		if err != nil {
+			t.Fatalf("k = %d: %v", k, err)
+		}
+		if err := verifyShuffle(pc, bp, decoded, xComm, yComm); err != nil {
+			t.Errorf("k = %d: decoded proof failed to verify: %v", k, err)
+		}
+
+		if err := verifyShuffle(pc, bp, &Proof{}, xComm, yComm); err == nil {
+			t.Errorf("k = %d: zero proof verified", k)
+		}
+		incomplete := *proof
+		incomplete.ipp = nil
+		if err := verifyShuffle(pc, bp, &incomplete, xComm, yComm); err == nil {
+			t.Errorf("k = %d: proof without inner product argument verified", k)
+		}
+
+		output[0]++
+		proof, xComm, yComm = proveShuffle(t, pc, bp, input, output)
+		if err := verifyShuffle(pc, bp, proof, xComm, yComm); err == nil {
+			t.Errorf("k = %d: invalid shuffle verified", k)
+		}
+	}
+}
+
+// rangeGadget constrains v to be in [0, 2^n) by allocating its bits.
+func rangeGadget(cs ConstraintSystem, v LinearCombination, bits []*ristretto255.Scalar, n int) error {
+	var sum LinearCombination
+	exp2 := scalar.One()
+	two := scalar.FromUint64(2)
+	for i := 0; i < n; i++ {
+		var l, r *ristretto255.Scalar
+		if bits != nil {
+			l = bits[i]
+			r = ristretto255.NewScalar().Subtract(l, scalar.One())
+		}
+		a, b, o, err := cs.AllocateMultiplier(l, r)
+		if err != nil {
+			return err
+		}
+		// a * (a - 1) = 0, and b = a - 1.
+		cs.Constrain(o.LC())
+		cs.Constrain(a.LC().Sub(One.LC()).Sub(b.LC()))
+		sum = sum.Add(Term(a, exp2))
+		exp2 = ristretto255.NewScalar().Multiply(exp2, two)
+	}
+	cs.Constrain(sum.Sub(v))
+	return nil
+}
+
+func TestRangeGadget(t *testing.T) {
+	pc := bulletproofs.DefaultPedersenGens()
+	bp := bulletproofs.NewBulletproofGens(16)
+
+	for _, tc := range []struct {
+		v     uint64
+		valid bool
+	}{{0, true}, {1, true}, {200, true}, {255, true}, {256, false}, {1 << 20, false}} {
+		prover := NewProver(pc, transcript.New("RangeTest"))
+		V, vVar := prover.Commit(scalar.FromUint64(tc.v), scalar.MustRandom())
+		bits := make([]*ristretto255.Scalar, 8)
+		for i := range bits {
+			bits[i] = scalar.FromUint64((tc.v >> i) & 1)
+		}
+		if err := rangeGadget(prover, vVar.LC(), bits, 8); err != nil {
+			t.Fatal(err)
+		}
+		proof, err := prover.Prove(bp)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifier := NewVerifier(transcript.New("RangeTest"))
+		vVar = verifier.Commit(V)
+		if err := rangeGadget(verifier, vVar.LC(), nil, 8); err != nil {
+			t.Fatal(err)
+		}
+		err = verifier.Verify(proof, pc, bp)
+		if tc.valid && err != nil {
+			t.Errorf("v = %d: valid proof failed to verify: %v", tc.v, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("v = %d: invalid proof verified", tc.v)
+		}
+	}
+}
+
+func TestAllocateAndMultiply(t *testing.T) {
+	pc := bulletproofs.DefaultPedersenGens()
+	bp := bulletproofs.NewBulletproofGens(4)
+
+	// Prove knowledge of committed a, b such that a * b = 21 and a + b = 10,
+	// allocating the factors with Allocate.
+	gadget := func(cs ConstraintSystem, a, b *ristretto255.Scalar, aComm, bComm Variable) error {
+		l, err := cs.Allocate(a)
+		if err != nil {
+			return err
+		}
+		r, err := cs.Allocate(b)
+		if err != nil {
+			return err
+		}
+		_, _, o := cs.Multiply(l.LC(), r.LC())
+		cs.Constrain(o.LC().Sub(Constant(scalar.FromUint64(21))))
+		cs.Constrain(l.LC().Add(r.LC()).Sub(Constant(scalar.FromUint64(10))))
+		cs.Constrain(l.LC().Sub(aComm.LC()))
+		cs.Constrain(r.LC().Sub(bComm.LC()))
+		return nil
+	}
+
+	a, b := scalar.FromUint64(3), scalar.FromUint64(7)
+	prover := NewProver(pc, transcript.New("AllocateTest"))
+	A, aVar := prover.Commit(a, scalar.MustRandom())
+	B, bVar := prover.Commit(b, scalar.MustRandom())
+	if err := gadget(prover, a, b, aVar, bVar); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := prover.Prove(bp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewVerifier(transcript.New("AllocateTest"))
+	if _, err := verifier.Allocate(scalar.One()); err == nil {
+		t.Error("verifier accepted an assignment")
+	}
+	verifier = NewVerifier(transcript.New("AllocateTest"))
+	aVar, bVar = verifier.Commit(A), verifier.Commit(B)
+	if err := gadget(verifier, nil, nil, aVar, bVar); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.Verify(proof, pc, bp); err != nil {
+		t.Errorf("valid proof failed to verify: %v", err)
+	}
+
+	verifier = NewVerifier(transcript.New("AllocateTest"))
+	aVar, bVar = verifier.Commit(B), verifier.Commit(A)
+	if err := gadget(verifier, nil, nil, aVar, bVar); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.Verify(proof, pc, bp); err == nil {
+		t.Error("proof verified against swapped commitments")
+	}
+
+	if _, err := NewProver(pc, transcript.New("AllocateTest")).Allocate(nil); err == nil {
+		t.Error("prover accepted a missing assignment")
+	}
+}
+
+func TestProofEncodingErrors(t *testing.T) {
+	for _, l := range []int{0, 32, 15 * 32, 17 * 32} {
+		if _, err := new(Proof).SetBytes(make([]byte, l)); err == nil {
+			t.Errorf("accepted a proof of %d bytes", l)
+		}
+	}
+	b := make([]byte, 16*32)
+	for i := range b[:32] {
+		b[i] = 0xff
+	}
+	if _, err := new(Proof).SetBytes(b); err == nil {
+		t.Error("accepted a non-canonical element")
+	}
+}
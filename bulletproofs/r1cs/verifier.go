@@ -0,0 +1,288 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r1cs
+
+import (
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/bulletproofs"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+// Verifier builds a constraint system from the commitments to the
+// high-level variables, and verifies a Proof of its satisfiability.
+type Verifier struct {
+	t *transcript.Transcript
+
+	constraints []LinearCombination
+
+	V []*ristretto255.Element
+
+	numVars           int
+	pendingMultiplier int
+	hasPending        bool
+
+	deferred randomizedConstraints
+}
+
+var _ RandomizableConstraintSystem = &Verifier{}
+
+// NewVerifier returns a Verifier that will absorb the proof into t.
+func NewVerifier(t *transcript.Transcript) *Verifier {
+	t.AppendMessage("dom-sep", []byte("r1cs v1"))
+	return &Verifier{t: t}
+}
+
+// Commit absorbs the high-level commitment V, and returns the Variable
+// representing the committed value.
+func (v *Verifier) Commit(V *ristretto255.Element) Variable {
+	i := len(v.V)
+	v.V = append(v.V, ristretto255.NewIdentityElement().Set(V))
+	v.t.AppendElement("V", V)
+	return Variable{kind: kindCommitted, index: i}
+}
+
+// Transcript implements ConstraintSystem.
+func (v *Verifier) Transcript() *transcript.Transcript {
+	return v.t
+}
+
+// Multiply implements ConstraintSystem.
+func (v *Verifier) Multiply(left, right LinearCombination) (l, r, o Variable) {
+	l, r, o = v.appendMultiplier()
+	v.constraints = append(v.constraints, left.Sub(l.LC()), right.Sub(r.LC()))
+	return l, r, o
+}
+
+// Allocate implements ConstraintSystem.
+func (v *Verifier) Allocate(assignment *ristretto255.Scalar) (Variable, error) {
+	if assignment != nil {
+		return Variable{}, errUnexpectedAssignment
+	}
+	if v.hasPending {
+		v.hasPending = false
+		return Variable{kind: kindMultiplierRight, index: v.pendingMultiplier}, nil
+	}
+	l, _, _ := v.appendMultiplier()
+	v.pendingMultiplier = l.index
+	v.hasPending = true
+	return l, nil
+}
+
+// AllocateMultiplier implements ConstraintSystem.
+func (v *Verifier) AllocateMultiplier(left, right *ristretto255.Scalar) (l, r, o Variable, err error) {
+	if left != nil || right != nil {
+		return Variable{}, Variable{}, Variable{}, errUnexpectedAssignment
+	}
+	l, r, o = v.appendMultiplier()
+	return l, r, o, nil
+}
+
+func (v *Verifier) appendMultiplier() (Variable, Variable, Variable) {
+	i := v.numVars
+	v.numVars++
+	return Variable{kind: kindMultiplierLeft, index: i},
+		Variable{kind: kindMultiplierRight, index: i},
+		Variable{kind: kindMultiplierOutput, index: i}
+}
+
+// Constrain implements ConstraintSystem.
+func (v *Verifier) Constrain(lc LinearCombination) {
+	v.constraints = append(v.constraints, lc)
+}
+
+// SpecifyRandomizedConstraints implements RandomizableConstraintSystem.
+func (v *Verifier) SpecifyRandomizedConstraints(callback func(RandomizedConstraintSystem) error) error {
+	return v.deferred.specify(callback)
+}
+
+// randomizingVerifier is the view of a Verifier given to second phase
+// callbacks.
+type randomizingVerifier struct {
+	*Verifier
+}
+
+func (v randomizingVerifier) ChallengeScalar(label string) *ristretto255.Scalar {
+	return v.t.ChallengeScalar(label)
+}
+
+// Verify runs the second phase of the constraint system, and checks that
+// proof shows it is satisfied for the committed values.
+//
+// The whole check is performed with a single VarTimeMultiScalarMult.
+func (v *Verifier) Verify(proof *Proof, pc *bulletproofs.PedersenGens, bp *bulletproofs.BulletproofGens) error {
+	if proof == nil || !proof.complete() {
+		return errVerification
+	}
+
+	v.t.AppendUint64("m", uint64(len(v.V)))
+
+	n1 := v.numVars
+	if err := v.t.ValidateAndAppendElement("A_I1", proof.AI1); err != nil {
+		return errVerification
+	}
+	if err := v.t.ValidateAndAppendElement("A_O1", proof.AO1); err != nil {
+		return errVerification
+	}
+	if err := v.t.ValidateAndAppendElement("S1", proof.S1); err != nil {
+		return errVerification
+	}
+
+	v.t.AppendMessage("dom-sep", []byte("r1cs-2phase"))
+	if err := v.deferred.run(randomizingVerifier{v}); err != nil {
+		return err
+	}
+
+	n := v.numVars
+	n2 := n - n1
+	paddedN := nextPowerOfTwo(n)
+	if bp.Capacity() < paddedN {
+		return errInvalidGeneratorsLength
+	}
+
+	if n2 == 0 {
+		// Without second phase multipliers, the second phase commitments
+		// must be the identity, and are not checked below.
+		identity := ristretto255.NewIdentityElement()
+		if proof.AI2.Equal(identity) != 1 || proof.AO2.Equal(identity) != 1 || proof.S2.Equal(identity) != 1 {
+			return errVerification
+		}
+		v.t.AppendElement("A_I2", proof.AI2)
+		v.t.AppendElement("A_O2", proof.AO2)
+		v.t.AppendElement("S2", proof.S2)
+	} else {
+		if err := v.t.ValidateAndAppendElement("A_I2", proof.AI2); err != nil {
+			return errVerification
+		}
+		if err := v.t.ValidateAndAppendElement("A_O2", proof.AO2); err != nil {
+			return errVerification
+		}
+		if err := v.t.ValidateAndAppendElement("S2", proof.S2); err != nil {
+			return errVerification
+		}
+	}
+
+	y := v.t.ChallengeScalar("y")
+	z := v.t.ChallengeScalar("z")
+
+	for _, T := range []struct {
+		label string
+		e     *ristretto255.Element
+	}{{"T_1", proof.T1}, {"T_3", proof.T3}, {"T_4", proof.T4}, {"T_5", proof.T5}, {"T_6", proof.T6}} {
+		if err := v.t.ValidateAndAppendElement(T.label, T.e); err != nil {
+			return errVerification
+		}
+	}
+
+	u := v.t.ChallengeScalar("u")
+	x := v.t.ChallengeScalar("x")
+
+	v.t.AppendScalar("t_x", proof.TX)
+	v.t.AppendScalar("t_x_blinding", proof.TXBlinding)
+	v.t.AppendScalar("e_blinding", proof.EBlinding)
+
+	w := v.t.ChallengeScalar("w")
+
+	wL, wR, wO, wV, wc := flattenConstraints(v.constraints, n, len(v.V), z)
+
+	uSq, uInvSq, s, err := proof.ipp.VerificationScalars(paddedN, v.t)
+	if err != nil {
+		return err
+	}
+	a, b := proof.ipp.A, proof.ipp.B
+
+	yInv := ristretto255.NewScalar().Invert(y)
+	yInvVec := scalar.Powers(yInv, paddedN)
+
+	ynegWR := zeroScalars(paddedN)
+	for i := 0; i < n; i++ {
+		ynegWR[i].Multiply(wR[i], yInvVec[i])
+	}
+	delta := scalar.InnerProduct(ynegWR[:n], wL)
+
+	gFactors, _ := generatorFactors(n1, paddedN, u, yInv)
+
+	tmp := ristretto255.NewScalar()
+	gScalars := make([]*ristretto255.Scalar, paddedN)
+	hScalars := make([]*ristretto255.Scalar, paddedN)
+	for i := 0; i < paddedN; i++ {
+		// u_or_1 * (x * y^-i * wR_i - a * s_i)
+		g := ristretto255.NewScalar().Multiply(x, ynegWR[i])
+		g.Subtract(g, tmp.Multiply(a, s[i]))
+		gScalars[i] = g.Multiply(g, gFactors[i])
+
+		// u_or_1 * (y^-i * (x * wL_i + wO_i - b / s_i) - 1)
+		h := ristretto255.NewScalar()
+		if i < n {
+			h.Multiply(x, wL[i])
+			h.Add(h, wO[i])
+		}
+		h.Subtract(h, tmp.Multiply(b, s[paddedN-1-i]))
+		h.Multiply(h, yInvVec[i])
+		h.Subtract(h, scalar.One())
+		hScalars[i] = h.Multiply(h, gFactors[i])
+	}
+
+	r := scalar.MustRandom()
+	xx := ristretto255.NewScalar().Multiply(x, x)
+	rxx := ristretto255.NewScalar().Multiply(r, xx)
+	xxx := ristretto255.NewScalar().Multiply(x, xx)
+
+	scalars := []*ristretto255.Scalar{
+		x, xx, xxx, // A_I1, A_O1, S1
+		ristretto255.NewScalar().Multiply(u, x),   // A_I2
+		ristretto255.NewScalar().Multiply(u, xx),  // A_O2
+		ristretto255.NewScalar().Multiply(u, xxx), // S2
+	}
+	points := []*ristretto255.Element{proof.AI1, proof.AO1, proof.S1, proof.AI2, proof.AO2, proof.S2}
+
+	for i := range v.V {
+		scalars = append(scalars, ristretto255.NewScalar().Multiply(wV[i], rxx))
+		points = append(points, v.V[i])
+	}
+
+	// r * (x T_1 + x^3 T_3 + x^4 T_4 + x^5 T_5 + x^6 T_6)
+	scalars = append(scalars,
+		ristretto255.NewScalar().Multiply(r, x),
+		ristretto255.NewScalar().Multiply(rxx, x),
+		ristretto255.NewScalar().Multiply(rxx, xx),
+		ristretto255.NewScalar().Multiply(rxx, xxx),
+		ristretto255.NewScalar().Multiply(rxx, ristretto255.NewScalar().Multiply(xx, xx)))
+	points = append(points, proof.T1, proof.T3, proof.T4, proof.T5, proof.T6)
+
+	// w * (t_x - a * b) + r * (x^2 * (wc + delta) - t_x)
+	bScalar := ristretto255.NewScalar().Multiply(a, b)
+	bScalar.Subtract(proof.TX, bScalar)
+	bScalar.Multiply(bScalar, w)
+	rTerm := ristretto255.NewScalar().Add(wc, delta)
+	rTerm.Multiply(rTerm, xx)
+	rTerm.Subtract(rTerm, proof.TX)
+	rTerm.Multiply(rTerm, r)
+	bScalar.Add(bScalar, rTerm)
+	scalars = append(scalars, bScalar)
+	points = append(points, pc.B)
+
+	// -e_blinding - r * t_x_blinding
+	bBlindingScalar := ristretto255.NewScalar().Multiply(r, proof.TXBlinding)
+	bBlindingScalar.Add(bBlindingScalar, proof.EBlinding)
+	bBlindingScalar.Negate(bBlindingScalar)
+	scalars = append(scalars, bBlindingScalar)
+	points = append(points, pc.BBlinding)
+
+	scalars = append(scalars, gScalars...)
+	points = append(points, bp.G[:paddedN]...)
+	scalars = append(scalars, hScalars...)
+	points = append(points, bp.H[:paddedN]...)
+	scalars = append(scalars, uSq...)
+	points = append(points, proof.ipp.L...)
+	scalars = append(scalars, uInvSq...)
+	points = append(points, proof.ipp.R...)
+
+	check := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(scalars, points)
+	if check.Equal(ristretto255.NewIdentityElement()) != 1 {
+		return errVerification
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package clsag implements the CLSAG linkable ring signatures of Goodell,
+// Noether and RandomRun, "Concise Linkable Ring Signatures and Forgery
+// Against Adversarial Keys" (FC 2020), over ristretto255.
+//
+// A Signature shows that the signer knows the secret key x of one of the
+// public keys in a ring, P[l] = x * G, without revealing l. Each Signature
+// carries a key image I = x * Hp(P[l]), where Hp is hash_to_ristretto255, so
+// two signatures by the same key have the same key image, which can be
+// compared with Linked to detect double-signing.
+//
+// Ring members can optionally carry an auxiliary key C[i], as Monero does
+// with commitments to zero. The signer then also shows knowledge of z such
+// that C[l] = z * G, and the Signature carries the auxiliary image
+// D = z * Hp(P[l]). Without auxiliary keys, D is the identity.
+//
+// Because ristretto255 has prime order, key images have no torsion
+// component, and each key has exactly one valid key image.
+package clsag
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/h2c"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+const hashToPointDST = "ristretto255-CLSAG-v1-hash-to-point"
+
+var (
+	errVerification = errors.New("clsag: signature verification failed")
+	errFormat       = errors.New("clsag: malformed signature encoding")
+	errRing         = errors.New("clsag: invalid ring")
+)
+
+// Signature is a CLSAG signature for a ring of len(S) members.
+type Signature struct {
+	// KeyImage is I = x * Hp(P[l]), the link tag of the signing key.
+	KeyImage *ristretto255.Element
+	// AuxImage is D = z * Hp(P[l]), or the identity without auxiliary keys.
+	AuxImage *ristretto255.Element
+	// C0 is the challenge at ring index zero.
+	C0 *ristretto255.Scalar
+	// S are the responses, one per ring member.
+	S []*ristretto255.Scalar
+}
+
+// KeyImage returns the key image of the secret key x, which is the same for
+// all signatures made with x. It can be used to check a Signature against a
+// list of spent keys without a Signature from each.
+func KeyImage(x *ristretto255.Scalar) *ristretto255.Element {
+	P := ristretto255.NewIdentityElement().ScalarBaseMult(x)
+	return ristretto255.NewIdentityElement().ScalarMult(x, hashToPoint(P))
+}
+
+func hashToPoint(P *ristretto255.Element) *ristretto255.Element {
+	return h2c.HashToElement(P.Bytes(), []byte(hashToPointDST))
+}
+
+// Linked reports whether a and b were made with the same secret key.
+func Linked(a, b *Signature) bool {
+	return a.KeyImage.Equal(b.KeyImage) == 1
+}
+
+// checkRing validates the ring and the optional auxiliary keys, and returns
+// the auxiliary keys with nil replaced by identity Elements.
+func checkRing(ring, aux []*ristretto255.Element) ([]*ristretto255.Element, error) {
+	if len(ring) == 0 {
+		return nil, errRing
+	}
+	if aux != nil && len(aux) != len(ring) {
+		return nil, errors.New("clsag: mismatched ring and auxiliary key lengths")
+	}
+	identity := ristretto255.NewIdentityElement()
+	for _, P := range ring {
+		if P == nil || P.Equal(identity) == 1 {
+			return nil, errRing
+		}
+	}
+	if aux == nil {
+		aux = make([]*ristretto255.Element, len(ring))
+		for i := range aux {
+			aux[i] = ristretto255.NewIdentityElement()
+		}
+	}
+	for _, C := range aux {
+		if C == nil {
+			return nil, errRing
+		}
+	}
+	return aux, nil
+}
+
+// setup returns the aggregation coefficients mu_P and mu_C, and the
+// transcript the round challenges are derived from.
+func setup(msg []byte, ring, aux []*ristretto255.Element, I, D *ristretto255.Element) (muP, muC *ristretto255.Scalar, t *transcript.Transcript) {
+	t = transcript.New("ristretto255-CLSAG-v1")
+	t.AppendUint64("n", uint64(len(ring)))
+	for i := range ring {
+		t.AppendElement("P", ring[i])
+		t.AppendElement("C", aux[i])
+	}
+	t.AppendElement("I", I)
+	t.AppendElement("D", D)
+	muP = t.Clone().ChallengeScalar("mu_P")
+	muC = t.Clone().ChallengeScalar("mu_C")
+	t.AppendMessage("msg", msg)
+	return muP, muC, t
+}
+
+func roundChallenge(t *transcript.Transcript, L, R *ristretto255.Element) *ristretto255.Scalar {
+	t = t.Clone()
+	t.AppendElement("L", L)
+	t.AppendElement("R", R)
+	return t.ChallengeScalar("c")
+}
+
+// Sign returns a signature on msg by the secret key x of ring[index].
+func Sign(msg []byte, ring []*ristretto255.Element, index int, x *ristretto255.Scalar) (*Signature, error) {
+	return SignWithAux(msg, ring, nil, index, x, ristretto255.NewScalar())
+}
+
+// SignWithAux returns a signature on msg by the secret keys x of ring[index]
+// and z of aux[index]. If aux is nil, z must be zero.
+func SignWithAux(msg []byte, ring, aux []*ristretto255.Element, index int, x, z *ristretto255.Scalar) (*Signature, error) {
+	aux, err := checkRing(ring, aux)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(ring) {
+		return nil, errors.New("clsag: index out of range")
+	}
+	G := ristretto255.NewGeneratorElement()
+	if ristretto255.NewIdentityElement().ScalarMult(x, G).Equal(ring[index]) != 1 ||
+		ristretto255.NewIdentityElement().ScalarMult(z, G).Equal(aux[index]) != 1 {
+		return nil, errors.New("clsag: secret keys do not match the ring")
+	}
+
+	n := len(ring)
+	Hp := make([]*ristretto255.Element, n)
+	for i := range ring {
+		Hp[i] = hashToPoint(ring[i])
+	}
+	sig := &Signature{
+		KeyImage: ristretto255.NewIdentityElement().ScalarMult(x, Hp[index]),
+		AuxImage: ristretto255.NewIdentityElement().ScalarMult(z, Hp[index]),
+		S:        make([]*ristretto255.Scalar, n),
+	}
+	muP, muC, t := setup(msg, ring, aux, sig.KeyImage, sig.AuxImage)
+
+	// W[i] = mu_P * P[i] + mu_C * C[i] aggregates the keys of each member,
+	// with discrete log w = mu_P * x + mu_C * z for the signer.
+	W := make([]*ristretto255.Element, n)
+	for i := range W {
+		W[i] = ristretto255.NewIdentityElement().VarTimeMultiScalarMult(
+			[]*ristretto255.Scalar{muP, muC}, []*ristretto255.Element{ring[i], aux[i]})
+	}
+	WImage := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(
+		[]*ristretto255.Scalar{muP, muC}, []*ristretto255.Element{sig.KeyImage, sig.AuxImage})
+	w := ristretto255.NewScalar().Multiply(muP, x)
+	w.Add(w, ristretto255.NewScalar().Multiply(muC, z))
+
+	alpha, err := scalar.Random(nil)
+	if err != nil {
+		return nil, err
+	}
+	L := ristretto255.NewIdentityElement().ScalarBaseMult(alpha)
+	R := ristretto255.NewIdentityElement().ScalarMult(alpha, Hp[index])
+	c := roundChallenge(t, L, R)
+	for k := 1; k < n; k++ {
+		i := (index + k) % n
+		if i == 0 {
+			sig.C0 = c
+		}
+		if sig.S[i], err = scalar.Random(nil); err != nil {
+			return nil, err
+		}
+		L, R = ringStep(c, sig.S[i], W[i], Hp[i], WImage)
+		c = roundChallenge(t, L, R)
+	}
+	if index == 0 {
+		sig.C0 = c
+	}
+	sig.S[index] = ristretto255.NewScalar().Subtract(alpha, w.Multiply(c, w))
+	return sig, nil
+}
+
+// ringStep returns L = s * G + c * W and R = s * Hp + c * WImage.
+func ringStep(c, s *ristretto255.Scalar, W, Hp, WImage *ristretto255.Element) (L, R *ristretto255.Element) {
+	L = ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(c, W, s)
+	R = ristretto255.NewIdentityElement().VarTimeMultiScalarMult(
+		[]*ristretto255.Scalar{s, c}, []*ristretto255.Element{Hp, WImage})
+	return L, R
+}
+
+// Verify returns nil if sig is a valid signature on msg by a member of ring,
+// and an error otherwise.
+func Verify(msg []byte, ring []*ristretto255.Element, sig *Signature) error {
+	return VerifyWithAux(msg, ring, nil, sig)
+}
+
+// VerifyWithAux returns nil if sig is a valid signature on msg by a member of
+// ring with auxiliary keys aux, and an error otherwise.
+func VerifyWithAux(msg []byte, ring, aux []*ristretto255.Element, sig *Signature) error {
+	noAux := aux == nil
+	aux, err := checkRing(ring, aux)
+	if err != nil {
+		return err
+	}
+	if sig == nil || sig.KeyImage == nil || sig.AuxImage == nil || sig.C0 == nil ||
+		slices.Contains(sig.S, nil) {
+		return errVerification
+	}
+	identity := ristretto255.NewIdentityElement()
+	if len(sig.S) != len(ring) || sig.KeyImage.Equal(identity) == 1 ||
+		(noAux && sig.AuxImage.Equal(identity) != 1) {
+		return errVerification
+	}
+
+	muP, muC, t := setup(msg, ring, aux, sig.KeyImage, sig.AuxImage)
+	WImage := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(
+		[]*ristretto255.Scalar{muP, muC}, []*ristretto255.Element{sig.KeyImage, sig.AuxImage})
+	c := sig.C0
+	for i := range ring {
+		W := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(
+			[]*ristretto255.Scalar{muP, muC}, []*ristretto255.Element{ring[i], aux[i]})
+		L, R := ringStep(c, sig.S[i], W, hashToPoint(ring[i]), WImage)
+		c = roundChallenge(t, L, R)
+	}
+	if c.Equal(sig.C0) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+// ParseRing decodes a ring from the concatenation of the 32-byte encodings of
+// its members. Each must be the canonical encoding of a non-identity Element,
+// and members must be distinct.
+func ParseRing(b []byte) ([]*ristretto255.Element, error) {
+	if len(b) == 0 || len(b)%32 != 0 {
+		return nil, errRing
+	}
+	var ring []*ristretto255.Element
+	seen := make(map[string]bool)
+	identity := ristretto255.NewIdentityElement()
+	for ; len(b) > 0; b = b[32:] {
+		P, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[:32])
+		if err != nil || P.Equal(identity) == 1 || seen[string(b[:32])] {
+			return nil, errRing
+		}
+		seen[string(b[:32])] = true
+		ring = append(ring, P)
+	}
+	return ring, nil
+}
+
+// Bytes returns the encoding of the signature, I || D || c_0 || s_0 || ... ||
+// s_(n-1), for a total of 96 + 32n bytes.
+func (sig *Signature) Bytes() []byte {
+	out := make([]byte, 0, 96+32*len(sig.S))
+	out = append(out, sig.KeyImage.Bytes()...)
+	out = append(out, sig.AuxImage.Bytes()...)
+	out = append(out, sig.C0.Bytes()...)
+	for _, s := range sig.S {
+		out = append(out, s.Bytes()...)
+	}
+	return out
+}
+
+// SetBytes sets sig to the decoded encoding b, and returns sig. The key
+// image must be a canonical encoding of a non-identity Element, and all
+// Scalars must be canonical. If b is not a valid encoding, SetBytes returns
+// nil and an error, and the receiver is unchanged.
+func (sig *Signature) SetBytes(b []byte) (*Signature, error) {
+	if len(b) < 128 || len(b)%32 != 0 {
+		return nil, errFormat
+	}
+	I, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[:32])
+	if err != nil || I.Equal(ristretto255.NewIdentityElement()) == 1 {
+		return nil, errFormat
+	}
+	D, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[32:64])
+	if err != nil {
+		return nil, errFormat
+	}
+	c0, err := ristretto255.NewScalar().SetCanonicalBytes(b[64:96])
+	if err != nil {
+		return nil, errFormat
+	}
+	var S []*ristretto255.Scalar
+	for b = b[96:]; len(b) > 0; b = b[32:] {
+		s, err := ristretto255.NewScalar().SetCanonicalBytes(b[:32])
+		if err != nil {
+			return nil, errFormat
+		}
+		S = append(S, s)
+	}
+	sig.KeyImage, sig.AuxImage, sig.C0, sig.S = I, D, c0, S
+	return sig, nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package clsag
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+func newRing(n int) ([]*ristretto255.Element, []*ristretto255.Scalar) {
+	ring := make([]*ristretto255.Element, n)
+	keys := make([]*ristretto255.Scalar, n)
+	for i := range ring {
+		keys[i] = scalar.MustRandom()
+		ring[i] = ristretto255.NewIdentityElement().ScalarBaseMult(keys[i])
+	}
+	return ring, keys
+}
+
+func TestSignVerify(t *testing.T) {
+	msg := []byte("transfer 10 to bob")
+	for _, n := range []int{1, 2, 5, 11} {
+		ring, keys := newRing(n)
+		for l := range ring {
+			sig, err := Sign(msg, ring, l, keys[l])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(msg, ring, sig); err != nil {
+				t.Fatalf("n = %d, l = %d: %v", n, l, err)
+			}
+			if sig.KeyImage.Equal(KeyImage(keys[l])) != 1 {
+				t.Errorf("n = %d, l = %d: unexpected key image", n, l)
+			}
+			if err := Verify([]byte("transfer 99 to eve"), ring, sig); err == nil {
+				t.Errorf("n = %d, l = %d: signature verified for another message", n, l)
+			}
+			if err := Verify(msg, ring, &Signature{}); err == nil {
+				t.Errorf("n = %d, l = %d: zero signature verified", n, l)
+			}
+			incomplete := *sig
+			incomplete.S = make([]*ristretto255.Scalar, n)
+			if err := Verify(msg, ring, &incomplete); err == nil {
+				t.Errorf("n = %d, l = %d: signature without responses verified", n, l)
+			}
+
+			decoded, err := new(Signature).SetBytes(sig.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(msg, ring, decoded); err != nil {
+				t.Errorf("n = %d, l = %d: decoded signature: %v", n, l, err)
+			}
+		}
+	}
+}
+
+func TestVerifyRejectsWrongRing(t *testing.T) {
+	msg := []byte("msg")
+	ring, keys := newRing(4)
+	sig, err := Sign(msg, ring, 2, keys[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, _ := newRing(1)
+	replaced := append([]*ristretto255.Element(nil), ring...)
+	replaced[0] = other[0]
+	if err := Verify(msg, replaced, sig); err == nil {
+		t.Error("signature verified for a modified ring")
+	}
+	reordered := []*ristretto255.Element{ring[1], ring[0], ring[2], ring[3]}
+	if err := Verify(msg, reordered, sig); err == nil {
+		t.Error("signature verified for a reordered ring")
+	}
+	if err := Verify(msg, ring[:3], sig); err == nil {
+		t.Error("signature verified for a truncated ring")
+	}
+
+	if _, err := Sign(msg, ring, 1, keys[2]); err == nil {
+		t.Error("Sign accepted a key that is not at the index")
+	}
+	withIdentity := append([]*ristretto255.Element(nil), ring...)
+	withIdentity[3] = ristretto255.NewIdentityElement()
+	if _, err := Sign(msg, withIdentity, 2, keys[2]); err == nil {
+		t.Error("Sign accepted a ring containing the identity")
+	}
+}
+
+func TestLinkability(t *testing.T) {
+	ring, keys := newRing(5)
+	otherRing, otherKeys := newRing(3)
+	otherRing[1], otherKeys[1] = ring[3], keys[3]
+
+	a, _ := Sign([]byte("first"), ring, 3, keys[3])
+	b, _ := Sign([]byte("second"), otherRing, 1, otherKeys[1])
+	c, _ := Sign([]byte("first"), ring, 4, keys[4])
+	if !Linked(a, b) {
+		t.Error("signatures by the same key in different rings are not linked")
+	}
+	if Linked(a, c) {
+		t.Error("signatures by different keys are linked")
+	}
+
+	// Substituting the key image of another key breaks the signature.
+	forged, _ := Sign([]byte("first"), ring, 3, keys[3])
+	forged.KeyImage = KeyImage(keys[0])
+	if err := Verify([]byte("first"), ring, forged); err == nil {
+		t.Error("signature verified with a substituted key image")
+	}
+}
+
+func TestAuxiliaryKeys(t *testing.T) {
+	msg := []byte("msg")
+	ring, keys := newRing(4)
+	aux, auxKeys := newRing(4)
+	sig, err := SignWithAux(msg, ring, aux, 1, keys[1], auxKeys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyWithAux(msg, ring, aux, sig); err != nil {
+		t.Fatal(err)
+	}
+	if sig.KeyImage.Equal(KeyImage(keys[1])) != 1 {
+		t.Error("auxiliary keys changed the key image")
+	}
+	if err := Verify(msg, ring, sig); err == nil {
+		t.Error("signature verified without its auxiliary keys")
+	}
+	aux[0] = ring[0]
+	if err := VerifyWithAux(msg, ring, aux, sig); err == nil {
+		t.Error("signature verified with modified auxiliary keys")
+	}
+	if _, err := SignWithAux(msg, ring, aux, 1, keys[1], keys[1]); err == nil {
+		t.Error("SignWithAux accepted a wrong auxiliary secret")
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	ring, keys := newRing(3)
+	sig, _ := Sign([]byte("msg"), ring, 0, keys[0])
+	enc := sig.Bytes()
+	if len(enc) != 96+32*3 {
+		t.Fatalf("unexpected signature length %d", len(enc))
+	}
+
+	// A non-canonical field element encoding.
+	nonCanonical := make([]byte, 32)
+	for i := range nonCanonical {
+		nonCanonical[i] = 0xff
+	}
+	nonCanonical[31] = 0x7f
+	for _, tc := range []struct {
+		name string
+		b    []byte
+	}{
+		{"truncated", enc[:len(enc)-1]},
+		{"no responses", enc[:96]},
+		{"identity key image", append(make([]byte, 32), enc[32:]...)},
+		{"non-canonical key image", append(append([]byte(nil), nonCanonical...), enc[32:]...)},
+		{"non-canonical aux image", append(append(append([]byte(nil), enc[:32]...), nonCanonical...), enc[64:]...)},
+		{"non-canonical response", append(append([]byte(nil), enc[:len(enc)-32]...), nonCanonical...)},
+	} {
+		s := &Signature{}
+		if _, err := s.SetBytes(tc.b); err == nil {
+			t.Errorf("%s: SetBytes accepted an invalid encoding", tc.name)
+		}
+		if s.KeyImage != nil {
+			t.Errorf("%s: SetBytes modified the receiver", tc.name)
+		}
+	}
+
+	var ringBytes []byte
+	for _, P := range ring {
+		ringBytes = append(ringBytes, P.Bytes()...)
+	}
+	parsed, err := ParseRing(ringBytes)
+	if err != nil || len(parsed) != 3 || parsed[2].Equal(ring[2]) != 1 {
+		t.Fatalf("ParseRing failed: %v", err)
+	}
+	for _, b := range [][]byte{
+		nil,
+		ringBytes[:40],
+		append(append([]byte(nil), ringBytes...), ringBytes[:32]...),
+		append(append([]byte(nil), ringBytes...), make([]byte, 32)...),
+		append(append([]byte(nil), ringBytes...), nonCanonical...),
+	} {
+		if _, err := ParseRing(b); err == nil {
+			t.Errorf("ParseRing accepted an invalid ring of %d bytes", len(b))
+		}
+	}
+}
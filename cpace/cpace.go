@@ -0,0 +1,210 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cpace implements the CPace balanced password-authenticated key
+// exchange from draft-irtf-cfrg-cpace, with the CPaceRistretto255 ciphersuite
+// and SHA-512, in the initiator-responder setting.
+//
+// Both parties hold the same low-entropy password, and derive from it, a
+// session identifier, and an optional channel identifier a secret generator.
+// Each sends one Message, and both derive the same intermediate session key
+// (ISK) only if they used the same inputs. An active attacker can test a
+// single password guess per run of the protocol.
+//
+// The ISK is not authenticated: applications should confirm it, for example
+// by using it to key the first messages of the session.
+package cpace
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/element"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// ISKSize is the size in bytes of the intermediate session key.
+const ISKSize = sha512.Size
+
+// dsi is the domain separation identifier of the ciphersuite.
+const dsi = "CPaceRistretto255"
+
+// hashBlockSize is the input block size of SHA-512 (s_in_bytes).
+const hashBlockSize = 128
+
+// appendPrefixed appends b to out, prefixed with its LEB128-encoded length
+// (prepend_len).
+func appendPrefixed(out, b []byte) []byte {
+	out = binary.AppendUvarint(out, uint64(len(b)))
+	return append(out, b...)
+}
+
+// generatorString returns the generator_string for the inputs, zero-padded
+// so that the password fills the first SHA-512 block.
+func generatorString(password, channelID, sessionID []byte) []byte {
+	var out []byte
+	out = appendPrefixed(out, []byte(dsi))
+	out = appendPrefixed(out, password)
+	prefixLen := len(out)
+	padLen := max(0, hashBlockSize-1-prefixLen)
+	out = appendPrefixed(out, make([]byte, padLen))
+	out = appendPrefixed(out, channelID)
+	return appendPrefixed(out, sessionID)
+}
+
+// calculateGenerator returns the secret generator for the inputs.
+func calculateGenerator(password, channelID, sessionID []byte) *ristretto255.Element {
+	h := sha512.Sum512(generatorString(password, channelID, sessionID))
+	g, err := ristretto255.NewIdentityElement().SetUniformBytes(h[:])
+	if err != nil {
+		panic("cpace: internal error: SetUniformBytes failed")
+	}
+	return g
+}
+
+// Message is a CPace protocol message, carrying a public share and the
+// sender associated data.
+type Message struct {
+	Y  *ristretto255.Element
+	AD []byte
+}
+
+// Bytes returns the encoding of m, the lv_cat of the share and the
+// associated data.
+func (m *Message) Bytes() []byte {
+	out := appendPrefixed(nil, m.Y.Bytes())
+	return appendPrefixed(out, m.AD)
+}
+
+// SetBytes sets m to the decoded encoding b, and returns m. If b is not a
+// valid encoding, or the share is the identity element, SetBytes returns nil
+// and an error, and the receiver is unchanged.
+func (m *Message) SetBytes(b []byte) (*Message, error) {
+	y, rest, ok := readPrefixed(b)
+	if !ok || len(y) != 32 {
+		return nil, errors.New("cpace: invalid message encoding")
+	}
+	ad, rest, ok := readPrefixed(rest)
+	if !ok || len(rest) != 0 {
+		return nil, errors.New("cpace: invalid message encoding")
+	}
+	Y, err := element.DecodeNonIdentity(y)
+	if err != nil {
+		return nil, errors.New("cpace: invalid message encoding")
+	}
+	m.Y, m.AD = Y, append([]byte(nil), ad...)
+	return m, nil
+}
+
+// readPrefixed reads a length-prefixed field from the start of b.
+func readPrefixed(b []byte) (field, rest []byte, ok bool) {
+	n, l := binary.Uvarint(b)
+	if l <= 0 || n > uint64(len(b)-l) {
+		return nil, nil, false
+	}
+	b = b[l:]
+	return b[:n], b[n:], true
+}
+
+// Initiator holds the secret state of the party that sends the first
+// Message.
+type Initiator struct {
+	sessionID []byte
+	y         *ristretto255.Scalar
+	msg       *Message
+}
+
+// Start starts an exchange as the initiator, and returns the Message to send
+// to the responder. The session identifier should be unique to the exchange
+// and agreed on by both parties in advance. The channel identifier, for
+// example the encoded party identities, and ad are optional. If rand is nil,
+// crypto/rand.Reader is used.
+func Start(rand io.Reader, password, channelID, sessionID, ad []byte) (*Initiator, *Message, error) {
+	y, err := scalar.RandomNonZero(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	st, msg := start(y, password, channelID, sessionID, ad)
+	return st, msg, nil
+}
+
+func start(y *ristretto255.Scalar, password, channelID, sessionID, ad []byte) (*Initiator, *Message) {
+	g := calculateGenerator(password, channelID, sessionID)
+	msg := &Message{
+		Y:  ristretto255.NewIdentityElement().ScalarMult(y, g),
+		AD: append([]byte(nil), ad...),
+	}
+	st := &Initiator{sessionID: append([]byte(nil), sessionID...), y: y, msg: msg}
+	return st, msg
+}
+
+// Finish processes the responder Message, and returns the intermediate
+// session key. Finish returns an error if the exchange was already finished
+// or if msg is invalid, but not if the parties used different passwords: in
+// that case the keys simply differ.
+func (st *Initiator) Finish(msg *Message) (isk []byte, err error) {
+	if st.y == nil {
+		return nil, errors.New("cpace: exchange already finished")
+	}
+	y := st.y
+	st.y = nil
+	k, err := scalarMultVfy(y, msg.Y)
+	if err != nil {
+		return nil, err
+	}
+	return deriveISK(st.sessionID, k, st.msg, msg), nil
+}
+
+// Respond processes the initiator Message as the responder, and returns the
+// Message to send back and the intermediate session key. The inputs are as
+// for Start, and ad is the responder associated data.
+func Respond(rand io.Reader, password, channelID, sessionID, ad []byte, msg *Message) (*Message, []byte, error) {
+	y, err := scalar.RandomNonZero(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	return respond(y, password, channelID, sessionID, ad, msg)
+}
+
+func respond(y *ristretto255.Scalar, password, channelID, sessionID, ad []byte, msg *Message) (*Message, []byte, error) {
+	k, err := scalarMultVfy(y, msg.Y)
+	if err != nil {
+		return nil, nil, err
+	}
+	g := calculateGenerator(password, channelID, sessionID)
+	resp := &Message{
+		Y:  ristretto255.NewIdentityElement().ScalarMult(y, g),
+		AD: append([]byte(nil), ad...),
+	}
+	return resp, deriveISK(sessionID, k, msg, resp), nil
+}
+
+// scalarMultVfy returns the encoding of y * Y, or an error if Y or the
+// result is the identity element.
+func scalarMultVfy(y *ristretto255.Scalar, Y *ristretto255.Element) ([]byte, error) {
+	if Y == nil || element.IsIdentity(Y) {
+		return nil, errors.New("cpace: invalid public share")
+	}
+	k := ristretto255.NewIdentityElement().ScalarMult(y, Y)
+	if element.IsIdentity(k) {
+		return nil, errors.New("cpace: invalid public share")
+	}
+	return k.Bytes(), nil
+}
+
+// deriveISK returns the intermediate session key for the shared secret k and
+// the initiator-responder transcript of msgA and msgB.
+func deriveISK(sessionID, k []byte, msgA, msgB *Message) []byte {
+	var in []byte
+	in = appendPrefixed(in, []byte(dsi+"_ISK"))
+	in = appendPrefixed(in, sessionID)
+	in = appendPrefixed(in, k)
+	in = append(in, msgA.Bytes()...)
+	in = append(in, msgB.Bytes()...)
+	isk := sha512.Sum512(in)
+	return isk[:]
+}
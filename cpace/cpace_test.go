@@ -0,0 +1,199 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cpace
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+var (
+	testChannelID = []byte("\x0bA_initiator\x0bB_responder")
+	testSessionID = []byte("\x7e\x4b\x47\x91\xd6\xa8\xef\x01\x9b\x93\x6c\x79\xfb\x7f\x2c\x57")
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func checkHex(t *testing.T, name string, got []byte, want string) {
+	t.Helper()
+	if hex.EncodeToString(got) != want {
+		t.Errorf("got %s %x, want %s", name, got, want)
+	}
+}
+
+func exchange(t *testing.T, passwordA, passwordB, sessionA, sessionB []byte) (iskA, iskB []byte) {
+	t.Helper()
+	st, msgA, err := Start(nil, passwordA, testChannelID, sessionA, []byte("ADa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgA, err = new(Message).SetBytes(msgA.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgB, iskB, err := Respond(nil, passwordB, testChannelID, sessionB, []byte("ADb"), msgA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msgA.AD, []byte("ADa")) {
+		t.Errorf("responder got AD %q", msgA.AD)
+	}
+	msgB, err = new(Message).SetBytes(msgB.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	iskA, err = st.Finish(msgB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msgB.AD, []byte("ADb")) {
+		t.Errorf("initiator got AD %q", msgB.AD)
+	}
+	return iskA, iskB
+}
+
+func TestExchange(t *testing.T) {
+	iskA, iskB := exchange(t, []byte("Password"), []byte("Password"), testSessionID, testSessionID)
+	if !bytes.Equal(iskA, iskB) || len(iskA) != ISKSize {
+		t.Error("keys do not match")
+	}
+
+	iskA, iskB = exchange(t, []byte("Password"), []byte("Passw0rd"), testSessionID, testSessionID)
+	if bytes.Equal(iskA, iskB) {
+		t.Error("keys match with different passwords")
+	}
+
+	iskA, iskB = exchange(t, []byte("Password"), []byte("Password"), testSessionID, []byte("other"))
+	if bytes.Equal(iskA, iskB) {
+		t.Error("keys match with different session identifiers")
+	}
+}
+
+func TestFixedScalars(t *testing.T) {
+	// Derive every intermediate value from its byte-level definition in
+	// draft-irtf-cfrg-cpace, without the package helpers, using the inputs of
+	// the ristretto255 test vector but small fixed scalars, so that K = 6 * g.
+	// This checks the encodings, not the published vector outputs.
+	password := []byte("Password")
+	adA, adB := []byte("ADa"), []byte("ADb")
+	ya, yb := scalar.FromUint64(2), scalar.FromUint64(3)
+
+	// generator_string = lv_cat(DSI, PRS, zero_bytes(len_zpad), CI, sid),
+	// with len_zpad = 128 - 1 - len(prepend_len(PRS)) - len(prepend_len(DSI)).
+	genString := decodeHex(t, "11"+hex.EncodeToString([]byte(dsi))+
+		"08"+hex.EncodeToString(password)+
+		"64"+hex.EncodeToString(make([]byte, 100))+
+		"18"+hex.EncodeToString(testChannelID)+
+		"10"+hex.EncodeToString(testSessionID))
+	h := sha512.Sum512(genString)
+	g, err := ristretto255.NewIdentityElement().SetUniformBytes(h[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	Ya := ristretto255.NewIdentityElement().ScalarMult(ya, g)
+	Yb := ristretto255.NewIdentityElement().ScalarMult(yb, g)
+	K := ristretto255.NewIdentityElement().ScalarMult(scalar.FromUint64(6), g)
+
+	// ISK = SHA-512(lv_cat(DSI || "_ISK", sid, K) || lv_cat(Ya, ADa) ||
+	// lv_cat(Yb, ADb)).
+	iskInput := decodeHex(t, "15"+hex.EncodeToString([]byte(dsi+"_ISK"))+
+		"10"+hex.EncodeToString(testSessionID)+
+		"20"+hex.EncodeToString(K.Bytes())+
+		"20"+hex.EncodeToString(Ya.Bytes())+"03"+hex.EncodeToString(adA)+
+		"20"+hex.EncodeToString(Yb.Bytes())+"03"+hex.EncodeToString(adB))
+	wantISK := sha512.Sum512(iskInput)
+
+	st, msgA := start(ya, password, testChannelID, testSessionID, adA)
+	if msgA.Y.Equal(Ya) != 1 {
+		t.Errorf("got Ya %x, want %x", msgA.Y.Bytes(), Ya.Bytes())
+	}
+	checkHex(t, "msgA", msgA.Bytes(), "20"+hex.EncodeToString(Ya.Bytes())+"03"+hex.EncodeToString(adA))
+	msgB, iskB, err := respond(yb, password, testChannelID, testSessionID, adB, msgA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msgB.Y.Equal(Yb) != 1 {
+		t.Errorf("got Yb %x, want %x", msgB.Y.Bytes(), Yb.Bytes())
+	}
+	iskA, err := st.Finish(msgB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHex(t, "initiator ISK", iskA, hex.EncodeToString(wantISK[:]))
+	checkHex(t, "responder ISK", iskB, hex.EncodeToString(wantISK[:]))
+
+	if _, err := st.Finish(msgB); err == nil {
+		t.Error("exchange was finished twice")
+	}
+}
+
+func TestGeneratorString(t *testing.T) {
+	// The password and the padding fill exactly the first SHA-512 block,
+	// leaving one byte for the length of the padding.
+	got := generatorString([]byte("Password"), testChannelID, testSessionID)
+	want := "11" + hex.EncodeToString([]byte(dsi)) +
+		"08" + hex.EncodeToString([]byte("Password")) +
+		"64" + hex.EncodeToString(make([]byte, 100)) +
+		"18" + hex.EncodeToString(testChannelID) +
+		"10" + hex.EncodeToString(testSessionID)
+	if hex.EncodeToString(got) != want {
+		t.Errorf("got %x, want %s", got, want)
+	}
+
+	long := bytes.Repeat([]byte("p"), 200)
+	got = generatorString(long, nil, nil)
+	if len(got) != 1+len(dsi)+2+200+1+1+1 {
+		t.Errorf("unexpected padding for a long password: length %d", len(got))
+	}
+}
+
+func TestInvalidShare(t *testing.T) {
+	identity := &Message{Y: ristretto255.NewIdentityElement()}
+	if _, _, err := Respond(nil, []byte("Password"), nil, nil, nil, identity); err == nil {
+		t.Error("responder accepted the identity element")
+	}
+	st, _, err := Start(nil, []byte("Password"), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Finish(identity); err == nil {
+		t.Error("initiator accepted the identity element")
+	}
+	if _, err := new(Message).SetBytes(identity.Bytes()); err == nil {
+		t.Error("decoded a message with the identity element")
+	}
+}
+
+func TestMessageEncoding(t *testing.T) {
+	_, msg, err := Start(nil, []byte("Password"), nil, nil, bytes.Repeat([]byte("a"), 300))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := msg.Bytes()
+	got, err := new(Message).SetBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Y.Equal(msg.Y) != 1 || !bytes.Equal(got.AD, msg.AD) {
+		t.Error("message did not round-trip")
+	}
+	for _, bad := range [][]byte{nil, b[:len(b)-1], append(b, 0), b[1:]} {
+		if _, err := new(Message).SetBytes(bad); err == nil {
+			t.Errorf("decoded invalid message %x", bad)
+		}
+	}
+}
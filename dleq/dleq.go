@@ -0,0 +1,191 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dleq implements non-interactive Chaum-Pedersen proofs of discrete
+// logarithm equality over ristretto255, as specified in RFC 9497, Section 2.2.
+//
+// A Proof shows that the prover knows k such that B = k * A and D[i] = k * C[i]
+// for all i. Batches of pairs (C[i], D[i]) are compressed into a single
+// composite pair with a random linear combination, so proofs have a fixed size
+// of 64 bytes regardless of the batch size.
+package dleq
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/h2c"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// ProofSize is the size in bytes of an encoded Proof.
+const ProofSize = 64
+
+var errVerification = errors.New("dleq: proof verification failed")
+
+// Proof is a DLEQ proof, made of a challenge and a response.
+type Proof struct {
+	C, S *ristretto255.Scalar
+}
+
+// Suite binds proofs to a protocol context string. For the VOPRF and POPRF
+// modes of RFC 9497, it is "OPRFV1-" || I2OSP(mode, 1) || "-ristretto255-SHA512".
+type Suite struct {
+	contextString []byte
+}
+
+// NewSuite returns a Suite for the given context string.
+func NewSuite(contextString []byte) *Suite {
+	return &Suite{contextString: append([]byte(nil), contextString...)}
+}
+
+func (s *Suite) hashToScalar(msg []byte) *ristretto255.Scalar {
+	dst := append([]byte("HashToScalar-"), s.contextString...)
+	return h2c.HashToScalar(msg, dst)
+}
+
+// appendPrefixed appends I2OSP(len(b), 2) || b to out.
+func appendPrefixed(out, b []byte) []byte {
+	out = binary.BigEndian.AppendUint16(out, uint16(len(b)))
+	return append(out, b...)
+}
+
+// composites implements ComputeComposites, or ComputeCompositesFast if k is
+// not nil.
+func (s *Suite) composites(k *ristretto255.Scalar, B *ristretto255.Element, C, D []*ristretto255.Element) (M, Z *ristretto255.Element, err error) {
+	if len(C) != len(D) || len(C) == 0 {
+		return nil, nil, errors.New("dleq: mismatched or empty batch")
+	}
+	if len(C) > 0xffff {
+		return nil, nil, errors.New("dleq: batch is too large")
+	}
+
+	var seedTranscript []byte
+	seedTranscript = appendPrefixed(seedTranscript, B.Bytes())
+	seedTranscript = appendPrefixed(seedTranscript, append([]byte("Seed-"), s.contextString...))
+	seed := sha512.Sum512(seedTranscript)
+
+	d := make([]*ristretto255.Scalar, len(C))
+	for i := range C {
+		var t []byte
+		t = appendPrefixed(t, seed[:])
+		t = binary.BigEndian.AppendUint16(t, uint16(i))
+		t = appendPrefixed(t, C[i].Bytes())
+		t = appendPrefixed(t, D[i].Bytes())
+		t = append(t, "Composite"...)
+		d[i] = s.hashToScalar(t)
+	}
+
+	M = ristretto255.NewIdentityElement().VarTimeMultiScalarMult(d, C)
+	if k != nil {
+		Z = ristretto255.NewIdentityElement().ScalarMult(k, M)
+	} else {
+		Z = ristretto255.NewIdentityElement().VarTimeMultiScalarMult(d, D)
+	}
+	return M, Z, nil
+}
+
+func (s *Suite) challenge(B, M, Z, t2, t3 *ristretto255.Element) *ristretto255.Scalar {
+	var t []byte
+	for _, e := range []*ristretto255.Element{B, M, Z, t2, t3} {
+		t = appendPrefixed(t, e.Bytes())
+	}
+	t = append(t, "Challenge"...)
+	return s.hashToScalar(t)
+}
+
+// Prove returns a proof that B = k * A and D[i] = k * C[i], using a random
+// nonce.
+func (s *Suite) Prove(k *ristretto255.Scalar, A, B *ristretto255.Element, C, D []*ristretto255.Element) (*Proof, error) {
+	r, err := scalar.Random(nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.ProveWithNonce(k, A, B, C, D, r)
+}
+
+// ProveDeterministic is like Prove, but derives the nonce from k and the
+// statement, so that it does not depend on a random number generator.
+func (s *Suite) ProveDeterministic(k *ristretto255.Scalar, A, B *ristretto255.Element, C, D []*ristretto255.Element) (*Proof, error) {
+	M, Z, err := s.composites(k, B, C, D)
+	if err != nil {
+		return nil, err
+	}
+	var t []byte
+	t = appendPrefixed(t, k.Bytes())
+	for _, e := range []*ristretto255.Element{A, B, M, Z} {
+		t = appendPrefixed(t, e.Bytes())
+	}
+	t = append(t, "DeterministicNonce"...)
+	return s.prove(k, A, B, M, Z, s.hashToScalar(t)), nil
+}
+
+// ProveWithNonce is like Prove, but uses the caller-provided nonce r, which
+// must be uniformly random and secret, and never reused. It implements
+// GenerateProof from RFC 9497, and is mostly useful to reproduce test
+// vectors.
+func (s *Suite) ProveWithNonce(k *ristretto255.Scalar, A, B *ristretto255.Element, C, D []*ristretto255.Element, r *ristretto255.Scalar) (*Proof, error) {
+	M, Z, err := s.composites(k, B, C, D)
+	if err != nil {
+		return nil, err
+	}
+	return s.prove(k, A, B, M, Z, r), nil
+}
+
+func (s *Suite) prove(k *ristretto255.Scalar, A, B, M, Z *ristretto255.Element, r *ristretto255.Scalar) *Proof {
+	t2 := ristretto255.NewIdentityElement().ScalarMult(r, A)
+	t3 := ristretto255.NewIdentityElement().ScalarMult(r, M)
+	c := s.challenge(B, M, Z, t2, t3)
+	resp := ristretto255.NewScalar().Multiply(c, k)
+	resp.Subtract(r, resp)
+	return &Proof{C: c, S: resp}
+}
+
+// Verify checks that proof shows that B = k * A and D[i] = k * C[i] for
+// the same k.
+func (s *Suite) Verify(A, B *ristretto255.Element, C, D []*ristretto255.Element, proof *Proof) error {
+	if proof == nil || proof.C == nil || proof.S == nil {
+		return errVerification
+	}
+	M, Z, err := s.composites(nil, B, C, D)
+	if err != nil {
+		return err
+	}
+	t2 := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(
+		[]*ristretto255.Scalar{proof.S, proof.C}, []*ristretto255.Element{A, B})
+	t3 := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(
+		[]*ristretto255.Scalar{proof.S, proof.C}, []*ristretto255.Element{M, Z})
+	if s.challenge(B, M, Z, t2, t3).Equal(proof.C) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+// Bytes returns the 64-byte encoding of the proof, c || s.
+func (p *Proof) Bytes() []byte {
+	out := make([]byte, 0, ProofSize)
+	out = append(out, p.C.Bytes()...)
+	return append(out, p.S.Bytes()...)
+}
+
+// SetBytes sets p to the decoded 64-byte encoding b, and returns p. If b is
+// not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (p *Proof) SetBytes(b []byte) (*Proof, error) {
+	if len(b) != ProofSize {
+		return nil, errors.New("dleq: invalid proof length")
+	}
+	c, err := ristretto255.NewScalar().SetCanonicalBytes(b[:32])
+	if err != nil {
+		return nil, errors.New("dleq: invalid proof encoding")
+	}
+	s, err := ristretto255.NewScalar().SetCanonicalBytes(b[32:])
+	if err != nil {
+		return nil, errors.New("dleq: invalid proof encoding")
+	}
+	p.C, p.S = c, s
+	return p, nil
+}
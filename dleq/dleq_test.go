@@ -0,0 +1,162 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dleq
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func decodeElement(t *testing.T, s string) *ristretto255.Element {
+	t.Helper()
+	e, err := ristretto255.NewIdentityElement().SetCanonicalBytes(decodeHex(t, s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func decodeScalar(t *testing.T, s string) *ristretto255.Scalar {
+	t.Helper()
+	x, err := ristretto255.NewScalar().SetCanonicalBytes(decodeHex(t, s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+// Test vectors from RFC 9497, Appendix A.1.2 (ristretto255-SHA512, VOPRF mode).
+func TestRFC9497Vectors(t *testing.T) {
+	suite := NewSuite([]byte("OPRFV1-\x01-ristretto255-SHA512"))
+	k := decodeScalar(t, "e6f73f344b79b379f1a0dd37e07ff62e38d9f71345ce62ae3a9bc60b04ccd909")
+	A := ristretto255.NewGeneratorElement()
+	B := decodeElement(t, "c803e2cc6b05fc15064549b5920659ca4a77b2cca6f04f6b357009335476ad4e")
+	if ristretto255.NewIdentityElement().ScalarBaseMult(k).Equal(B) != 1 {
+		t.Fatal("pkSm does not match skSm")
+	}
+
+	for _, tc := range []struct {
+		blinded, evaluated []string
+		r, proof           string
+	}{
+		{
+			blinded:   []string{"863f330cc1a1259ed5a5998a23acfd37fb4351a793a5b3c090b642ddc439b945"},
+			evaluated: []string{"aa8fa048764d5623868679402ff6108d2521884fa138cd7f9c7669a9a014267e"},
+			r:         "222a5e897cf59db8145db8d16e597e8facb80ae7d4e26d9881aa6f61d645fc0e",
+			proof:     "ddef93772692e535d1a53903db24367355cc2cc78de93b3be5a8ffcc6985dd066d4346421d17bf5117a2a1ff0fcb2a759f58a539dfbe857a40bce4cf49ec600d",
+		},
+		{
+			blinded:   []string{"cc0b2a350101881d8a4cba4c80241d74fb7dcbfde4a61fde2f91443c2bf9ef0c"},
+			evaluated: []string{"60a59a57208d48aca71e9e850d22674b611f752bed48b36f7a91b372bd7ad468"},
+			r:         "222a5e897cf59db8145db8d16e597e8facb80ae7d4e26d9881aa6f61d645fc0e",
+			proof:     "401a0da6264f8cf45bb2f5264bc31e109155600babb3cd4e5af7d181a2c9dc0a67154fabf031fd936051dec80b0b6ae29c9503493dde7393b722eafdf5a50b02",
+		},
+		{
+			blinded: []string{"863f330cc1a1259ed5a5998a23acfd37fb4351a793a5b3c090b642ddc439b945",
+				"90a0145ea9da29254c3a56be4fe185465ebb3bf2a1801f7124bbbadac751e654"},
+			evaluated: []string{"aa8fa048764d5623868679402ff6108d2521884fa138cd7f9c7669a9a014267e",
+				"cc5ac221950a49ceaa73c8db41b82c20372a4c8d63e5dded2db920b7eee36a2a"},
+			r:     "419c4f4f5052c53c45f3da494d2b67b220d02118e0857cdbcf037f9ea84bbe0c",
+			proof: "cc203910175d786927eeb44ea847328047892ddf8590e723c37205cb74600b0a5ab5337c8eb4ceae0494c2cf89529dcf94572ed267473d567aeed6ab873dee08",
+		},
+	} {
+		var C, D []*ristretto255.Element
+		for i := range tc.blinded {
+			C = append(C, decodeElement(t, tc.blinded[i]))
+			D = append(D, decodeElement(t, tc.evaluated[i]))
+		}
+		proof, err := suite.ProveWithNonce(k, A, B, C, D, decodeScalar(t, tc.r))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(proof.Bytes()); got != tc.proof {
+			t.Errorf("got proof %s, want %s", got, tc.proof)
+		}
+		decoded, err := new(Proof).SetBytes(decodeHex(t, tc.proof))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := suite.Verify(A, B, C, D, decoded); err != nil {
+			t.Errorf("test vector proof failed to verify: %v", err)
+		}
+	}
+}
+
+func TestBatchProof(t *testing.T) {
+	suite := NewSuite([]byte("test context"))
+	k := scalar.MustRandom()
+	A := ristretto255.NewGeneratorElement()
+	B := ristretto255.NewIdentityElement().ScalarBaseMult(k)
+
+	var C, D []*ristretto255.Element
+	for i := 0; i < 10; i++ {
+		c := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.MustRandom())
+		C = append(C, c)
+		D = append(D, ristretto255.NewIdentityElement().ScalarMult(k, c))
+	}
+
+	for name, prove := range map[string]func(*ristretto255.Scalar, *ristretto255.Element, *ristretto255.Element, []*ristretto255.Element, []*ristretto255.Element) (*Proof, error){
+		"random":        suite.Prove,
+		"deterministic": suite.ProveDeterministic,
+	} {
+		proof, err := prove(k, A, B, C, D)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := suite.Verify(A, B, C, D, proof); err != nil {
+			t.Errorf("%s: valid proof failed to verify: %v", name, err)
+		}
+		if err := NewSuite([]byte("other context")).Verify(A, B, C, D, proof); err == nil {
+			t.Errorf("%s: proof verified in a different context", name)
+		}
+		if err := suite.Verify(A, B, C, D, &Proof{C: proof.C}); err == nil {
+			t.Errorf("%s: proof without a response verified", name)
+		}
+		if err := suite.Verify(A, B, C[1:], D[1:], proof); err == nil {
+			t.Errorf("%s: proof verified for a subset of the batch", name)
+		}
+		D2 := append([]*ristretto255.Element(nil), D...)
+		D2[5] = ristretto255.NewIdentityElement().Add(D2[5], A)
+		if err := suite.Verify(A, B, C, D2, proof); err == nil {
+			t.Errorf("%s: proof verified for a batch with a wrong pair", name)
+		}
+	}
+
+	p1, _ := suite.ProveDeterministic(k, A, B, C, D)
+	p2, _ := suite.ProveDeterministic(k, A, B, C, D)
+	if !bytes.Equal(p1.Bytes(), p2.Bytes()) {
+		t.Error("deterministic proofs differ")
+	}
+	p3, _ := suite.Prove(k, A, B, C, D)
+	if bytes.Equal(p1.Bytes(), p3.Bytes()) {
+		t.Error("random proof equals the deterministic one")
+	}
+
+	// A proof with the wrong k doesn't verify.
+	proof, err := suite.Prove(scalar.MustRandom(), A, B, C, D)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := suite.Verify(A, B, C, D, proof); err == nil {
+		t.Error("proof with the wrong key verified")
+	}
+
+	if _, err := suite.Prove(k, A, B, C, D[1:]); err == nil {
+		t.Error("accepted mismatched batch")
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package dlog solves bounded discrete logarithms in ristretto255, that is,
+// it recovers m from P = m * G when m is known to be small, for example to
+// decrypt lifted ElGamal ciphertexts.
+//
+// A Table implements baby-step giant-step with a precomputed table that can be
+// saved and reused. Kangaroo implements Pollard's kangaroo method with
+// distinguished points, which needs no precomputation and can search
+// arbitrary intervals.
+//
+// Since Element is not comparable, Elements are identified by their
+// canonical encodings. Execution time depends on the solution.
+package dlog
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// ErrNotFound is returned when the discrete logarithm is not in the
+// searched range.
+var ErrNotFound = errors.New("dlog: discrete logarithm not found in range")
+
+const tableMagic = "ristretto255-dlog-bsgs-v1"
+
+// Table is a baby-step giant-step table of the Elements j * G for j in
+// [0, BabySteps). A Table with m baby steps solves discrete logarithms up to
+// 2^k in about 2^k / m Element additions and encodings.
+//
+// A Table is safe for concurrent use.
+type Table struct {
+	m uint64
+	// entries maps the first 8 bytes of the encoding of j * G to j. Rare
+	// prefix collisions are kept in overflow.
+	entries  map[uint64]uint32
+	overflow map[uint64][]uint32
+}
+
+func key(b []byte) uint64 {
+	return binary.LittleEndian.Uint64(b[:8])
+}
+
+// NewTable precomputes a Table with babySteps entries, which must be at
+// least 1 and at most 2^32. The table uses roughly 16 bytes of memory per
+// entry, and takes one Element addition and encoding per entry to build.
+func NewTable(babySteps uint64) (*Table, error) {
+	if babySteps == 0 || babySteps > 1<<32 {
+		return nil, errors.New("dlog: invalid number of baby steps")
+	}
+	keys := make([]uint64, babySteps)
+	P := ristretto255.NewIdentityElement()
+	G := ristretto255.NewGeneratorElement()
+	for j := range keys {
+		keys[j] = key(P.Bytes())
+		P.Add(P, G)
+	}
+	return tableFromKeys(keys), nil
+}
+
+func tableFromKeys(keys []uint64) *Table {
+	t := &Table{
+		m:        uint64(len(keys)),
+		entries:  make(map[uint64]uint32, len(keys)),
+		overflow: make(map[uint64][]uint32),
+	}
+	for j, k := range keys {
+		if _, ok := t.entries[k]; ok {
+			t.overflow[k] = append(t.overflow[k], uint32(j))
+			continue
+		}
+		t.entries[k] = uint32(j)
+	}
+	return t
+}
+
+// BabySteps returns the number of entries in the table.
+func (t *Table) BabySteps() uint64 {
+	return t.m
+}
+
+// lookup returns the j such that Q = j * G, if any.
+func (t *Table) lookup(Q *ristretto255.Element) (uint64, bool) {
+	b := Q.Bytes()
+	k := key(b)
+	j, ok := t.entries[k]
+	if !ok {
+		return 0, false
+	}
+	candidates := append([]uint32{j}, t.overflow[k]...)
+	for _, j := range candidates {
+		P := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(uint64(j)))
+		if P.Equal(Q) == 1 {
+			return uint64(j), true
+		}
+	}
+	return 0, false
+}
+
+// Solve returns m such that P = m * G and 0 <= m <= max, or ErrNotFound.
+func (t *Table) Solve(P *ristretto255.Element, max uint64) (uint64, error) {
+	return t.SolveParallel(P, max, 1)
+}
+
+// SolveParallel is like Solve, but splits the giant steps among workers
+// goroutines.
+func (t *Table) SolveParallel(P *ristretto255.Element, max uint64, workers int) (uint64, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	// The giant steps are i = 0, 1, ..., last. last + 1 would overflow for
+	// max = 2^64 - 1 and m = 1, so the loop below never computes it.
+	last := max / t.m
+
+	// Worker w checks P - (w + i * workers) * m * G for i = 0, 1, ...
+	mG := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(t.m))
+	stride := ristretto255.NewIdentityElement().ScalarMult(scalar.FromUint64(uint64(workers)), mG)
+
+	var (
+		once   sync.Once
+		result uint64
+		found  = make(chan struct{})
+		wg     sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w uint64) {
+			defer wg.Done()
+			Q := ristretto255.NewIdentityElement().ScalarMult(scalar.FromUint64(w), mG)
+			Q.Subtract(P, Q)
+			for i := w; i <= last; i += uint64(workers) {
+				select {
+				case <-found:
+					return
+				default:
+				}
+				// i * m <= max, so the check doesn't overflow.
+				if j, ok := t.lookup(Q); ok {
+					if j <= max-i*t.m {
+						once.Do(func() {
+							result = i*t.m + j
+							close(found)
+						})
+					}
+					return
+				}
+				if last-i < uint64(workers) {
+					return
+				}
+				Q.Subtract(Q, stride)
+			}
+		}(uint64(w))
+	}
+	wg.Wait()
+
+	select {
+	case <-found:
+		return result, nil
+	default:
+		return 0, ErrNotFound
+	}
+}
+
+// WriteTo writes the table to w, using 8 bytes per entry, so that it can be
+// loaded with ReadTable without recomputing it.
+func (t *Table) WriteTo(w io.Writer) (int64, error) {
+	keys := make([]uint64, t.m)
+	for k, j := range t.entries {
+		keys[j] = k
+	}
+	for k, js := range t.overflow {
+		for _, j := range js {
+			keys[j] = k
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	var n int64
+	nn, _ := bw.WriteString(tableMagic)
+	n += int64(nn)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], t.m)
+	nn, _ = bw.Write(buf[:])
+	n += int64(nn)
+	for _, k := range keys {
+		binary.LittleEndian.PutUint64(buf[:], k)
+		nn, _ = bw.Write(buf[:])
+		n += int64(nn)
+	}
+	return n, bw.Flush()
+}
+
+// ReadTable reads a table written by WriteTo.
+//
+// The table is trusted to be correct, since checking it would be as expensive
+// as recomputing it, but all solutions are checked before being returned.
+func ReadTable(r io.Reader) (*Table, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(tableMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != tableMagic {
+		return nil, errors.New("dlog: invalid table header")
+	}
+	var buf [8]byte
+	if _, err := io.ReadFull(br, buf[:]); err != nil {
+		return nil, err
+	}
+	m := binary.LittleEndian.Uint64(buf[:])
+	if m == 0 || m > 1<<32 {
+		return nil, errors.New("dlog: invalid table size")
+	}
+	keys := make([]uint64, 0, min(m, 1<<20))
+	for i := uint64(0); i < m; i++ {
+		if _, err := io.ReadFull(br, buf[:]); err != nil {
+			return nil, err
+		}
+		keys = append(keys, binary.LittleEndian.Uint64(buf[:]))
+	}
+	return tableFromKeys(keys), nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dlog
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+func lift(m uint64) *ristretto255.Element {
+	return ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(m))
+}
+
+func TestTable(t *testing.T) {
+	table, err := NewTable(1 << 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const max = 1 << 20
+
+	for _, m := range []uint64{0, 1, 1023, 1024, 1025, 123456, max - 1, max} {
+		for _, workers := range []int{1, 4} {
+			got, err := table.SolveParallel(lift(m), max, workers)
+			if err != nil {
+				t.Errorf("m = %d, workers = %d: %v", m, workers, err)
+				continue
+			}
+			if got != m {
+				t.Errorf("m = %d, workers = %d: got %d", m, workers, got)
+			}
+		}
+	}
+
+	if _, err := table.Solve(lift(max+1), max); err != ErrNotFound {
+		t.Errorf("out of range logarithm: got %v", err)
+	}
+	if _, err := table.Solve(lift(1<<40), max); err != ErrNotFound {
+		t.Errorf("large logarithm: got %v", err)
+	}
+}
+
+func TestTableFullRange(t *testing.T) {
+	// With one baby step and max = 2^64 - 1, the number of giant steps
+	// doesn't fit in a uint64.
+	table, err := NewTable(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, workers := range []int{1, 3} {
+		got, err := table.SolveParallel(lift(5), math.MaxUint64, workers)
+		if err != nil || got != 5 {
+			t.Errorf("workers = %d: got %d, %v", workers, got, err)
+		}
+	}
+}
+
+func TestTableSerialization(t *testing.T) {
+	table, err := NewTable(500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	n, err := table.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo returned %d, wrote %d bytes", n, buf.Len())
+	}
+	loaded, err := ReadTable(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.BabySteps() != 500 {
+		t.Errorf("loaded table has %d entries", loaded.BabySteps())
+	}
+	if m, err := loaded.Solve(lift(77777), 100000); err != nil || m != 77777 {
+		t.Errorf("loaded table: got %d, %v", m, err)
+	}
+
+	if _, err := ReadTable(bytes.NewReader(buf.Bytes()[:100])); err == nil {
+		t.Error("truncated table accepted")
+	}
+	if _, err := ReadTable(bytes.NewReader([]byte("not a table at all, really not"))); err == nil {
+		t.Error("garbage table accepted")
+	}
+}
+
+func TestKangaroo(t *testing.T) {
+	for _, tc := range []struct{ m, lo, hi uint64 }{
+		{5, 0, 10},
+		{123456, 0, 1 << 20},
+		{0, 0, 1 << 20},
+		{1<<20 - 1, 0, 1 << 20},
+		{1<<32 + 42, 1 << 32, 1<<32 + 1<<24},
+	} {
+		for _, workers := range []int{1, 3} {
+			got, err := KangarooParallel(lift(tc.m), tc.lo, tc.hi, workers)
+			if err != nil {
+				t.Errorf("m = %d, workers = %d: %v", tc.m, workers, err)
+				continue
+			}
+			if got != tc.m {
+				t.Errorf("m = %d, workers = %d: got %d", tc.m, workers, got)
+			}
+		}
+	}
+
+	if _, err := Kangaroo(lift(1<<21), 0, 1<<16); err != ErrNotFound {
+		t.Errorf("out of range logarithm: got %v", err)
+	}
+}
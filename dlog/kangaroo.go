@@ -0,0 +1,229 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dlog
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"sync"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// Kangaroo returns m such that P = m * G and lo <= m < hi, using Pollard's
+// kangaroo method. It takes about 2 * sqrt(hi - lo) Element additions and
+// encodings, and a negligible amount of memory.
+//
+// The kangaroo method is probabilistic. Each walk is cut off after about
+// 16 * sqrt(hi - lo) steps, so that Kangaroo returns ErrNotFound in bounded
+// time if the logarithm is not in the interval, but it also returns
+// ErrNotFound if the walks fail to meet in time even though the logarithm is
+// in the interval. Past the first sqrt(hi - lo) steps, each further
+// sqrt(hi - lo) steps make a miss about e^2 times less likely, so the chance
+// of that is below 2^-32.
+//
+// The walks are a deterministic function of P, lo, hi, and the number of
+// workers, so calling Kangaroo again with the same arguments returns the same
+// result. Callers that need a definite answer can retry with
+// KangarooParallel and a different number of workers, or fall back to
+// Table.Solve, which never misses a logarithm in range.
+func Kangaroo(P *ristretto255.Element, lo, hi uint64) (uint64, error) {
+	return KangarooParallel(P, lo, hi, 1)
+}
+
+// KangarooParallel is like Kangaroo, but runs workers pairs of tame and wild
+// kangaroos concurrently, with a linear speedup, following van Oorschot and
+// Wiener.
+func KangarooParallel(P *ristretto255.Element, lo, hi uint64, workers int) (uint64, error) {
+	if hi <= lo {
+		return 0, errors.New("dlog: empty interval")
+	}
+	if hi-lo > 1<<62 {
+		return 0, errors.New("dlog: interval is too large")
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	// Shift the problem to [0, width) to keep exponents small.
+	width := hi - lo
+	Q := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(lo))
+	Q.Subtract(P, Q)
+
+	if width <= 16 {
+		R := ristretto255.NewIdentityElement()
+		G := ristretto255.NewGeneratorElement()
+		for m := uint64(0); m < width; m++ {
+			if R.Equal(Q) == 1 {
+				return lo + m, nil
+			}
+			R.Add(R, G)
+		}
+		return 0, ErrNotFound
+	}
+
+	k := &kangaroos{width: width, workers: uint64(workers)}
+	k.setup()
+	m, err := k.run(Q)
+	if err != nil {
+		return 0, err
+	}
+	return lo + m, nil
+}
+
+type kangaroos struct {
+	width, workers uint64
+
+	jumps      []uint64
+	jumpPoints []*ristretto255.Element
+	dpMask     uint64
+	maxSteps   uint64
+
+	mu sync.Mutex
+	// traps maps the encodings of distinguished points to the exponent
+	// of the tame kangaroo that reached them.
+	traps map[[32]byte]uint64
+	// wild maps the encodings of distinguished points to the distance
+	// travelled by the wild kangaroo that reached them from Q.
+	wild map[[32]byte]uint64
+}
+
+func (k *kangaroos) setup() {
+	// The mean jump size should be about workers * sqrt(width) / 2. Jumps are
+	// powers of two, so their mean is (2^n - 1) / n.
+	sqrtWidth := uint64(math.Sqrt(float64(k.width))) + 1
+	target := k.workers * sqrtWidth / 2
+	n := 1
+	for ((uint64(1)<<n)-1)/uint64(n) < target && n < 62 {
+		n++
+	}
+	for i := 0; i < n; i++ {
+		j := uint64(1) << i
+		k.jumps = append(k.jumps, j)
+		k.jumpPoints = append(k.jumpPoints,
+			ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(j)))
+	}
+
+	// A point is distinguished with probability 2^-dpBits. The expected
+	// overshoot after a collision is about 2^dpBits steps per kangaroo.
+	dpBits := 0
+	if l := bits.Len64(sqrtWidth / (8 * k.workers)); l > 0 {
+		dpBits = l / 2
+	}
+	k.dpMask = 1<<dpBits - 1
+
+	// Bound the walk well past the expected 2 * sqrt(width) total steps.
+	k.maxSteps = 16*sqrtWidth/k.workers + 64<<dpBits
+	k.traps = make(map[[32]byte]uint64)
+	k.wild = make(map[[32]byte]uint64)
+}
+
+// step selects the jump from the encoding of the current point.
+func (k *kangaroos) step(enc [32]byte) (int, bool) {
+	h := sha512.Sum512(enc[:])
+	v := binary.LittleEndian.Uint64(h[:8])
+	return int(v % uint64(len(k.jumps))), (v>>32)&k.dpMask == 0
+}
+
+func (k *kangaroos) run(Q *ristretto255.Element) (uint64, error) {
+	var (
+		once   sync.Once
+		result uint64
+		done   = make(chan struct{})
+		wg     sync.WaitGroup
+	)
+	finish := func(m uint64) {
+		once.Do(func() {
+			result = m
+			close(done)
+		})
+	}
+
+	spacing := k.width / (2 * k.workers)
+	for w := uint64(0); w < k.workers; w++ {
+		wg.Add(1)
+		go func(w uint64) {
+			defer wg.Done()
+			// The tame kangaroo starts around the middle of the interval,
+			// and the wild one at Q + offset * G.
+			tamePos := k.width/2 + w*spacing/4
+			tame := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(tamePos))
+			wildDist := w * spacing / 4
+			wild := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(wildDist))
+			wild.Add(wild, Q)
+
+			for i := uint64(0); i < k.maxSteps; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				var enc [32]byte
+				copy(enc[:], tame.Bytes())
+				j, dp := k.step(enc)
+				if dp {
+					if m, ok := k.trap(enc, tamePos, true, Q); ok {
+						finish(m)
+						return
+					}
+				}
+				tame.Add(tame, k.jumpPoints[j])
+				tamePos += k.jumps[j]
+
+				copy(enc[:], wild.Bytes())
+				j, dp = k.step(enc)
+				if dp {
+					if m, ok := k.trap(enc, wildDist, false, Q); ok {
+						finish(m)
+						return
+					}
+				}
+				wild.Add(wild, k.jumpPoints[j])
+				wildDist += k.jumps[j]
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	select {
+	case <-done:
+		return result, nil
+	default:
+		return 0, ErrNotFound
+	}
+}
+
+// trap records a distinguished point, and returns the solution if a tame and
+// a wild kangaroo collided.
+func (k *kangaroos) trap(enc [32]byte, v uint64, tame bool, Q *ristretto255.Element) (uint64, bool) {
+	k.mu.Lock()
+	var m uint64
+	var ok bool
+	if tame {
+		k.traps[enc] = v
+		var d uint64
+		if d, ok = k.wild[enc]; ok {
+			m = v - d
+		}
+	} else {
+		k.wild[enc] = v
+		var pos uint64
+		if pos, ok = k.traps[enc]; ok {
+			m = pos - v
+		}
+	}
+	k.mu.Unlock()
+
+	if !ok || m >= k.width {
+		return 0, false
+	}
+	check := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(m))
+	return m, check.Equal(Q) == 1
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ecdh implements Diffie-Hellman key agreement over ristretto255,
+// with an API that mirrors the standard library crypto/ecdh package.
+//
+// The package-level GenerateKey, NewPrivateKey, and NewPublicKey functions
+// take the place of the methods of crypto/ecdh.Curve, and PrivateKey and
+// PublicKey have the same methods as their crypto/ecdh counterparts, except
+// for Curve.
+//
+// Private keys are canonical encodings of non-zero Scalars, and public keys
+// are canonical encodings of non-identity Elements. The shared secret is the
+// 32-byte encoding of the product of the private Scalar and the peer public
+// Element; it should be passed through a KDF before use as a key.
+package ecdh
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/element"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+const (
+	// PrivateKeySize is the size in bytes of an encoded PrivateKey.
+	PrivateKeySize = 32
+	// PublicKeySize is the size in bytes of an encoded PublicKey.
+	PublicKeySize = 32
+	// SharedSecretSize is the size in bytes of the output of ECDH.
+	SharedSecretSize = 32
+)
+
+// PrivateKey is a ristretto255 Diffie-Hellman private key.
+type PrivateKey struct {
+	k         *ristretto255.Scalar
+	publicKey *PublicKey
+}
+
+// PublicKey is a ristretto255 Diffie-Hellman public key.
+type PublicKey struct {
+	e *ristretto255.Element
+}
+
+// GenerateKey returns a new random PrivateKey, reading randomness from rand.
+// Unlike crypto/ecdh, if rand is nil, crypto/rand.Reader is used.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	k, err := scalar.RandomNonZero(rand)
+	if err != nil {
+		return nil, err
+	}
+	return newPrivateKey(k), nil
+}
+
+// NewPrivateKey decodes a PrivateKey from its PrivateKeySize-byte encoding.
+// Non-canonical and zero Scalars are rejected.
+func NewPrivateKey(key []byte) (*PrivateKey, error) {
+	if len(key) != PrivateKeySize {
+		return nil, errors.New("ecdh: invalid private key size")
+	}
+	k, err := ristretto255.NewScalar().SetCanonicalBytes(key)
+	if err != nil || scalar.IsZero(k) {
+		return nil, errors.New("ecdh: invalid private key")
+	}
+	return newPrivateKey(k), nil
+}
+
+func newPrivateKey(k *ristretto255.Scalar) *PrivateKey {
+	e := ristretto255.NewIdentityElement().ScalarBaseMult(k)
+	return &PrivateKey{k: k, publicKey: &PublicKey{e: e}}
+}
+
+// NewPublicKey decodes a PublicKey from its PublicKeySize-byte encoding.
+// Non-canonical encodings and the identity element are rejected.
+func NewPublicKey(key []byte) (*PublicKey, error) {
+	if len(key) != PublicKeySize {
+		return nil, errors.New("ecdh: invalid public key size")
+	}
+	e, err := element.DecodeNonIdentity(key)
+	if err != nil {
+		return nil, errors.New("ecdh: invalid public key")
+	}
+	return &PublicKey{e: e}, nil
+}
+
+// ECDH performs a Diffie-Hellman exchange and returns the SharedSecretSize-
+// byte shared secret. It returns an error if the result is the identity
+// element.
+func (k *PrivateKey) ECDH(remote *PublicKey) ([]byte, error) {
+	e := ristretto255.NewIdentityElement().ScalarMult(k.k, remote.e)
+	if element.IsIdentity(e) {
+		return nil, errors.New("ecdh: shared secret is the identity element")
+	}
+	return e.Bytes(), nil
+}
+
+// Bytes returns a copy of the PrivateKeySize-byte encoding of k.
+func (k *PrivateKey) Bytes() []byte {
+	return k.k.Bytes()
+}
+
+// Equal returns whether x represents the same private key as k.
+//
+// Note that there can be equivalent private keys with different encodings
+// which would return false from this check but behave the same way as
+// inputs to ECDH. This can't happen with this package, since all encodings
+// are canonical, but can with other crypto.PrivateKey implementations.
+func (k *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	xx, ok := x.(*PrivateKey)
+	if !ok {
+		return false
+	}
+	return k.k.Equal(xx.k) == 1
+}
+
+// Public implements the implicit interface of all standard library private
+// keys. See the docs of crypto.PrivateKey.
+func (k *PrivateKey) Public() crypto.PublicKey {
+	return k.PublicKey()
+}
+
+// PublicKey returns the public key corresponding to k.
+func (k *PrivateKey) PublicKey() *PublicKey {
+	return k.publicKey
+}
+
+// Bytes returns a copy of the PublicKeySize-byte encoding of k.
+func (k *PublicKey) Bytes() []byte {
+	return k.e.Bytes()
+}
+
+// Equal returns whether x represents the same public key as k.
+func (k *PublicKey) Equal(x crypto.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare(k.Bytes(), xx.Bytes()) == 1
+}
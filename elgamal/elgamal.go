@@ -0,0 +1,220 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package elgamal implements ElGamal encryption over ristretto255.
+//
+// A Ciphertext encrypting the Element M under the public key P = s * G is
+//
+//	(C1, C2) = (r * G, M + r * P)
+//
+// for a random Scalar r. Small integers can be encrypted "lifted", as
+// M = m * G, which makes the scheme additively homomorphic. Decrypting a
+// lifted ciphertext returns m * G, from which m can be recovered for small m
+// with the dlog package.
+package elgamal
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// CiphertextSize is the size in bytes of an encoded Ciphertext.
+const CiphertextSize = 64
+
+// PrivateKey is an ElGamal decryption key.
+type PrivateKey struct {
+	s  *ristretto255.Scalar
+	pk *PublicKey
+}
+
+// PublicKey is an ElGamal encryption key.
+type PublicKey struct {
+	P *ristretto255.Element
+}
+
+// GenerateKey returns a new PrivateKey, reading randomness from rand. If rand
+// is nil, crypto/rand.Reader is used.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	s, err := scalar.RandomNonZero(rand)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKey(s)
+}
+
+// NewPrivateKey returns the PrivateKey for the secret Scalar s, which must
+// not be zero.
+func NewPrivateKey(s *ristretto255.Scalar) (*PrivateKey, error) {
+	if scalar.IsZero(s) {
+		return nil, errors.New("elgamal: invalid private key")
+	}
+	P := ristretto255.NewIdentityElement().ScalarBaseMult(s)
+	return &PrivateKey{s: ristretto255.NewScalar().Set(s), pk: &PublicKey{P: P}}, nil
+}
+
+// Public returns the public key corresponding to priv.
+func (priv *PrivateKey) Public() *PublicKey {
+	return priv.pk
+}
+
+// Scalar returns the secret Scalar of priv.
+func (priv *PrivateKey) Scalar() *ristretto255.Scalar {
+	return ristretto255.NewScalar().Set(priv.s)
+}
+
+// NewPublicKey decodes a 32-byte public key encoding. The identity is
+// rejected.
+func NewPublicKey(b []byte) (*PublicKey, error) {
+	P, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b)
+	if err != nil {
+		return nil, errors.New("elgamal: invalid public key encoding")
+	}
+	if P.Equal(ristretto255.NewIdentityElement()) == 1 {
+		return nil, errors.New("elgamal: invalid public key")
+	}
+	return &PublicKey{P: P}, nil
+}
+
+// Bytes returns the 32-byte encoding of pk.
+func (pk *PublicKey) Bytes() []byte {
+	return pk.P.Bytes()
+}
+
+// Ciphertext is an ElGamal ciphertext. The zero value is not valid; use
+// NewCiphertext or one of the encryption methods.
+type Ciphertext struct {
+	C1, C2 *ristretto255.Element
+}
+
+// NewCiphertext returns a new Ciphertext set to the trivial encryption of
+// the identity, (O, O).
+func NewCiphertext() *Ciphertext {
+	return &Ciphertext{C1: ristretto255.NewIdentityElement(), C2: ristretto255.NewIdentityElement()}
+}
+
+// Encrypt returns an encryption of M under pk, and the randomness r used,
+// which can be used to prove statements about the ciphertext.
+func (pk *PublicKey) Encrypt(M *ristretto255.Element) (*Ciphertext, *ristretto255.Scalar, error) {
+	r, err := scalar.Random(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pk.EncryptWithRandomness(M, r), r, nil
+}
+
+// EncryptWithRandomness returns the encryption (r * G, M + r * P) of M under
+// pk. r must be uniformly random and never reused.
+func (pk *PublicKey) EncryptWithRandomness(M *ristretto255.Element, r *ristretto255.Scalar) *Ciphertext {
+	C1 := ristretto255.NewIdentityElement().ScalarBaseMult(r)
+	C2 := ristretto255.NewIdentityElement().ScalarMult(r, pk.P)
+	C2.Add(C2, M)
+	return &Ciphertext{C1: C1, C2: C2}
+}
+
+// EncryptUint64 returns a lifted encryption of m, that is an encryption of
+// m * G, and the randomness used.
+func (pk *PublicKey) EncryptUint64(m uint64) (*Ciphertext, *ristretto255.Scalar, error) {
+	M := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(m))
+	return pk.Encrypt(M)
+}
+
+// Decrypt returns the Element encrypted by c, M = C2 - s * C1. For lifted
+// ciphertexts, that is m * G.
+func (priv *PrivateKey) Decrypt(c *Ciphertext) *ristretto255.Element {
+	sC1 := ristretto255.NewIdentityElement().ScalarMult(priv.s, c.C1)
+	return sC1.Subtract(c.C2, sC1)
+}
+
+// Set sets c = x and returns c.
+func (c *Ciphertext) Set(x *Ciphertext) *Ciphertext {
+	c.C1 = ristretto255.NewIdentityElement().Set(x.C1)
+	c.C2 = ristretto255.NewIdentityElement().Set(x.C2)
+	return c
+}
+
+// Add sets c to the component-wise sum of x and y, an encryption of the sum
+// of their plaintexts, and returns c.
+func (c *Ciphertext) Add(x, y *Ciphertext) *Ciphertext {
+	C1 := ristretto255.NewIdentityElement().Add(x.C1, y.C1)
+	C2 := ristretto255.NewIdentityElement().Add(x.C2, y.C2)
+	c.C1, c.C2 = C1, C2
+	return c
+}
+
+// Subtract sets c to x - y, an encryption of the difference of their
+// plaintexts, and returns c.
+func (c *Ciphertext) Subtract(x, y *Ciphertext) *Ciphertext {
+	C1 := ristretto255.NewIdentityElement().Subtract(x.C1, y.C1)
+	C2 := ristretto255.NewIdentityElement().Subtract(x.C2, y.C2)
+	c.C1, c.C2 = C1, C2
+	return c
+}
+
+// ScalarMult sets c = s * x, an encryption of s times the plaintext of x, and
+// returns c.
+func (c *Ciphertext) ScalarMult(s *ristretto255.Scalar, x *Ciphertext) *Ciphertext {
+	C1 := ristretto255.NewIdentityElement().ScalarMult(s, x.C1)
+	C2 := ristretto255.NewIdentityElement().ScalarMult(s, x.C2)
+	c.C1, c.C2 = C1, C2
+	return c
+}
+
+// AddPlaintext sets c to x with M added to its plaintext, and returns c.
+func (c *Ciphertext) AddPlaintext(x *Ciphertext, M *ristretto255.Element) *Ciphertext {
+	C1 := ristretto255.NewIdentityElement().Set(x.C1)
+	C2 := ristretto255.NewIdentityElement().Add(x.C2, M)
+	c.C1, c.C2 = C1, C2
+	return c
+}
+
+// Rerandomize sets c to a fresh encryption under pk of the same plaintext as
+// x, by adding an encryption of the identity, and returns c and the
+// randomness used.
+func (c *Ciphertext) Rerandomize(pk *PublicKey, x *Ciphertext) (*Ciphertext, *ristretto255.Scalar, error) {
+	r, err := scalar.Random(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.RerandomizeWithRandomness(pk, x, r), r, nil
+}
+
+// RerandomizeWithRandomness sets c = x + (r * G, r * P) and returns c.
+func (c *Ciphertext) RerandomizeWithRandomness(pk *PublicKey, x *Ciphertext, r *ristretto255.Scalar) *Ciphertext {
+	return c.Add(x, pk.EncryptWithRandomness(ristretto255.NewIdentityElement(), r))
+}
+
+// Equal returns 1 if c and x are the same ciphertext, and 0 otherwise. Two
+// encryptions of the same plaintext with different randomness are not equal.
+func (c *Ciphertext) Equal(x *Ciphertext) int {
+	return c.C1.Equal(x.C1) & c.C2.Equal(x.C2)
+}
+
+// Bytes returns the 64-byte encoding of c, C1 || C2.
+func (c *Ciphertext) Bytes() []byte {
+	out := make([]byte, 0, CiphertextSize)
+	out = append(out, c.C1.Bytes()...)
+	return append(out, c.C2.Bytes()...)
+}
+
+// SetBytes sets c to the decoded 64-byte encoding b, and returns c. If b is
+// not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (c *Ciphertext) SetBytes(b []byte) (*Ciphertext, error) {
+	if len(b) != CiphertextSize {
+		return nil, errors.New("elgamal: invalid ciphertext length")
+	}
+	C1, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[:32])
+	if err != nil {
+		return nil, errors.New("elgamal: invalid ciphertext encoding")
+	}
+	C2, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[32:])
+	if err != nil {
+		return nil, errors.New("elgamal: invalid ciphertext encoding")
+	}
+	c.C1, c.C2 = C1, C2
+	return c, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package elgamal
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+func lift(m uint64) *ristretto255.Element {
+	return ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(m))
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	priv, err := GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sha512.Sum512([]byte("message"))
+	M, _ := ristretto255.NewIdentityElement().SetUniformBytes(h[:])
+
+	c, _, err := priv.Public().Encrypt(M)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if priv.Decrypt(c).Equal(M) != 1 {
+		t.Error("decryption failed")
+	}
+
+	decoded, err := NewCiphertext().SetBytes(c.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Equal(c) != 1 {
+		t.Error("encoding round-trip failed")
+	}
+
+	c2, _, err := priv.Public().Encrypt(M)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c2.Equal(c) == 1 {
+		t.Error("encryption is deterministic")
+	}
+
+	other, _ := GenerateKey(nil)
+	if other.Decrypt(c).Equal(M) == 1 {
+		t.Error("decryption with the wrong key succeeded")
+	}
+}
+
+func TestHomomorphism(t *testing.T) {
+	priv, _ := GenerateKey(nil)
+	pk := priv.Public()
+
+	a, _, _ := pk.EncryptUint64(1234)
+	b, _, _ := pk.EncryptUint64(5678)
+
+	sum := NewCiphertext().Add(a, b)
+	if priv.Decrypt(sum).Equal(lift(1234+5678)) != 1 {
+		t.Error("Add is not homomorphic")
+	}
+	diff := NewCiphertext().Subtract(b, a)
+	if priv.Decrypt(diff).Equal(lift(5678-1234)) != 1 {
+		t.Error("Subtract is not homomorphic")
+	}
+	prod := NewCiphertext().ScalarMult(scalar.FromUint64(3), a)
+	if priv.Decrypt(prod).Equal(lift(3*1234)) != 1 {
+		t.Error("ScalarMult is not homomorphic")
+	}
+	plus := NewCiphertext().AddPlaintext(a, lift(1))
+	if priv.Decrypt(plus).Equal(lift(1235)) != 1 {
+		t.Error("AddPlaintext failed")
+	}
+
+	// Operations can alias their arguments.
+	acc := NewCiphertext().Set(a)
+	acc.Add(acc, acc)
+	if priv.Decrypt(acc).Equal(lift(2468)) != 1 {
+		t.Error("aliased Add failed")
+	}
+
+	re, _, err := NewCiphertext().Rerandomize(pk, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if re.Equal(a) == 1 {
+		t.Error("rerandomized ciphertext is unchanged")
+	}
+	if priv.Decrypt(re).Equal(lift(1234)) != 1 {
+		t.Error("rerandomization changed the plaintext")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	priv, _ := GenerateKey(nil)
+	pk, err := NewPublicKey(priv.Public().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pk.Bytes(), priv.Public().Bytes()) {
+		t.Error("public key round-trip failed")
+	}
+	if _, err := NewPublicKey(ristretto255.NewIdentityElement().Bytes()); err == nil {
+		t.Error("identity public key accepted")
+	}
+	if _, err := NewPrivateKey(ristretto255.NewScalar()); err == nil {
+		t.Error("zero private key accepted")
+	}
+	if _, err := NewCiphertext().SetBytes(make([]byte, 63)); err == nil {
+		t.Error("short ciphertext accepted")
+	}
+}
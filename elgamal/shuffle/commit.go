@@ -0,0 +1,121 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shuffle
+
+import (
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/bulletproofs"
+	"github.com/gtank/ristretto255/elgamal"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// commitmentKey is a key for generalized Pedersen commitments to vectors of
+// up to len(G) Scalars, com(a; r) = <a, G> + r * H.
+type commitmentKey struct {
+	G []*ristretto255.Element
+	H *ristretto255.Element
+}
+
+func newCommitmentKey(n int) *commitmentKey {
+	return &commitmentKey{
+		G: bulletproofs.NewBulletproofGens(n).G,
+		H: bulletproofs.DefaultPedersenGens().BBlinding,
+	}
+}
+
+// commit returns com(a; r) in constant time.
+func (ck *commitmentKey) commit(a []*ristretto255.Scalar, r *ristretto255.Scalar) *ristretto255.Element {
+	scalars := append(append(make([]*ristretto255.Scalar, 0, len(a)+1), a...), r)
+	points := append(append(make([]*ristretto255.Element, 0, len(a)+1), ck.G[:len(a)]...), ck.H)
+	return ristretto255.NewIdentityElement().MultiScalarMult(scalars, points)
+}
+
+// check returns whether sum(coeffs[i] * comms[i]) = com(a; r).
+func (ck *commitmentKey) check(coeffs []*ristretto255.Scalar, comms []*ristretto255.Element, a []*ristretto255.Scalar, r *ristretto255.Scalar) bool {
+	scalars := append([]*ristretto255.Scalar(nil), coeffs...)
+	points := append([]*ristretto255.Element(nil), comms...)
+	for i := range a {
+		scalars = append(scalars, ristretto255.NewScalar().Negate(a[i]))
+		points = append(points, ck.G[i])
+	}
+	scalars = append(scalars, ristretto255.NewScalar().Negate(r))
+	points = append(points, ck.H)
+	return isIdentity(ristretto255.NewIdentityElement().VarTimeMultiScalarMult(scalars, points))
+}
+
+func isIdentity(e *ristretto255.Element) bool {
+	return e.Equal(ristretto255.NewIdentityElement()) == 1
+}
+
+// linearCombination returns sum(coeffs[i] * comms[i]) in variable time.
+func linearCombination(coeffs []*ristretto255.Scalar, comms []*ristretto255.Element) *ristretto255.Element {
+	return ristretto255.NewIdentityElement().VarTimeMultiScalarMult(coeffs, comms)
+}
+
+// ciphertextMultiExp returns sum(s[i] * c[i]) component-wise, in constant
+// time.
+func ciphertextMultiExp(s []*ristretto255.Scalar, c []*elgamal.Ciphertext) *elgamal.Ciphertext {
+	C1 := make([]*ristretto255.Element, len(c))
+	C2 := make([]*ristretto255.Element, len(c))
+	for i := range c {
+		C1[i], C2[i] = c[i].C1, c[i].C2
+	}
+	return &elgamal.Ciphertext{
+		C1: ristretto255.NewIdentityElement().MultiScalarMult(s, C1),
+		C2: ristretto255.NewIdentityElement().MultiScalarMult(s, C2),
+	}
+}
+
+func zeroVector(n int) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, n)
+	for i := range out {
+		out[i] = ristretto255.NewScalar()
+	}
+	return out
+}
+
+func randomVector(n int) ([]*ristretto255.Scalar, error) {
+	out := make([]*ristretto255.Scalar, n)
+	for i := range out {
+		s, err := scalar.Random(nil)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+// combine returns sum(coeffs[i] * vs[i]).
+func combine(coeffs []*ristretto255.Scalar, vs [][]*ristretto255.Scalar) []*ristretto255.Scalar {
+	out := zeroVector(len(vs[0]))
+	tmp := ristretto255.NewScalar()
+	for i, v := range vs {
+		for j := range v {
+			out[j].Add(out[j], tmp.Multiply(coeffs[i], v[j]))
+		}
+	}
+	return out
+}
+
+func hadamard(a, b []*ristretto255.Scalar) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, len(a))
+	for i := range a {
+		out[i] = ristretto255.NewScalar().Multiply(a[i], b[i])
+	}
+	return out
+}
+
+// bilinear returns the bilinear map a * b = sum(a_j * b_j * y^(j+1)) used by
+// the zero argument, given yPows = [y, y^2, ..., y^n].
+func bilinear(a, b, yPows []*ristretto255.Scalar) *ristretto255.Scalar {
+	out := ristretto255.NewScalar()
+	tmp := ristretto255.NewScalar()
+	for j := range a {
+		tmp.Multiply(a[j], b[j])
+		out.Add(out, tmp.Multiply(tmp, yPows[j]))
+	}
+	return out
+}
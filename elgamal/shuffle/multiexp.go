@@ -0,0 +1,136 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shuffle
+
+import (
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/elgamal"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+// multiExpArgument shows that C = E(0; rho) + sum_i <a_i, C_i> for the m
+// rows of ciphertexts C_i and the m columns a_i committed to in c_A
+// (Bayer–Groth, Section 4).
+type multiExpArgument struct {
+	cA0          *ristretto255.Element
+	cB           []*ristretto255.Element // c_B_k for k = 0, ..., 2m-1 except m
+	E            []*elgamal.Ciphertext   // E_k for k = 0, ..., 2m-1 except m
+	a            []*ristretto255.Scalar
+	r, b, s, tau *ristretto255.Scalar
+}
+
+func proveMultiExp(t *transcript.Transcript, ck *commitmentKey, pk *elgamal.PublicKey, C [][]*elgamal.Ciphertext, a [][]*ristretto255.Scalar, r []*ristretto255.Scalar, rho *ristretto255.Scalar) (*multiExpArgument, error) {
+	m, n := len(a), len(a[0])
+	a0, err := randomVector(n)
+	if err != nil {
+		return nil, err
+	}
+	rnd, err := randomVector(6*m + 1)
+	if err != nil {
+		return nil, err
+	}
+	r0, bk, sk, tauk := rnd[0], rnd[1:2*m+1], rnd[2*m+1:4*m+1], rnd[4*m+1:]
+	bk[m], sk[m], tauk[m] = ristretto255.NewScalar(), ristretto255.NewScalar(), rho
+
+	A := append([][]*ristretto255.Scalar{a0}, a...)
+	R := append([]*ristretto255.Scalar{r0}, r...)
+
+	proof := &multiExpArgument{cA0: ck.commit(a0, r0)}
+	t.AppendElement("c_A0", proof.cA0)
+	G := ristretto255.NewGeneratorElement()
+	for k := 0; k < 2*m; k++ {
+		if k == m {
+			continue
+		}
+		// E_k = E(b_k * G; tau_k) + sum of <a_j, C_i> over j = k - m + i.
+		var scalars []*ristretto255.Scalar
+		var cts []*elgamal.Ciphertext
+		for i := 1; i <= m; i++ {
+			j := k - m + i
+			if j < 0 || j > m {
+				continue
+			}
+			scalars = append(scalars, A[j]...)
+			cts = append(cts, C[i-1]...)
+		}
+		Ek := ciphertextMultiExp(scalars, cts)
+		Ek.Add(Ek, pk.EncryptWithRandomness(ristretto255.NewIdentityElement().ScalarMult(bk[k], G), tauk[k]))
+		cBk := ck.commit(bk[k:k+1], sk[k])
+
+		t.AppendElement("c_B", cBk)
+		t.AppendElement("E_1", Ek.C1)
+		t.AppendElement("E_2", Ek.C2)
+		proof.cB = append(proof.cB, cBk)
+		proof.E = append(proof.E, Ek)
+	}
+
+	x := t.ChallengeScalar("x")
+	xPows := scalar.Powers(x, 2*m)
+
+	proof.a = combine(xPows[:m+1], A)
+	proof.r = scalar.InnerProduct(xPows[:m+1], R)
+	proof.b = scalar.InnerProduct(xPows, bk)
+	proof.s = scalar.InnerProduct(xPows, sk)
+	proof.tau = scalar.InnerProduct(xPows, tauk)
+	return proof, nil
+}
+
+func verifyMultiExp(t *transcript.Transcript, ck *commitmentKey, pk *elgamal.PublicKey, C [][]*elgamal.Ciphertext, target *elgamal.Ciphertext, cA []*ristretto255.Element, p *multiExpArgument) bool {
+	m, n := len(cA), len(ck.G)
+	if len(p.cB) != 2*m-1 || len(p.E) != 2*m-1 || len(p.a) != n {
+		return false
+	}
+	t.AppendElement("c_A0", p.cA0)
+	for k := range p.cB {
+		t.AppendElement("c_B", p.cB[k])
+		t.AppendElement("E_1", p.E[k].C1)
+		t.AppendElement("E_2", p.E[k].C2)
+	}
+
+	x := t.ChallengeScalar("x")
+	xPows := scalar.Powers(x, 2*m)
+	// c_B_m = com(0; 0) is the identity, and E_m is the target.
+	xPowsK := append(append([]*ristretto255.Scalar(nil), xPows[:m]...), xPows[m+1:]...)
+
+	if !ck.check(xPows[:m+1], append([]*ristretto255.Element{p.cA0}, cA...), p.a, p.r) ||
+		!ck.check(xPowsK, p.cB, []*ristretto255.Scalar{p.b}, p.s) {
+		return false
+	}
+
+	// Check both components of
+	//
+	//   sum_k x^k E_k = E(b * G; tau) + sum_i x^(m-i) <a, C_i>
+	//
+	// at once, as the first plus w times the second for a random w.
+	w, err := scalar.Random(nil)
+	if err != nil {
+		return false
+	}
+	var scalars []*ristretto255.Scalar
+	var points []*ristretto255.Element
+	add := func(s *ristretto255.Scalar, c *elgamal.Ciphertext) {
+		scalars = append(scalars, s, ristretto255.NewScalar().Multiply(w, s))
+		points = append(points, c.C1, c.C2)
+	}
+	for k := range p.E {
+		add(xPowsK[k], p.E[k])
+	}
+	add(xPows[m], target)
+	for i := 1; i <= m; i++ {
+		for l := range C[i-1] {
+			s := ristretto255.NewScalar().Multiply(xPows[m-i], p.a[l])
+			add(s.Negate(s), C[i-1][l])
+		}
+	}
+	// E(b * G; tau) = (tau * G, b * G + tau * P).
+	gScalar := ristretto255.NewScalar().Multiply(w, p.b)
+	gScalar.Add(gScalar, p.tau)
+	pScalar := ristretto255.NewScalar().Multiply(w, p.tau)
+	scalars = append(scalars, gScalar.Negate(gScalar), pScalar.Negate(pScalar))
+	points = append(points, ristretto255.NewGeneratorElement(), pk.P)
+
+	return isIdentity(linearCombination(scalars, points))
+}
@@ -0,0 +1,342 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shuffle
+
+import (
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+// productArgument shows that the entries of the m columns committed to in
+// c_A multiply to a public value b (Bayer–Groth, Section 5.1). For m > 1 it
+// commits to the Hadamard product of the columns, and proves it with a
+// Hadamard product argument and a single value product argument.
+type productArgument struct {
+	cb       *ristretto255.Element // nil if m = 1
+	hadamard *hadamardArgument     // nil if m = 1
+	svp      *singleValueArgument
+}
+
+func proveProduct(t *transcript.Transcript, ck *commitmentKey, a [][]*ristretto255.Scalar, r []*ristretto255.Scalar) (*productArgument, error) {
+	if len(a) == 1 {
+		svp, err := proveSingleValue(t, ck, a[0], r[0])
+		if err != nil {
+			return nil, err
+		}
+		return &productArgument{svp: svp}, nil
+	}
+
+	b := a[0]
+	for _, ai := range a[1:] {
+		b = hadamard(b, ai)
+	}
+	s, err := scalar.Random(nil)
+	if err != nil {
+		return nil, err
+	}
+	cb := ck.commit(b, s)
+	t.AppendElement("c_b", cb)
+
+	h, err := proveHadamard(t, ck, a, r, b, s)
+	if err != nil {
+		return nil, err
+	}
+	svp, err := proveSingleValue(t, ck, b, s)
+	if err != nil {
+		return nil, err
+	}
+	return &productArgument{cb: cb, hadamard: h, svp: svp}, nil
+}
+
+func verifyProduct(t *transcript.Transcript, ck *commitmentKey, cA []*ristretto255.Element, b *ristretto255.Scalar, p *productArgument) bool {
+	if len(cA) == 1 {
+		return p.cb == nil && p.hadamard == nil && verifySingleValue(t, ck, cA[0], b, p.svp)
+	}
+	if p.cb == nil || p.hadamard == nil {
+		return false
+	}
+	t.AppendElement("c_b", p.cb)
+	return verifyHadamard(t, ck, cA, p.cb, p.hadamard) &&
+		verifySingleValue(t, ck, p.cb, b, p.svp)
+}
+
+// hadamardArgument shows that c_b commits to the entry-wise product of the
+// m columns committed to in c_A (Bayer–Groth, Section 5.1), by reducing to a
+// zero argument over the partial products b_1 = a_1, b_i = b_(i-1) ∘ a_i.
+type hadamardArgument struct {
+	cB   []*ristretto255.Element // c_B_2, ..., c_B_(m-1)
+	zero *zeroArgument
+}
+
+func proveHadamard(t *transcript.Transcript, ck *commitmentKey, a [][]*ristretto255.Scalar, r []*ristretto255.Scalar, b []*ristretto255.Scalar, s *ristretto255.Scalar) (*hadamardArgument, error) {
+	m, n := len(a), len(b)
+	bs := make([][]*ristretto255.Scalar, m)
+	ss := make([]*ristretto255.Scalar, m)
+	bs[0], ss[0] = a[0], r[0]
+	bs[m-1], ss[m-1] = b, s
+
+	proof := &hadamardArgument{}
+	for i := 1; i < m-1; i++ {
+		var err error
+		if ss[i], err = scalar.Random(nil); err != nil {
+			return nil, err
+		}
+		bs[i] = hadamard(bs[i-1], a[i])
+		cBi := ck.commit(bs[i], ss[i])
+		t.AppendElement("c_B", cBi)
+		proof.cB = append(proof.cB, cBi)
+	}
+
+	x := t.ChallengeScalar("x")
+	y := t.ChallengeScalar("y")
+	xPows := scalar.Powers(x, m)
+	yPows := scalar.Powers(y, n+1)[1:]
+
+	// The zero argument statement is
+	//
+	//   sum_(i=1)^(m-1) a_(i+1) * (x^i b_i) + (-1) * (sum_(i=1)^(m-1) x^i b_(i+1)) = 0.
+	minusOne := make([]*ristretto255.Scalar, n)
+	for j := range minusOne {
+		minusOne[j] = ristretto255.NewScalar().Negate(scalar.One())
+	}
+	za := append(append([][]*ristretto255.Scalar(nil), a[1:]...), minusOne)
+	zr := append(append([]*ristretto255.Scalar(nil), r[1:]...), ristretto255.NewScalar())
+	zb := make([][]*ristretto255.Scalar, m)
+	zs := make([]*ristretto255.Scalar, m)
+	for i := 1; i < m; i++ {
+		zb[i-1] = combine(xPows[i:i+1], bs[i-1:i])
+		zs[i-1] = ristretto255.NewScalar().Multiply(xPows[i], ss[i-1])
+	}
+	zb[m-1] = combine(xPows[1:], bs[1:])
+	zs[m-1] = scalar.InnerProduct(xPows[1:], ss[1:])
+
+	zero, err := proveZero(t, ck, yPows, za, zr, zb, zs)
+	if err != nil {
+		return nil, err
+	}
+	proof.zero = zero
+	return proof, nil
+}
+
+func verifyHadamard(t *transcript.Transcript, ck *commitmentKey, cA []*ristretto255.Element, cb *ristretto255.Element, p *hadamardArgument) bool {
+	m, n := len(cA), len(ck.G)
+	if len(p.cB) != m-2 || p.zero == nil {
+		return false
+	}
+	for _, cBi := range p.cB {
+		t.AppendElement("c_B", cBi)
+	}
+	cB := append(append([]*ristretto255.Element{cA[0]}, p.cB...), cb)
+
+	x := t.ChallengeScalar("x")
+	y := t.ChallengeScalar("y")
+	xPows := scalar.Powers(x, m)
+	yPows := scalar.Powers(y, n+1)[1:]
+
+	// c_(-1) = com(-1, ..., -1; 0) = -sum(G_j).
+	cMinusOne := ristretto255.NewIdentityElement()
+	for _, G := range ck.G {
+		cMinusOne.Subtract(cMinusOne, G)
+	}
+	zcA := append(append([]*ristretto255.Element(nil), cA[1:]...), cMinusOne)
+	zcB := make([]*ristretto255.Element, m)
+	for i := 1; i < m; i++ {
+		zcB[i-1] = ristretto255.NewIdentityElement().ScalarMult(xPows[i], cB[i-1])
+	}
+	zcB[m-1] = linearCombination(xPows[1:], cB[1:])
+
+	return verifyZero(t, ck, yPows, zcA, zcB, p.zero)
+}
+
+// zeroArgument shows that sum(a_i * b_i) = 0 for the m vectors committed to
+// in c_A and c_B, where * is the bilinear map defined by y (Bayer–Groth,
+// Section 5.2).
+type zeroArgument struct {
+	cA0, cBm1 *ristretto255.Element
+	cD        []*ristretto255.Element // c_D_k for k = 0, ..., 2m except m+1
+	a, b      []*ristretto255.Scalar
+	r, s, t   *ristretto255.Scalar
+}
+
+func proveZero(t *transcript.Transcript, ck *commitmentKey, yPows []*ristretto255.Scalar, a [][]*ristretto255.Scalar, r []*ristretto255.Scalar, b [][]*ristretto255.Scalar, s []*ristretto255.Scalar) (*zeroArgument, error) {
+	m, n := len(a), len(a[0])
+	a0, err := randomVector(n)
+	if err != nil {
+		return nil, err
+	}
+	bm1, err := randomVector(n)
+	if err != nil {
+		return nil, err
+	}
+	rnd, err := randomVector(2*m + 3)
+	if err != nil {
+		return nil, err
+	}
+	r0, sm1, tk := rnd[0], rnd[1], rnd[2:]
+	tk[m+1] = ristretto255.NewScalar()
+
+	// A holds a_0, ..., a_m and B holds b_1, ..., b_(m+1).
+	A := append([][]*ristretto255.Scalar{a0}, a...)
+	R := append([]*ristretto255.Scalar{r0}, r...)
+	B := append(append([][]*ristretto255.Scalar(nil), b...), bm1)
+	S := append(append([]*ristretto255.Scalar(nil), s...), sm1)
+
+	// d_k is the coefficient of x^k in a * b, which collects the terms
+	// a_i * b_j with k = m + 1 + i - j. The diagonal i = j is the statement,
+	// so d_(m+1) = 0.
+	d := zeroVector(2*m + 1)
+	for i := 0; i <= m; i++ {
+		for j := 0; j <= m; j++ {
+			k := m + i - j
+			d[k].Add(d[k], bilinear(A[i], B[j], yPows))
+		}
+	}
+
+	proof := &zeroArgument{cA0: ck.commit(a0, r0), cBm1: ck.commit(bm1, sm1)}
+	t.AppendElement("c_A0", proof.cA0)
+	t.AppendElement("c_Bm+1", proof.cBm1)
+	for k := range d {
+		if k == m+1 {
+			continue
+		}
+		cDk := ck.commit(d[k:k+1], tk[k])
+		t.AppendElement("c_D", cDk)
+		proof.cD = append(proof.cD, cDk)
+	}
+
+	x := t.ChallengeScalar("x")
+	xPows := scalar.Powers(x, 2*m+1)
+	xPowsRev := reverse(xPows[:m+1])
+
+	proof.a = combine(xPows[:m+1], A)
+	proof.r = scalar.InnerProduct(xPows[:m+1], R)
+	proof.b = combine(xPowsRev, B)
+	proof.s = scalar.InnerProduct(xPowsRev, S)
+	proof.t = scalar.InnerProduct(xPows, tk)
+	return proof, nil
+}
+
+func verifyZero(t *transcript.Transcript, ck *commitmentKey, yPows []*ristretto255.Scalar, cA, cB []*ristretto255.Element, p *zeroArgument) bool {
+	m, n := len(cA), len(ck.G)
+	if len(p.cD) != 2*m || len(p.a) != n || len(p.b) != n {
+		return false
+	}
+	t.AppendElement("c_A0", p.cA0)
+	t.AppendElement("c_Bm+1", p.cBm1)
+	for _, cDk := range p.cD {
+		t.AppendElement("c_D", cDk)
+	}
+
+	x := t.ChallengeScalar("x")
+	xPows := scalar.Powers(x, 2*m+1)
+	xPowsRev := reverse(xPows[:m+1])
+	// c_D_(m+1) = com(0; 0) is the identity, so its power is dropped.
+	xPowsD := append(append([]*ristretto255.Scalar(nil), xPows[:m+1]...), xPows[m+2:]...)
+
+	ab := bilinear(p.a, p.b, yPows)
+	return ck.check(xPows[:m+1], append([]*ristretto255.Element{p.cA0}, cA...), p.a, p.r) &&
+		ck.check(xPowsRev, append(append([]*ristretto255.Element(nil), cB...), p.cBm1), p.b, p.s) &&
+		ck.check(xPowsD, p.cD, []*ristretto255.Scalar{ab}, p.t)
+}
+
+func reverse(s []*ristretto255.Scalar) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, len(s))
+	for i := range s {
+		out[len(s)-1-i] = s[i]
+	}
+	return out
+}
+
+// singleValueArgument shows that the entries of the vector committed to in
+// c_a multiply to a public value b (Bayer–Groth, Section 5.3).
+type singleValueArgument struct {
+	cd, cLowerDelta, cUpperDelta *ristretto255.Element
+	aTilde, bTilde               []*ristretto255.Scalar
+	rTilde, sTilde               *ristretto255.Scalar
+}
+
+func proveSingleValue(t *transcript.Transcript, ck *commitmentKey, a []*ristretto255.Scalar, r *ristretto255.Scalar) (*singleValueArgument, error) {
+	n := len(a)
+	b := make([]*ristretto255.Scalar, n)
+	b[0] = a[0]
+	for i := 1; i < n; i++ {
+		b[i] = ristretto255.NewScalar().Multiply(b[i-1], a[i])
+	}
+
+	d, err := randomVector(n)
+	if err != nil {
+		return nil, err
+	}
+	delta, err := randomVector(n)
+	if err != nil {
+		return nil, err
+	}
+	delta[0], delta[n-1] = d[0], ristretto255.NewScalar()
+	rnd, err := randomVector(3)
+	if err != nil {
+		return nil, err
+	}
+	rd, s1, sx := rnd[0], rnd[1], rnd[2]
+
+	lower := make([]*ristretto255.Scalar, n-1)
+	upper := make([]*ristretto255.Scalar, n-1)
+	tmp := ristretto255.NewScalar()
+	for i := 0; i < n-1; i++ {
+		lower[i] = ristretto255.NewScalar().Multiply(delta[i], d[i+1])
+		lower[i].Negate(lower[i])
+		upper[i] = ristretto255.NewScalar().Subtract(delta[i+1], tmp.Multiply(a[i+1], delta[i]))
+		upper[i].Subtract(upper[i], tmp.Multiply(b[i], d[i+1]))
+	}
+
+	proof := &singleValueArgument{
+		cd:          ck.commit(d, rd),
+		cLowerDelta: ck.commit(lower, s1),
+		cUpperDelta: ck.commit(upper, sx),
+	}
+	t.AppendElement("c_d", proof.cd)
+	t.AppendElement("c_delta", proof.cLowerDelta)
+	t.AppendElement("c_Delta", proof.cUpperDelta)
+
+	x := t.ChallengeScalar("x")
+	proof.aTilde = make([]*ristretto255.Scalar, n)
+	proof.bTilde = make([]*ristretto255.Scalar, n)
+	for i := range a {
+		proof.aTilde[i] = ristretto255.NewScalar().Multiply(x, a[i])
+		proof.aTilde[i].Add(proof.aTilde[i], d[i])
+		proof.bTilde[i] = ristretto255.NewScalar().Multiply(x, b[i])
+		proof.bTilde[i].Add(proof.bTilde[i], delta[i])
+	}
+	proof.rTilde = ristretto255.NewScalar().Multiply(x, r)
+	proof.rTilde.Add(proof.rTilde, rd)
+	proof.sTilde = ristretto255.NewScalar().Multiply(x, sx)
+	proof.sTilde.Add(proof.sTilde, s1)
+	return proof, nil
+}
+
+func verifySingleValue(t *transcript.Transcript, ck *commitmentKey, ca *ristretto255.Element, b *ristretto255.Scalar, p *singleValueArgument) bool {
+	n := len(ck.G)
+	if p == nil || len(p.aTilde) != n || len(p.bTilde) != n {
+		return false
+	}
+	t.AppendElement("c_d", p.cd)
+	t.AppendElement("c_delta", p.cLowerDelta)
+	t.AppendElement("c_Delta", p.cUpperDelta)
+
+	x := t.ChallengeScalar("x")
+	one := scalar.One()
+	v := make([]*ristretto255.Scalar, n-1)
+	tmp := ristretto255.NewScalar()
+	for i := 0; i < n-1; i++ {
+		v[i] = ristretto255.NewScalar().Multiply(x, p.bTilde[i+1])
+		v[i].Subtract(v[i], tmp.Multiply(p.bTilde[i], p.aTilde[i+1]))
+	}
+	xb := ristretto255.NewScalar().Multiply(x, b)
+
+	return ck.check([]*ristretto255.Scalar{x, one}, []*ristretto255.Element{ca, p.cd}, p.aTilde, p.rTilde) &&
+		ck.check([]*ristretto255.Scalar{x, one}, []*ristretto255.Element{p.cUpperDelta, p.cLowerDelta}, v, p.sTilde) &&
+		p.bTilde[0].Equal(p.aTilde[0]) == 1 &&
+		p.bTilde[n-1].Equal(xb) == 1
+}
@@ -0,0 +1,458 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package shuffle implements the verifiable shuffle of ElGamal ciphertexts of
+// Bayer and Groth, "Efficient Zero-Knowledge Argument for Correctness of a
+// Shuffle" (EUROCRYPT 2012).
+//
+// A Proof shows that a list of N ciphertexts is a permutation of the
+// rerandomizations of another, without revealing the permutation. The
+// ciphertexts are arranged as an m × n matrix with m = floor(sqrt(N)) and
+// n = ceil(N / m), padded with trivial encryptions of the identity, so proofs
+// have O(sqrt(N)) Elements and Scalars for any N. Verification is dominated
+// by variable time multi-scalar multiplications over the input and output
+// Elements.
+package shuffle
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"slices"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/elgamal"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+var (
+	errVerification = errors.New("shuffle: proof verification failed")
+	errFormat       = errors.New("shuffle: malformed proof encoding")
+)
+
+// Witness is the secret information of a shuffle.
+type Witness struct {
+	// Permutation maps output positions to input positions: out[i] is a
+	// rerandomization of in[Permutation[i]].
+	Permutation []int
+	// Randomness[i] is the Scalar out[i] was rerandomized with.
+	Randomness []*ristretto255.Scalar
+}
+
+// Shuffle returns a random permutation of the rerandomizations of cts under
+// pk, and the Witness to prove it with.
+func Shuffle(pk *elgamal.PublicKey, cts []*elgamal.Ciphertext) ([]*elgamal.Ciphertext, *Witness, error) {
+	w := &Witness{
+		Permutation: make([]int, len(cts)),
+		Randomness:  make([]*ristretto255.Scalar, len(cts)),
+	}
+	for i := range w.Permutation {
+		w.Permutation[i] = i
+	}
+	for i := len(cts) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, nil, err
+		}
+		k := int(j.Int64())
+		w.Permutation[i], w.Permutation[k] = w.Permutation[k], w.Permutation[i]
+	}
+	out := make([]*elgamal.Ciphertext, len(cts))
+	for i, j := range w.Permutation {
+		var err error
+		if out[i], w.Randomness[i], err = elgamal.NewCiphertext().Rerandomize(pk, cts[j]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return out, w, nil
+}
+
+// Proof is a shuffle argument for N ciphertexts, padded to m * n.
+type Proof struct {
+	m, n     int
+	cA, cB   []*ristretto255.Element
+	product  *productArgument
+	multiExp *multiExpArgument
+}
+
+// dimensions returns the m × n shape of the ciphertext matrix for N
+// ciphertexts, with m = floor(sqrt(N)) and n = ceil(N / m). Then m <= n and
+// m * n < N + 2 * sqrt(N), so at most O(sqrt(N)) padding ciphertexts are
+// needed even if N is prime.
+func dimensions(N int) (m, n int, err error) {
+	if N < 2 {
+		return 0, 0, errors.New("shuffle: at least two ciphertexts are required")
+	}
+	if N > 1<<32 {
+		return 0, 0, errors.New("shuffle: too many ciphertexts")
+	}
+	m = 1
+	for (m+1)*(m+1) <= N {
+		m++
+	}
+	return m, (N + m - 1) / m, nil
+}
+
+// pad returns cts followed by trivial encryptions of the identity, (O, O),
+// up to a total of N ciphertexts. The input and output are padded the same
+// way, and the prover maps each padding ciphertext to itself with zero
+// randomness. A prover that maps padding to real ciphertexts instead can
+// only swap encryptions of the identity, so the shuffled plaintexts are
+// unchanged.
+func pad(cts []*elgamal.Ciphertext, N int) []*elgamal.Ciphertext {
+	out := append(make([]*elgamal.Ciphertext, 0, N), cts...)
+	for len(out) < N {
+		out = append(out, elgamal.NewCiphertext())
+	}
+	return out
+}
+
+func absorbStatement(t *transcript.Transcript, pk *elgamal.PublicKey, in, out []*elgamal.Ciphertext) {
+	t.AppendMessage("dom-sep", []byte("shuffle v1"))
+	t.AppendElement("pk", pk.P)
+	t.AppendUint64("N", uint64(len(in)))
+	for _, cts := range [][]*elgamal.Ciphertext{in, out} {
+		buf := make([]byte, 0, elgamal.CiphertextSize*len(cts))
+		for _, c := range cts {
+			buf = append(buf, c.Bytes()...)
+		}
+		t.AppendMessage("ciphertexts", buf)
+	}
+}
+
+// matrix splits v into m columns of n entries.
+func matrix[T any](v []T, m, n int) [][]T {
+	out := make([][]T, m)
+	for j := range out {
+		out[j] = v[j*n : (j+1)*n]
+	}
+	return out
+}
+
+// Prove returns a proof that out is a shuffle of in under pk, according to w.
+//
+// Proving time is dominated by O(N * sqrt(N)) constant time exponentiations.
+func Prove(t *transcript.Transcript, pk *elgamal.PublicKey, in, out []*elgamal.Ciphertext, w *Witness) (*Proof, error) {
+	N := len(in)
+	m, n, err := dimensions(N)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) != N || len(w.Permutation) != N || len(w.Randomness) != N {
+		return nil, errors.New("shuffle: mismatched lengths")
+	}
+	seen := make([]bool, N)
+	for i, j := range w.Permutation {
+		if j < 0 || j >= N || seen[j] {
+			return nil, errors.New("shuffle: invalid permutation")
+		}
+		seen[j] = true
+		c := elgamal.NewCiphertext().RerandomizeWithRandomness(pk, in[j], w.Randomness[i])
+		if c.Equal(out[i]) != 1 {
+			return nil, errors.New("shuffle: witness does not match ciphertexts")
+		}
+	}
+
+	ck := newCommitmentKey(n)
+	absorbStatement(t, pk, in, out)
+	proof := &Proof{m: m, n: n}
+
+	N = m * n
+	in, out = pad(in, N), pad(out, N)
+	perm := append(make([]int, 0, N), w.Permutation...)
+	randomness := append(make([]*ristretto255.Scalar, 0, N), w.Randomness...)
+	for i := len(perm); i < N; i++ {
+		perm = append(perm, i)
+		randomness = append(randomness, ristretto255.NewScalar())
+	}
+
+	// Commit to the permutation, a_i = pi(i) + 1.
+	a := make([]*ristretto255.Scalar, N)
+	for i, j := range perm {
+		a[i] = scalar.FromUint64(uint64(j) + 1)
+	}
+	A := matrix(a, m, n)
+	r, err := randomVector(m)
+	if err != nil {
+		return nil, err
+	}
+	for j := range A {
+		proof.cA = append(proof.cA, ck.commit(A[j], r[j]))
+		t.AppendElement("c_A", proof.cA[j])
+	}
+
+	// Commit to the permuted powers of the challenge, b_i = x^(pi(i) + 1).
+	x := t.ChallengeScalar("x")
+	xPows := scalar.Powers(x, N+1)
+	b := make([]*ristretto255.Scalar, N)
+	for i, j := range perm {
+		b[i] = xPows[j+1]
+	}
+	B := matrix(b, m, n)
+	s, err := randomVector(m)
+	if err != nil {
+		return nil, err
+	}
+	for j := range B {
+		proof.cB = append(proof.cB, ck.commit(B[j], s[j]))
+		t.AppendElement("c_B", proof.cB[j])
+	}
+
+	// Prove that prod(y * a_i + b_i - z) = prod(y * i + x^i - z), which
+	// holds for random y and z only if a and b are consistent
+	// permutations of (1, ..., N) and (x, ..., x^N).
+	y := t.ChallengeScalar("y")
+	z := t.ChallengeScalar("z")
+	d := make([]*ristretto255.Scalar, N)
+	for i := range d {
+		d[i] = ristretto255.NewScalar().Multiply(y, a[i])
+		d[i].Add(d[i], b[i])
+		d[i].Subtract(d[i], z)
+	}
+	tD := make([]*ristretto255.Scalar, m)
+	for j := range tD {
+		tD[j] = ristretto255.NewScalar().Multiply(y, r[j])
+		tD[j].Add(tD[j], s[j])
+	}
+	if proof.product, err = proveProduct(t, ck, matrix(d, m, n), tD); err != nil {
+		return nil, err
+	}
+
+	// Prove that sum(x^i * in_i) = E(0; rho) + sum(b_i * out_i) with
+	// rho = -sum(b_i * rho_i).
+	rho := scalar.InnerProduct(b, randomness)
+	rho.Negate(rho)
+	if proof.multiExp, err = proveMultiExp(t, ck, pk, matrix(out, m, n), B, s, rho); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// Verify returns nil if proof shows that out is a shuffle of in under pk, and
+// an error otherwise.
+func Verify(t *transcript.Transcript, pk *elgamal.PublicKey, in, out []*elgamal.Ciphertext, proof *Proof) error {
+	N := len(in)
+	m, n, err := dimensions(N)
+	if err != nil {
+		return err
+	}
+	if len(out) != N {
+		return errors.New("shuffle: mismatched lengths")
+	}
+	if proof == nil || proof.m != m || proof.n != n || len(proof.cA) != m || len(proof.cB) != m ||
+		slices.Contains(proof.cA, nil) || slices.Contains(proof.cB, nil) ||
+		proof.product == nil || proof.multiExp == nil {
+		return errVerification
+	}
+
+	ck := newCommitmentKey(n)
+	absorbStatement(t, pk, in, out)
+	N = m * n
+	in, out = pad(in, N), pad(out, N)
+	for _, c := range proof.cA {
+		t.AppendElement("c_A", c)
+	}
+	x := t.ChallengeScalar("x")
+	xPows := scalar.Powers(x, N+1)
+	for _, c := range proof.cB {
+		t.AppendElement("c_B", c)
+	}
+	y := t.ChallengeScalar("y")
+	z := t.ChallengeScalar("z")
+
+	// c_D = y * c_A + c_B + com(-z, ..., -z; 0).
+	cMinusZ := ristretto255.NewIdentityElement()
+	for _, G := range ck.G {
+		cMinusZ.Add(cMinusZ, G)
+	}
+	cMinusZ.ScalarMult(ristretto255.NewScalar().Negate(z), cMinusZ)
+	cD := make([]*ristretto255.Element, m)
+	for j := range cD {
+		cD[j] = ristretto255.NewIdentityElement().ScalarMult(y, proof.cA[j])
+		cD[j].Add(cD[j], proof.cB[j])
+		cD[j].Add(cD[j], cMinusZ)
+	}
+	product := scalar.One()
+	tmp := ristretto255.NewScalar()
+	for i := 1; i <= N; i++ {
+		tmp.Multiply(y, scalar.FromUint64(uint64(i)))
+		tmp.Add(tmp, xPows[i])
+		tmp.Subtract(tmp, z)
+		product.Multiply(product, tmp)
+	}
+	if !verifyProduct(t, ck, cD, product, proof.product) {
+		return errVerification
+	}
+
+	C1 := make([]*ristretto255.Element, N)
+	C2 := make([]*ristretto255.Element, N)
+	for i, c := range in {
+		C1[i], C2[i] = c.C1, c.C2
+	}
+	target := &elgamal.Ciphertext{
+		C1: linearCombination(xPows[1:], C1),
+		C2: linearCombination(xPows[1:], C2),
+	}
+	if !verifyMultiExp(t, ck, pk, matrix(out, m, n), target, proof.cB, proof.multiExp) {
+		return errVerification
+	}
+	return nil
+}
+
+// Bytes returns the encoding of the proof. It starts with m and n as 32-bit
+// big-endian integers, followed by the commitments and responses of each
+// argument, for a total of 8 + 32 * (11m + 5n + 11) bytes if m > 1, and
+// 8 + 32 * (3n + 15) bytes if m = 1.
+func (p *Proof) Bytes() []byte {
+	var e encoder
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(p.m))
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(p.n))
+	e.elements(p.cA...)
+	e.elements(p.cB...)
+
+	pa := p.product
+	if pa.cb != nil {
+		e.elements(pa.cb)
+		e.elements(pa.hadamard.cB...)
+		z := pa.hadamard.zero
+		e.elements(z.cA0, z.cBm1)
+		e.elements(z.cD...)
+		e.scalars(z.a...)
+		e.scalars(z.b...)
+		e.scalars(z.r, z.s, z.t)
+	}
+	sv := pa.svp
+	e.elements(sv.cd, sv.cLowerDelta, sv.cUpperDelta)
+	e.scalars(sv.aTilde...)
+	e.scalars(sv.bTilde...)
+	e.scalars(sv.rTilde, sv.sTilde)
+
+	me := p.multiExp
+	e.elements(me.cA0)
+	e.elements(me.cB...)
+	for _, c := range me.E {
+		e.elements(c.C1, c.C2)
+	}
+	e.scalars(me.a...)
+	e.scalars(me.r, me.b, me.s, me.tau)
+	return e.buf
+}
+
+// SetBytes sets p to the decoded encoding b, and returns p. If b is not a
+// canonical encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (p *Proof) SetBytes(b []byte) (*Proof, error) {
+	if len(b) < 8 {
+		return nil, errFormat
+	}
+	m := int(binary.BigEndian.Uint32(b[0:4]))
+	n := int(binary.BigEndian.Uint32(b[4:8]))
+	if m < 1 || n < 2 || m > n || m*n > 1<<32 {
+		return nil, errFormat
+	}
+	size := 3*n + 15
+	if m > 1 {
+		size = 11*m + 5*n + 11
+	}
+	if len(b) != 8+32*size {
+		return nil, errFormat
+	}
+
+	d := decoder{buf: b[8:]}
+	q := &Proof{m: m, n: n}
+	q.cA = d.elements(m)
+	q.cB = d.elements(m)
+
+	q.product = &productArgument{}
+	if m > 1 {
+		q.product.cb = d.element()
+		h := &hadamardArgument{cB: d.elements(m - 2)}
+		z := &zeroArgument{cA0: d.element(), cBm1: d.element()}
+		z.cD = d.elements(2 * m)
+		z.a = d.scalars(n)
+		z.b = d.scalars(n)
+		z.r, z.s, z.t = d.scalar(), d.scalar(), d.scalar()
+		h.zero = z
+		q.product.hadamard = h
+	}
+	sv := &singleValueArgument{cd: d.element(), cLowerDelta: d.element(), cUpperDelta: d.element()}
+	sv.aTilde = d.scalars(n)
+	sv.bTilde = d.scalars(n)
+	sv.rTilde, sv.sTilde = d.scalar(), d.scalar()
+	q.product.svp = sv
+
+	me := &multiExpArgument{cA0: d.element()}
+	me.cB = d.elements(2*m - 1)
+	for k := 0; k < 2*m-1; k++ {
+		me.E = append(me.E, &elgamal.Ciphertext{C1: d.element(), C2: d.element()})
+	}
+	me.a = d.scalars(n)
+	me.r, me.b, me.s, me.tau = d.scalar(), d.scalar(), d.scalar(), d.scalar()
+	q.multiExp = me
+
+	if d.err != nil {
+		return nil, errFormat
+	}
+	*p = *q
+	return p, nil
+}
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) elements(es ...*ristretto255.Element) {
+	for _, el := range es {
+		e.buf = append(e.buf, el.Bytes()...)
+	}
+}
+
+func (e *encoder) scalars(ss ...*ristretto255.Scalar) {
+	for _, s := range ss {
+		e.buf = append(e.buf, s.Bytes()...)
+	}
+}
+
+// decoder reads canonical Elements and Scalars from buf, recording the first
+// error in err. The caller checks the total length in advance.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) element() *ristretto255.Element {
+	el, err := ristretto255.NewIdentityElement().SetCanonicalBytes(d.buf[:32])
+	if err != nil && d.err == nil {
+		d.err = err
+	}
+	d.buf = d.buf[32:]
+	return el
+}
+
+func (d *decoder) elements(n int) []*ristretto255.Element {
+	out := make([]*ristretto255.Element, n)
+	for i := range out {
+		out[i] = d.element()
+	}
+	return out
+}
+
+func (d *decoder) scalar() *ristretto255.Scalar {
+	s, err := ristretto255.NewScalar().SetCanonicalBytes(d.buf[:32])
+	if err != nil && d.err == nil {
+		d.err = err
+	}
+	d.buf = d.buf[32:]
+	return s
+}
+
+func (d *decoder) scalars(n int) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, n)
+	for i := range out {
+		out[i] = d.scalar()
+	}
+	return out
+}
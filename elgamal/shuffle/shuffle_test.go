@@ -0,0 +1,215 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package shuffle
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/elgamal"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+func encryptAll(t *testing.T, pk *elgamal.PublicKey, N int) []*elgamal.Ciphertext {
+	cts := make([]*elgamal.Ciphertext, N)
+	for i := range cts {
+		var err error
+		if cts[i], _, err = pk.EncryptUint64(uint64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cts
+}
+
+func TestShuffle(t *testing.T) {
+	priv, err := elgamal.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := priv.Public()
+
+	// Cover m = 1, m = 2, primes that need padding, and larger square and
+	// non-square shapes.
+	for _, N := range []int{2, 3, 4, 6, 7, 9, 12, 16, 30, 101, 256} {
+		t.Run(fmt.Sprint(N), func(t *testing.T) {
+			in := encryptAll(t, pk, N)
+			out, w, err := Shuffle(pk, in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, j := range w.Permutation {
+				want := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(uint64(j)))
+				if priv.Decrypt(out[i]).Equal(want) != 1 {
+					t.Fatalf("out[%d] does not decrypt to in[%d]", i, j)
+				}
+			}
+
+			proof, err := Prove(transcript.New("test"), pk, in, out, w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(transcript.New("test"), pk, in, out, proof); err != nil {
+				t.Fatal(err)
+			}
+
+			enc := proof.Bytes()
+			m, n, _ := dimensions(N)
+			size := 8 + 32*(3*n+15)
+			if m > 1 {
+				size = 8 + 32*(11*m+5*n+11)
+			}
+			if len(enc) != size {
+				t.Errorf("proof is %d bytes, expected %d", len(enc), size)
+			}
+			if N >= 101 && len(enc) >= elgamal.CiphertextSize*N {
+				t.Errorf("proof is %d bytes, larger than the %d ciphertexts", len(enc), N)
+			}
+			decoded, err := new(Proof).SetBytes(enc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(transcript.New("test"), pk, in, out, decoded); err != nil {
+				t.Errorf("decoded proof failed to verify: %v", err)
+			}
+			if _, err := new(Proof).SetBytes(enc[:len(enc)-1]); err == nil {
+				t.Error("truncated proof decoded")
+			}
+			t.Logf("m = %d, n = %d, proof size = %d bytes", m, n, len(enc))
+
+			if err := Verify(transcript.New("other"), pk, in, out, proof); err == nil {
+				t.Error("proof verified with a different transcript")
+			}
+
+			// Swapping two outputs, or replacing one, breaks the proof.
+			swapped := append([]*elgamal.Ciphertext(nil), out...)
+			swapped[0], swapped[1] = swapped[1], swapped[0]
+			if err := Verify(transcript.New("test"), pk, in, swapped, proof); err == nil {
+				t.Error("proof verified for swapped outputs")
+			}
+			forged := append([]*elgamal.Ciphertext(nil), out...)
+			forged[0], _, _ = pk.EncryptUint64(1000)
+			if err := Verify(transcript.New("test"), pk, in, forged, proof); err == nil {
+				t.Error("proof verified for a replaced output")
+			}
+		})
+	}
+}
+
+func TestDimensions(t *testing.T) {
+	for _, N := range []int{2, 3, 5, 7, 101, 65537, 1<<31 - 1, 1 << 32} {
+		m, n, err := dimensions(N)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m > n || m*n < N || m*n-N >= 2*m+1 {
+			t.Errorf("N = %d: unexpected shape %d × %d", N, m, n)
+		}
+		// The proof has 11m + 5n + 11 Elements and Scalars, fewer than the
+		// 2N Elements of the ciphertexts once N is not tiny.
+		if size := 11*m + 5*n + 11; N >= 101 && size >= 2*N {
+			t.Errorf("N = %d: proof size %d is not sub-linear", N, size)
+		}
+	}
+	if m, n, _ := dimensions(65537); m != 256 || n != 257 {
+		t.Errorf("N = 65537: got %d × %d, expected 256 × 257", m, n)
+	}
+}
+
+func TestProveRejectsBadWitness(t *testing.T) {
+	priv, _ := elgamal.GenerateKey(nil)
+	pk := priv.Public()
+	in := encryptAll(t, pk, 6)
+	out, w, err := Shuffle(pk, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bad := &Witness{Permutation: append([]int(nil), w.Permutation...), Randomness: w.Randomness}
+	bad.Permutation[0] = bad.Permutation[1]
+	if _, err := Prove(transcript.New("test"), pk, in, out, bad); err == nil {
+		t.Error("Prove accepted a non-permutation")
+	}
+
+	bad = &Witness{Permutation: w.Permutation, Randomness: append([]*ristretto255.Scalar(nil), w.Randomness...)}
+	bad.Randomness[2] = scalar.One()
+	if _, err := Prove(transcript.New("test"), pk, in, out, bad); err == nil {
+		t.Error("Prove accepted wrong randomness")
+	}
+
+	// A shuffle of different plaintexts can't be proven even with a
+	// well-formed witness.
+	other := encryptAll(t, pk, 6)
+	if _, err := Prove(transcript.New("test"), pk, other, out, w); err == nil {
+		t.Error("Prove accepted unrelated inputs")
+	}
+}
+
+func TestVerifyRejectsTamperedProof(t *testing.T) {
+	priv, _ := elgamal.GenerateKey(nil)
+	pk := priv.Public()
+	in := encryptAll(t, pk, 8)
+	out, w, err := Shuffle(pk, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := Prove(transcript.New("test"), pk, in, out, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(transcript.New("test"), pk, in, out, nil); err == nil {
+		t.Error("nil proof verified")
+	}
+	if err := Verify(transcript.New("test"), pk, in, out, &Proof{}); err == nil {
+		t.Error("zero proof verified")
+	}
+	incomplete := *proof
+	incomplete.cB = make([]*ristretto255.Element, len(proof.cB))
+	if err := Verify(transcript.New("test"), pk, in, out, &incomplete); err == nil {
+		t.Error("proof without commitments verified")
+	}
+
+	enc := proof.Bytes()
+
+	// Replace each Scalar or Element in turn with another valid encoding.
+	one := scalar.One().Bytes()
+	for off := 8; off < len(enc); off += 32 {
+		tampered := append([]byte(nil), enc...)
+		copy(tampered[off:], one)
+		if string(tampered) == string(enc) {
+			continue
+		}
+		p, err := new(Proof).SetBytes(tampered)
+		if err != nil {
+			continue
+		}
+		if err := Verify(transcript.New("test"), pk, in, out, p); err == nil {
+			t.Errorf("proof verified after tampering at offset %d", off)
+		}
+	}
+}
+
+func BenchmarkShuffle(b *testing.B) {
+	priv, _ := elgamal.GenerateKey(nil)
+	pk := priv.Public()
+	in := make([]*elgamal.Ciphertext, 64)
+	for i := range in {
+		in[i], _, _ = pk.EncryptUint64(uint64(i))
+	}
+	out, w, _ := Shuffle(pk, in)
+	proof, _ := Prove(transcript.New("bench"), pk, in, out, w)
+
+	b.Run("Prove", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Prove(transcript.New("bench"), pk, in, out, w)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Verify(transcript.New("bench"), pk, in, out, proof)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package threshold implements threshold decryption of ElGamal ciphertexts
+// over ristretto255.
+//
+// The decryption key is split with Shamir secret sharing among n trustees,
+// so that any t of them can decrypt together, but fewer can't. Each trustee
+// publishes a PartialDecryption, s_i * C1, with a DLEQ proof that it used the
+// share matching its public VerificationKey, s_i * G. A combiner verifies the
+// proofs and interpolates the shares in the exponent with Lagrange
+// coefficients.
+//
+// Deal implements a trusted dealer. Distributed key generation is out of
+// scope of this package, but its output can be used with NewKeyShare.
+package threshold
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/dleq"
+	"github.com/gtank/ristretto255/elgamal"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+var suite = dleq.NewSuite([]byte("ristretto255-threshold-elgamal-v1"))
+
+// KeyShare is the decryption key share of a trustee.
+type KeyShare struct {
+	// Index is the trustee's evaluation point, in [1, n].
+	Index int
+	s     *ristretto255.Scalar
+}
+
+// VerificationKey is the public commitment to a trustee's share, s_i * G.
+type VerificationKey struct {
+	Index int
+	Y     *ristretto255.Element
+}
+
+// NewKeyShare returns the KeyShare for the trustee with the given index and
+// secret share.
+func NewKeyShare(index int, share *ristretto255.Scalar) (*KeyShare, error) {
+	if index < 1 {
+		return nil, errors.New("threshold: invalid share index")
+	}
+	return &KeyShare{Index: index, s: ristretto255.NewScalar().Set(share)}, nil
+}
+
+// VerificationKey returns the public verification key of ks.
+func (ks *KeyShare) VerificationKey() *VerificationKey {
+	return &VerificationKey{Index: ks.Index, Y: ristretto255.NewIdentityElement().ScalarBaseMult(ks.s)}
+}
+
+// Deal generates a new ElGamal key and splits it into n shares, any t of
+// which can decrypt. It returns the public key and the shares, which must be
+// distributed privately to the trustees with indexes 1 to n.
+func Deal(t, n int, rand io.Reader) (*elgamal.PublicKey, []*KeyShare, error) {
+	if t < 1 || n < t {
+		return nil, nil, errors.New("threshold: invalid threshold parameters")
+	}
+	// The constant term is the private key, the others may be zero.
+	coeffs := make([]*ristretto255.Scalar, t)
+	for i := range coeffs {
+		random := scalar.Random
+		if i == 0 {
+			random = scalar.RandomNonZero
+		}
+		c, err := random(rand)
+		if err != nil {
+			return nil, nil, err
+		}
+		coeffs[i] = c
+	}
+	priv, err := elgamal.NewPrivateKey(coeffs[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shares := make([]*KeyShare, n)
+	for i := range shares {
+		// Evaluate the polynomial at i + 1 with Horner's method.
+		x := scalar.FromUint64(uint64(i + 1))
+		y := ristretto255.NewScalar()
+		for j := t - 1; j >= 0; j-- {
+			y.Multiply(y, x)
+			y.Add(y, coeffs[j])
+		}
+		shares[i] = &KeyShare{Index: i + 1, s: y}
+	}
+	return priv.Public(), shares, nil
+}
+
+// PartialDecryption is a trustee's contribution to the decryption of a
+// batch of ciphertexts.
+type PartialDecryption struct {
+	Index int
+	// D[j] is s_i * C1 of the j-th ciphertext.
+	D []*ristretto255.Element
+	// Proof shows that log_G(Y_i) = log_C1(D[j]) for all j.
+	Proof *dleq.Proof
+}
+
+// PartialDecrypt returns the partial decryption of cts with ks, with a single
+// proof for the whole batch.
+func (ks *KeyShare) PartialDecrypt(cts []*elgamal.Ciphertext) (*PartialDecryption, error) {
+	if len(cts) == 0 {
+		return nil, errors.New("threshold: no ciphertexts")
+	}
+	C1 := make([]*ristretto255.Element, len(cts))
+	D := make([]*ristretto255.Element, len(cts))
+	for i, ct := range cts {
+		C1[i] = ct.C1
+		D[i] = ristretto255.NewIdentityElement().ScalarMult(ks.s, ct.C1)
+	}
+	Y := ks.VerificationKey().Y
+	proof, err := suite.Prove(ks.s, ristretto255.NewGeneratorElement(), Y, C1, D)
+	if err != nil {
+		return nil, err
+	}
+	return &PartialDecryption{Index: ks.Index, D: D, Proof: proof}, nil
+}
+
+// Verify checks that pd is a correct partial decryption of cts by the
+// trustee with verification key vk.
+func (pd *PartialDecryption) Verify(vk *VerificationKey, cts []*elgamal.Ciphertext) error {
+	if pd.Index != vk.Index || len(pd.D) != len(cts) || slices.Contains(pd.D, nil) || pd.Proof == nil {
+		return errors.New("threshold: partial decryption does not match")
+	}
+	C1 := make([]*ristretto255.Element, len(cts))
+	for i, ct := range cts {
+		C1[i] = ct.C1
+	}
+	if err := suite.Verify(ristretto255.NewGeneratorElement(), vk.Y, C1, pd.D, pd.Proof); err != nil {
+		return fmt.Errorf("threshold: invalid partial decryption from trustee %d", pd.Index)
+	}
+	return nil
+}
+
+// Combine verifies the partial decryptions against the trustees'
+// verification keys, and uses t valid ones to decrypt cts. It returns the
+// decrypted Elements, which for lifted ciphertexts are m * G.
+//
+// Invalid or nil partial decryptions and nil verification keys are ignored,
+// as long as at least t valid ones from distinct trustees remain. t must be
+// between 1 and len(vks).
+func Combine(t int, vks []*VerificationKey, cts []*elgamal.Ciphertext, partials []*PartialDecryption) ([]*ristretto255.Element, error) {
+	if t < 1 || t > len(vks) {
+		return nil, errors.New("threshold: invalid threshold")
+	}
+	keys := make(map[int]*VerificationKey, len(vks))
+	for _, vk := range vks {
+		if vk != nil && vk.Y != nil {
+			keys[vk.Index] = vk
+		}
+	}
+
+	var valid []*PartialDecryption
+	seen := make(map[int]bool)
+	for _, pd := range partials {
+		if pd == nil {
+			continue
+		}
+		vk, ok := keys[pd.Index]
+		if !ok || seen[pd.Index] {
+			continue
+		}
+		if pd.Verify(vk, cts) != nil {
+			continue
+		}
+		seen[pd.Index] = true
+		valid = append(valid, pd)
+		if len(valid) == t {
+			break
+		}
+	}
+	if len(valid) < t {
+		return nil, fmt.Errorf("threshold: only %d valid partial decryptions, need %d", len(valid), t)
+	}
+
+	indexes := make([]int, t)
+	for i, pd := range valid {
+		indexes[i] = pd.Index
+	}
+	lambdas := lagrangeCoefficients(indexes)
+
+	out := make([]*ristretto255.Element, len(cts))
+	D := make([]*ristretto255.Element, t)
+	for j, ct := range cts {
+		for i, pd := range valid {
+			D[i] = pd.D[j]
+		}
+		sC1 := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(lambdas, D)
+		out[j] = sC1.Subtract(ct.C2, sC1)
+	}
+	return out, nil
+}
+
+// lagrangeCoefficients returns the Lagrange coefficients at zero for the
+// given distinct evaluation points.
+func lagrangeCoefficients(indexes []int) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, len(indexes))
+	for i, xi := range indexes {
+		num, den := scalar.One(), scalar.One()
+		for j, xj := range indexes {
+			if i == j {
+				continue
+			}
+			// lambda_i = prod x_j / (x_j - x_i)
+			num.Multiply(num, scalar.FromUint64(uint64(xj)))
+			den.Multiply(den, scalar.FromInt64(int64(xj)-int64(xi)))
+		}
+		out[i] = num.Multiply(num, den.Invert(den))
+	}
+	return out
+}
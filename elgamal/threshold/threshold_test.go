@@ -0,0 +1,122 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package threshold
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/elgamal"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+func lift(m uint64) *ristretto255.Element {
+	return ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(m))
+}
+
+func TestThresholdDecryption(t *testing.T) {
+	const threshold, n = 3, 5
+	pk, shares, err := Deal(threshold, n, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vks []*VerificationKey
+	for _, ks := range shares {
+		vks = append(vks, ks.VerificationKey())
+	}
+
+	// Tally three yes votes and one no vote.
+	tally := elgamal.NewCiphertext()
+	for _, v := range []uint64{1, 1, 0, 1} {
+		ct, _, err := pk.EncryptUint64(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tally.Add(tally, ct)
+	}
+	other, _, _ := pk.EncryptUint64(42)
+	cts := []*elgamal.Ciphertext{tally, other}
+
+	var partials []*PartialDecryption
+	for _, ks := range shares {
+		pd, err := ks.PartialDecrypt(cts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pd.Verify(vks[ks.Index-1], cts); err != nil {
+			t.Errorf("trustee %d: %v", ks.Index, err)
+		}
+		partials = append(partials, pd)
+	}
+
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var pds []*PartialDecryption
+		for _, i := range subset {
+			pds = append(pds, partials[i])
+		}
+		out, err := Combine(threshold, vks, cts, pds)
+		if err != nil {
+			t.Fatalf("subset %v: %v", subset, err)
+		}
+		if out[0].Equal(lift(3)) != 1 || out[1].Equal(lift(42)) != 1 {
+			t.Errorf("subset %v: wrong decryption", subset)
+		}
+	}
+
+	if _, err := Combine(threshold, vks, cts, partials[:2]); err == nil {
+		t.Error("decrypted with fewer than t trustees")
+	}
+	if _, err := Combine(threshold, vks, cts, []*PartialDecryption{partials[0], partials[0], partials[1]}); err == nil {
+		t.Error("decrypted with a duplicate trustee")
+	}
+	for _, bad := range []int{-1, 0, len(vks) + 1} {
+		if _, err := Combine(bad, vks, cts, partials); err == nil {
+			t.Errorf("accepted t = %d", bad)
+		}
+	}
+
+	// A cheating trustee is detected and skipped.
+	bad := &PartialDecryption{Index: 2, D: append([]*ristretto255.Element(nil), partials[1].D...), Proof: partials[1].Proof}
+	bad.D[0] = ristretto255.NewIdentityElement().Add(bad.D[0], lift(1))
+	if err := bad.Verify(vks[1], cts); err == nil {
+		t.Error("tampered partial decryption verified")
+	}
+	if _, err := Combine(threshold, vks, cts, []*PartialDecryption{partials[0], bad, partials[2]}); err == nil {
+		t.Error("decrypted with a tampered partial decryption")
+	}
+	out, err := Combine(threshold, vks, cts, []*PartialDecryption{partials[0], bad, partials[2], partials[3]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[0].Equal(lift(3)) != 1 {
+		t.Error("wrong decryption after skipping a tampered share")
+	}
+
+	// Nil partial decryptions and verification keys are skipped too.
+	incomplete := &PartialDecryption{Index: 2, D: make([]*ristretto255.Element, len(cts)), Proof: partials[1].Proof}
+	out, err = Combine(threshold, vks, cts, []*PartialDecryption{nil, partials[0], incomplete, partials[2], partials[3]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[0].Equal(lift(3)) != 1 {
+		t.Error("wrong decryption after skipping nil shares")
+	}
+	missing := append([]*VerificationKey{nil}, vks[1:]...)
+	if _, err := Combine(threshold, missing, cts, partials[:3]); err == nil {
+		t.Error("decrypted with a partial decryption from a missing trustee")
+	}
+	if _, err := Combine(threshold, missing, cts, partials[1:4]); err != nil {
+		t.Errorf("failed to decrypt with a nil verification key: %v", err)
+	}
+}
+
+func TestDealErrors(t *testing.T) {
+	if _, _, err := Deal(0, 3, nil); err == nil {
+		t.Error("accepted t = 0")
+	}
+	if _, _, err := Deal(4, 3, nil); err == nil {
+		t.Error("accepted t > n")
+	}
+}
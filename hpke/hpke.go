@@ -0,0 +1,333 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hpke implements Hybrid Public Key Encryption from RFC 9180, with a
+// DHKEM over ristretto255.
+//
+// The KEM, DHKEM(ristretto255, HKDF-SHA512), follows the DHKEM construction
+// of RFC 9180, Section 4.1: the DH function multiplies a public Element by a
+// private Scalar, rejects identity results, and its output is the 32-byte
+// Element encoding, from which ExtractAndExpand derives the shared secret.
+// Since ristretto255 is not a registered HPKE KEM, its identifier KEMID is
+// not standard.
+//
+// The key schedule and the encryption contexts are those of RFC 9180,
+// Sections 5 and 6, in the base, PSK, auth, and auth-PSK modes, with the
+// HKDF-SHA256, HKDF-SHA384, and HKDF-SHA512 KDFs, and the AES-128-GCM,
+// AES-256-GCM, and export-only AEADs.
+package hpke
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math"
+)
+
+// KDF is an HPKE KDF identifier.
+type KDF uint16
+
+const (
+	HKDFSHA256 KDF = 0x0001
+	HKDFSHA384 KDF = 0x0002
+	HKDFSHA512 KDF = 0x0003
+)
+
+// AEAD is an HPKE AEAD identifier.
+type AEAD uint16
+
+const (
+	AES128GCM AEAD = 0x0001
+	AES256GCM AEAD = 0x0002
+	// ExportOnly is the identifier of the export-only AEAD, for contexts
+	// that are only used with Export.
+	ExportOnly AEAD = 0xffff
+)
+
+type mode byte
+
+const (
+	modeBase    mode = 0x00
+	modePSK     mode = 0x01
+	modeAuth    mode = 0x02
+	modeAuthPSK mode = 0x03
+)
+
+// Suite is an HPKE ciphersuite, with the KEM of this package.
+type Suite struct {
+	kemID uint16
+	kdf   KDF
+	aead  AEAD
+	hash  func() hash.Hash
+	nk    int
+}
+
+// NewSuite returns the Suite with the given KDF and AEAD.
+func NewSuite(kdf KDF, aead AEAD) (*Suite, error) {
+	return newSuite(KEMID, kdf, aead)
+}
+
+func newSuite(kemID uint16, kdf KDF, aead AEAD) (*Suite, error) {
+	s := &Suite{kemID: kemID, kdf: kdf, aead: aead}
+	switch kdf {
+	case HKDFSHA256:
+		s.hash = sha256.New
+	case HKDFSHA384:
+		s.hash = sha512.New384
+	case HKDFSHA512:
+		s.hash = sha512.New
+	default:
+		return nil, errors.New("hpke: unsupported KDF")
+	}
+	switch aead {
+	case AES128GCM:
+		s.nk = 16
+	case AES256GCM:
+		s.nk = 32
+	case ExportOnly:
+	default:
+		return nil, errors.New("hpke: unsupported AEAD")
+	}
+	return s, nil
+}
+
+// id returns the HPKE suite_id.
+func (s *Suite) id() []byte {
+	id := []byte("HPKE")
+	id = binary.BigEndian.AppendUint16(id, s.kemID)
+	id = binary.BigEndian.AppendUint16(id, uint16(s.kdf))
+	return binary.BigEndian.AppendUint16(id, uint16(s.aead))
+}
+
+// context is the encryption context shared by Sender and Recipient.
+type context struct {
+	suite          *Suite
+	aead           cipher.AEAD
+	baseNonce      []byte
+	seq            uint64
+	exporterSecret []byte
+}
+
+// keySchedule implements KeySchedule from RFC 9180, Section 5.1.
+func (s *Suite) keySchedule(m mode, sharedSecret, info, psk, pskID []byte) (*context, error) {
+	if (psk == nil) != (pskID == nil) {
+		return nil, errors.New("hpke: inconsistent PSK inputs")
+	}
+	if (psk != nil) != (m == modePSK || m == modeAuthPSK) {
+		return nil, errors.New("hpke: PSK inputs do not match the mode")
+	}
+	id := s.id()
+	pskIDHash := labeledExtract(s.hash, id, nil, "psk_id_hash", pskID)
+	infoHash := labeledExtract(s.hash, id, nil, "info_hash", info)
+	ksContext := append([]byte{byte(m)}, pskIDHash...)
+	ksContext = append(ksContext, infoHash...)
+
+	secret := labeledExtract(s.hash, id, sharedSecret, "secret", psk)
+	c := &context{
+		suite:          s,
+		exporterSecret: labeledExpand(s.hash, id, secret, "exp", ksContext, s.hash().Size()),
+	}
+	if s.aead != ExportOnly {
+		key := labeledExpand(s.hash, id, secret, "key", ksContext, s.nk)
+		c.baseNonce = labeledExpand(s.hash, id, secret, "base_nonce", ksContext, 12)
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if c.aead, err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// nonce returns the nonce for the current sequence number, or an error if
+// the sequence numbers are exhausted or the AEAD is export-only.
+func (c *context) nonce() ([]byte, error) {
+	if c.aead == nil {
+		return nil, errors.New("hpke: export-only context")
+	}
+	if c.seq == math.MaxUint64 {
+		return nil, errors.New("hpke: message limit reached")
+	}
+	nonce := append([]byte(nil), c.baseNonce...)
+	var seq [12]byte
+	binary.BigEndian.PutUint64(seq[4:], c.seq)
+	for i := range nonce {
+		nonce[i] ^= seq[i]
+	}
+	return nonce, nil
+}
+
+// export implements Context.Export from RFC 9180, Section 5.3.
+func (c *context) export(exporterContext []byte, n int) ([]byte, error) {
+	if n < 0 || n > 255*c.suite.hash().Size() {
+		return nil, errors.New("hpke: invalid export length")
+	}
+	return labeledExpand(c.suite.hash, c.suite.id(), c.exporterSecret, "sec", exporterContext, n), nil
+}
+
+// Sender is the encryption context of a sender.
+type Sender struct {
+	c *context
+}
+
+// Seal encrypts plaintext with the associated data aad, and returns the
+// ciphertext. Ciphertexts must be opened in the order they are sealed.
+func (s *Sender) Seal(aad, plaintext []byte) ([]byte, error) {
+	nonce, err := s.c.nonce()
+	if err != nil {
+		return nil, err
+	}
+	ct := s.c.aead.Seal(nil, nonce, plaintext, aad)
+	s.c.seq++
+	return ct, nil
+}
+
+// Export returns a secret of length n derived from the context and
+// exporterContext. It returns an error if n is negative or larger than 255
+// times the KDF hash size.
+func (s *Sender) Export(exporterContext []byte, n int) ([]byte, error) {
+	return s.c.export(exporterContext, n)
+}
+
+// Recipient is the decryption context of a recipient.
+type Recipient struct {
+	c *context
+}
+
+// Open decrypts ciphertext with the associated data aad, and returns the
+// plaintext. If decryption fails, the sequence number is not incremented.
+func (r *Recipient) Open(aad, ciphertext []byte) ([]byte, error) {
+	nonce, err := r.c.nonce()
+	if err != nil {
+		return nil, err
+	}
+	pt, err := r.c.aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, errors.New("hpke: decryption failed")
+	}
+	r.c.seq++
+	return pt, nil
+}
+
+// Export returns the same secret as Sender.Export for the same arguments.
+func (r *Recipient) Export(exporterContext []byte, n int) ([]byte, error) {
+	return r.c.export(exporterContext, n)
+}
+
+func (s *Suite) setupSender(rand io.Reader, m mode, pkR *PublicKey, info, psk, pskID []byte, skS *PrivateKey) ([]byte, *Sender, error) {
+	ephemeral, err := GenerateKey(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedSecret, enc, err := encap(ephemeral, pkR, skS)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := s.keySchedule(m, sharedSecret, info, psk, pskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, &Sender{c}, nil
+}
+
+func (s *Suite) setupRecipient(m mode, enc []byte, skR *PrivateKey, info, psk, pskID []byte, pkS *PublicKey) (*Recipient, error) {
+	sharedSecret, err := decap(enc, skR, pkS)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.keySchedule(m, sharedSecret, info, psk, pskID)
+	if err != nil {
+		return nil, err
+	}
+	return &Recipient{c}, nil
+}
+
+// SetupBaseSender sets up a base mode context for encrypting to pkR, and
+// returns the encapsulated key to send to the recipient. If rand is nil,
+// crypto/rand.Reader is used.
+func (s *Suite) SetupBaseSender(rand io.Reader, pkR *PublicKey, info []byte) (enc []byte, sender *Sender, err error) {
+	return s.setupSender(rand, modeBase, pkR, info, nil, nil, nil)
+}
+
+// SetupBaseRecipient sets up a base mode context for decrypting with skR.
+func (s *Suite) SetupBaseRecipient(enc []byte, skR *PrivateKey, info []byte) (*Recipient, error) {
+	return s.setupRecipient(modeBase, enc, skR, info, nil, nil, nil)
+}
+
+// SetupPSKSender is like SetupBaseSender, but also authenticates the sender
+// as a holder of the pre-shared key psk, identified by pskID. Both must be
+// non-empty.
+func (s *Suite) SetupPSKSender(rand io.Reader, pkR *PublicKey, info, psk, pskID []byte) (enc []byte, sender *Sender, err error) {
+	if len(psk) == 0 || len(pskID) == 0 {
+		return nil, nil, errors.New("hpke: missing PSK inputs")
+	}
+	return s.setupSender(rand, modePSK, pkR, info, psk, pskID, nil)
+}
+
+// SetupPSKRecipient is the recipient side of SetupPSKSender.
+func (s *Suite) SetupPSKRecipient(enc []byte, skR *PrivateKey, info, psk, pskID []byte) (*Recipient, error) {
+	if len(psk) == 0 || len(pskID) == 0 {
+		return nil, errors.New("hpke: missing PSK inputs")
+	}
+	return s.setupRecipient(modePSK, enc, skR, info, psk, pskID, nil)
+}
+
+// SetupAuthSender is like SetupBaseSender, but also authenticates the sender
+// as the holder of skS.
+func (s *Suite) SetupAuthSender(rand io.Reader, pkR *PublicKey, info []byte, skS *PrivateKey) (enc []byte, sender *Sender, err error) {
+	return s.setupSender(rand, modeAuth, pkR, info, nil, nil, skS)
+}
+
+// SetupAuthRecipient is the recipient side of SetupAuthSender, for the
+// sender public key pkS.
+func (s *Suite) SetupAuthRecipient(enc []byte, skR *PrivateKey, info []byte, pkS *PublicKey) (*Recipient, error) {
+	return s.setupRecipient(modeAuth, enc, skR, info, nil, nil, pkS)
+}
+
+// SetupAuthPSKSender combines SetupAuthSender and SetupPSKSender.
+func (s *Suite) SetupAuthPSKSender(rand io.Reader, pkR *PublicKey, info, psk, pskID []byte, skS *PrivateKey) (enc []byte, sender *Sender, err error) {
+	if len(psk) == 0 || len(pskID) == 0 {
+		return nil, nil, errors.New("hpke: missing PSK inputs")
+	}
+	return s.setupSender(rand, modeAuthPSK, pkR, info, psk, pskID, skS)
+}
+
+// SetupAuthPSKRecipient is the recipient side of SetupAuthPSKSender.
+func (s *Suite) SetupAuthPSKRecipient(enc []byte, skR *PrivateKey, info, psk, pskID []byte, pkS *PublicKey) (*Recipient, error) {
+	if len(psk) == 0 || len(pskID) == 0 {
+		return nil, errors.New("hpke: missing PSK inputs")
+	}
+	return s.setupRecipient(modeAuthPSK, enc, skR, info, psk, pskID, pkS)
+}
+
+// Seal is the single-shot base mode encryption of plaintext to pkR, as in
+// RFC 9180, Section 6.1. It returns the encapsulated key and the ciphertext.
+func (s *Suite) Seal(rand io.Reader, pkR *PublicKey, info, aad, plaintext []byte) (enc, ciphertext []byte, err error) {
+	enc, sender, err := s.SetupBaseSender(rand, pkR, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	ciphertext, err = sender.Seal(aad, plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, ciphertext, nil
+}
+
+// Open is the single-shot base mode decryption of a ciphertext produced by
+// Seal.
+func (s *Suite) Open(enc []byte, skR *PrivateKey, info, aad, ciphertext []byte) ([]byte, error) {
+	r, err := s.SetupBaseRecipient(enc, skR, info)
+	if err != nil {
+		return nil, err
+	}
+	return r.Open(aad, ciphertext)
+}
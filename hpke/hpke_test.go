@@ -0,0 +1,572 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hpke
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// decodeOptionalHex is like decodeHex, but returns nil for an empty string.
+func decodeOptionalHex(t *testing.T, s string) []byte {
+	if s == "" {
+		return nil
+	}
+	return decodeHex(t, s)
+}
+
+type rfcEncryption struct {
+	aad, pt, ct string
+}
+
+type rfcExport struct {
+	context string
+	length  int
+	value   string
+}
+
+type rfcVector struct {
+	mode                                          mode
+	kdf                                           KDF
+	aead                                          AEAD
+	info, skEm, pkRm, skSm, pkSm, psk, pskID, enc string
+	sharedSecret, key, baseNonce, exporterSecret  string
+	encryptions                                   []rfcEncryption
+	exports                                       []rfcExport
+}
+
+// Test vectors for DHKEM(X25519, HKDF-SHA256) from RFC 9180, Appendix A,
+// truncated to the first two encryptions. They check ExtractAndExpand, the
+// key schedule, and the encryption contexts, which do not depend on the
+// group, with X25519 from crypto/ecdh as the DH function.
+var rfcVectors = []rfcVector{
+	{
+		mode: 0, kdf: 0x0001, aead: 0x0001,
+		info:           "4f6465206f6e2061204772656369616e2055726e",
+		skEm:           "52c4a758a802cd8b936eceea314432798d5baf2d7e9235dc084ab1b9cfa2f736",
+		pkRm:           "3948cfe0ad1ddb695d780e59077195da6c56506b027329794ab02bca80815c4d",
+		enc:            "37fda3567bdbd628e88668c3c8d7e97d1d1253b6d4ea6d44c150f741f1bf4431",
+		sharedSecret:   "fe0e18c9f024ce43799ae393c7e8fe8fce9d218875e8227b0187c04e7d2ea1fc",
+		key:            "4531685d41d65f03dc48f6b8302c05b0",
+		baseNonce:      "56d890e5accaaf011cff4b7d",
+		exporterSecret: "45ff1c2e220db587171952c0592d5f5ebe103f1561a2614e38f2ffd47e99e3f8",
+		encryptions: []rfcEncryption{
+			{aad: "436f756e742d30", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "f938558b5d72f1a23810b4be2ab4f84331acc02fc97babc53a52ae8218a355a96d8770ac83d07bea87e13c512a"},
+			{aad: "436f756e742d31", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "af2d7e9ac9ae7e270f46ba1f975be53c09f8d875bdc8535458c2494e8a6eab251c03d0c22a56b8ca42c2063b84"},
+		},
+		exports: []rfcExport{
+			{context: "", length: 32, value: "3853fe2b4035195a573ffc53856e77058e15d9ea064de3e59f4961d0095250ee"},
+			{context: "00", length: 32, value: "2e8f0b54673c7029649d4eb9d5e33bf1872cf76d623ff164ac185da9e88c21a5"},
+			{context: "54657374436f6e74657874", length: 32, value: "e9e43065102c3836401bed8c3c3c75ae46be1639869391d62c61f1ec7af54931"},
+		},
+	},
+	{
+		mode: 1, kdf: 0x0001, aead: 0x0001,
+		info:           "4f6465206f6e2061204772656369616e2055726e",
+		skEm:           "463426a9ffb42bb17dbe6044b9abd1d4e4d95f9041cef0e99d7824eef2b6f588",
+		pkRm:           "9fed7e8c17387560e92cc6462a68049657246a09bfa8ade7aefe589672016366",
+		psk:            "0247fd33b913760fa1fa51e1892d9f307fbe65eb171e8132c2af18555a738b82",
+		pskID:          "456e6e796e20447572696e206172616e204d6f726961",
+		enc:            "0ad0950d9fb9588e59690b74f1237ecdf1d775cd60be2eca57af5a4b0471c91b",
+		sharedSecret:   "727699f009ffe3c076315019c69648366b69171439bd7dd0807743bde76986cd",
+		key:            "15026dba546e3ae05836fc7de5a7bb26",
+		baseNonce:      "9518635eba129d5ce0914555",
+		exporterSecret: "3d76025dbbedc49448ec3f9080a1abab6b06e91c0b11ad23c912f043a0ee7655",
+		encryptions: []rfcEncryption{
+			{aad: "436f756e742d30", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "e52c6fed7f758d0cf7145689f21bc1be6ec9ea097fef4e959440012f4feb73fb611b946199e681f4cfc34db8ea"},
+			{aad: "436f756e742d31", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "49f3b19b28a9ea9f43e8c71204c00d4a490ee7f61387b6719db765e948123b45b61633ef059ba22cd62437c8ba"},
+		},
+		exports: []rfcExport{
+			{context: "", length: 32, value: "dff17af354c8b41673567db6259fd6029967b4e1aad13023c2ae5df8f4f43bf6"},
+			{context: "00", length: 32, value: "6a847261d8207fe596befb52928463881ab493da345b10e1dcc645e3b94e2d95"},
+			{context: "54657374436f6e74657874", length: 32, value: "8aff52b45a1be3a734bc7a41e20b4e055ad4c4d22104b0c20285a7c4302401cd"},
+		},
+	},
+	{
+		mode: 2, kdf: 0x0001, aead: 0x0001,
+		info:           "4f6465206f6e2061204772656369616e2055726e",
+		skEm:           "ff4442ef24fbc3c1ff86375b0be1e77e88a0de1e79b30896d73411c5ff4c3518",
+		pkRm:           "1632d5c2f71c2b38d0a8fcc359355200caa8b1ffdf28618080466c909cb69b2e",
+		skSm:           "dc4a146313cce60a278a5323d321f051c5707e9c45ba21a3479fecdf76fc69dd",
+		pkSm:           "8b0c70873dc5aecb7f9ee4e62406a397b350e57012be45cf53b7105ae731790b",
+		enc:            "23fb952571a14a25e3d678140cd0e5eb47a0961bb18afcf85896e5453c312e76",
+		sharedSecret:   "2d6db4cf719dc7293fcbf3fa64690708e44e2bebc81f84608677958c0d4448a7",
+		key:            "b062cb2c4dd4bca0ad7c7a12bbc341e6",
+		baseNonce:      "a1bc314c1942ade7051ffed0",
+		exporterSecret: "ee1a093e6e1c393c162ea98fdf20560c75909653550540a2700511b65c88c6f1",
+		encryptions: []rfcEncryption{
+			{aad: "436f756e742d30", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "5fd92cc9d46dbf8943e72a07e42f363ed5f721212cd90bcfd072bfd9f44e06b80fd17824947496e21b680c141b"},
+			{aad: "436f756e742d31", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "d3736bb256c19bfa93d79e8f80b7971262cb7c887e35c26370cfed62254369a1b52e3d505b79dd699f002bc8ed"},
+		},
+		exports: []rfcExport{
+			{context: "", length: 32, value: "28c70088017d70c896a8420f04702c5a321d9cbf0279fba899b59e51bac72c85"},
+			{context: "00", length: 32, value: "25dfc004b0892be1888c3914977aa9c9bbaf2c7471708a49e1195af48a6f29ce"},
+			{context: "54657374436f6e74657874", length: 32, value: "5a0131813abc9a522cad678eb6bafaabc43389934adb8097d23c5ff68059eb64"},
+		},
+	},
+	{
+		mode: 3, kdf: 0x0001, aead: 0x0001,
+		info:           "4f6465206f6e2061204772656369616e2055726e",
+		skEm:           "14de82a5897b613616a00c39b87429df35bc2b426bcfd73febcb45e903490768",
+		pkRm:           "1d11a3cd247ae48e901939659bd4d79b6b959e1f3e7d66663fbc9412dd4e0976",
+		skSm:           "fc1c87d2f3832adb178b431fce2ac77c7ca2fd680f3406c77b5ecdf818b119f4",
+		pkSm:           "2bfb2eb18fcad1af0e4f99142a1c474ae74e21b9425fc5c589382c69b50cc57e",
+		psk:            "0247fd33b913760fa1fa51e1892d9f307fbe65eb171e8132c2af18555a738b82",
+		pskID:          "456e6e796e20447572696e206172616e204d6f726961",
+		enc:            "820818d3c23993492cc5623ab437a48a0a7ca3e9639c140fe1e33811eb844b7c",
+		sharedSecret:   "f9d0e870aba28d04709b2680cb8185466c6a6ff1d6e9d1091d5bf5e10ce3a577",
+		key:            "1364ead92c47aa7becfa95203037b19a",
+		baseNonce:      "99d8b5c54669807e9fc70df1",
+		exporterSecret: "f048d55eacbf60f9c6154bd4021774d1075ebf963c6adc71fa846f183ab2dde6",
+		encryptions: []rfcEncryption{
+			{aad: "436f756e742d30", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "a84c64df1e11d8fd11450039d4fe64ff0c8a99fca0bd72c2d4c3e0400bc14a40f27e45e141a24001697737533e"},
+			{aad: "436f756e742d31", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "4d19303b848f424fc3c3beca249b2c6de0a34083b8e909b6aa4c3688505c05ffe0c8f57a0a4c5ab9da127435d9"},
+		},
+		exports: []rfcExport{
+			{context: "", length: 32, value: "08f7e20644bb9b8af54ad66d2067457c5f9fcb2a23d9f6cb4445c0797b330067"},
+			{context: "00", length: 32, value: "52e51ff7d436557ced5265ff8b94ce69cf7583f49cdb374e6aad801fc063b010"},
+			{context: "54657374436f6e74657874", length: 32, value: "a30c20370c026bbea4dca51cb63761695132d342bae33a6a11527d3e7679436d"},
+		},
+	},
+	{
+		mode: 3, kdf: 0x0003, aead: 0x0002,
+		info:           "4f6465206f6e2061204772656369616e2055726e",
+		skEm:           "5386934a3f61c6cdb2a70b18fb67106d7e7a77c8b4d4126c016a350be0ab3217",
+		pkRm:           "740730cdce9e8dab82ca0648a3cc2df40281d4c2166e9f6c3698e6aa666e4930",
+		skSm:           "ac9e7ab12c37daeaa9b2098502a7db2118d536e6b3b9e8385d79a52ee7f71541",
+		pkSm:           "99ce50c3f04d367deac454e1c04c662fa2b398ea2fae15d93d163aa07d6dba49",
+		psk:            "0247fd33b913760fa1fa51e1892d9f307fbe65eb171e8132c2af18555a738b82",
+		pskID:          "456e6e796e20447572696e206172616e204d6f726961",
+		enc:            "473a5c15d5e0b488c7b321e99172e1663be514efe79387ffb1da4a53b806c461",
+		sharedSecret:   "d22ed5c53b896b89c11940993dbc6924a8f0e17f11ca0d095804060bf9909106",
+		key:            "d96b2d9043a9b875fc4b2b7079dccd0d6e2c7b431a0517065e73a349b625bb24",
+		baseNonce:      "7782f07d1ce3bd345b1de3da",
+		exporterSecret: "b47dad6405736797e6583defa8ee9adab77fe62c3c0730ed6672a08c63fc10b8bc4fad3cb8c2016358419fc2266afd1856c81e9353baf32b007c5f7bbd55a9e0",
+		encryptions: []rfcEncryption{
+			{aad: "436f756e742d30", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "2ea1d1a353b0aba7bb38ed44f518adf446e08fc09f0957587ab42c16986ec2c673b0c1b4874b2ef68f1faaa67b"},
+			{aad: "436f756e742d31", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "037852438d48eae6c32b5aee5db029026939cd967dbaff83a7fd6a96d2f92f99b72ede907ac0795d8a6acaaa57"},
+		},
+		exports: []rfcExport{
+			{context: "", length: 32, value: "243c7c7b1461cd6c8640e728b32ae1a6bf9ab58ffaaa21d3e048bc385dd54008"},
+			{context: "00", length: 32, value: "a0e09de8c298866898cd022934a8c5e3c9cb4b35e483b40fea76518682b822a7"},
+			{context: "54657374436f6e74657874", length: 32, value: "cf3817737cfd63c25ff9fec3541fdc0ed2a7279dfc5cef3cdde9a18648644808"},
+		},
+	},
+	{
+		mode: 0, kdf: 0x0003, aead: 0x0002,
+		info:           "4f6465206f6e2061204772656369616e2055726e",
+		skEm:           "dc926085fd67a0338320c3b47944b56eec296981d646ab5e3492e3460bebaf51",
+		pkRm:           "aa7bddcf5ca0b2c0cf760b5dffc62740a8e761ec572032a809bebc87aaf7575e",
+		enc:            "c12ba9fb91d7ebb03057d8bea4398688dcc1d1d1ff3b97f09b96b9bf89bd1e4a",
+		sharedSecret:   "96fe0a805d100153533f0646095a652eecb19346db433089666ee539a796ffb2",
+		key:            "f3354d286a48f67ca0c22029feb446938efb1b9b8a410852d7bdd3404acd0c09",
+		baseNonce:      "d654f65e557737ea2a0b5489",
+		exporterSecret: "74536eda135901a81409ab3f8f4767d2cf41933136bbd194427cec8e6fe2253f3ac0beae54180a7837dea9277a3290749777f65a874fdd2ca69c7ef5ee5bbcfe",
+		encryptions: []rfcEncryption{
+			{aad: "436f756e742d30", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "186cbeffd80fd68862b09d968a944c9f1ecc1c3f5dbcd1e26973ec30a9856f006f7bb472c3e30fff57ced669fc"},
+			{aad: "436f756e742d31", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "26f19180ac025f865e8383809317e472474b91afbdbd0e402800bca5c299157fefd833aec48ec220eedd683c31"},
+		},
+		exports: []rfcExport{
+			{context: "", length: 32, value: "e0c5b2c8c3af6ea743bf51b48f75d965f5eb71fce668c550863b14b75f61840c"},
+			{context: "00", length: 32, value: "782f53407c273fdd8ffe55fe9540b5c209dcf74beeffb38a807948b354fca3b3"},
+			{context: "54657374436f6e74657874", length: 32, value: "af616a8dc3fa47900b8e68f878fba983134b4b608bcad9c0f743d2aa7c1a781b"},
+		},
+	},
+	{
+		mode: 1, kdf: 0x0003, aead: 0x0002,
+		info:           "4f6465206f6e2061204772656369616e2055726e",
+		skEm:           "245b6a48b7cf15a0d89b40b932804edb018b3a6de68e4f3f7c33f64ba3d8d2e6",
+		pkRm:           "7891026ecbfe6339d804da654cdd6797e9bedf85f3abc56ae46a693eeef55743",
+		psk:            "0247fd33b913760fa1fa51e1892d9f307fbe65eb171e8132c2af18555a738b82",
+		pskID:          "456e6e796e20447572696e206172616e204d6f726961",
+		enc:            "67867a1c41afa75cbce4f726304adda5062c2793c2e6b307dd0191a204a4db5b",
+		sharedSecret:   "360d4f9490b0822e944c012ce6dac05f3331a1ae2695a2e64d6f42e3ef63abb9",
+		key:            "0976c6d00ce1f600195b827db4d60232bda81c1f577d1de13e19ad00ebbc38ba",
+		baseNonce:      "fa603a394e9e6bd93d21cd52",
+		exporterSecret: "348e036205f78026df40a27b87f7e474015a20e5a8e9a828cd396f18aa3fa0e38a943bda9604865ce99481c93c481068f746ab7e87fd9842f2c12b07fc96f29f",
+		encryptions: []rfcEncryption{
+			{aad: "436f756e742d30", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "018c929f81250301f7839048f814448a679e94f0e19b944737b54ced9e623e535e5ebc439e6eb49ca00b04883e"},
+			{aad: "436f756e742d31", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "e96fe1bd46cf4943536e731887e6e3557ff87e128e9244bb7eedd25f3e9a78a5c943a805052cd60e8d8f5f61d9"},
+		},
+		exports: []rfcExport{
+			{context: "", length: 32, value: "75570a8d2eac7404054cd589d70987bbf69a7771a0cdefdc431fc97144085dd8"},
+			{context: "00", length: 32, value: "b637f2a82362259126c2e3f955b3958b03d7c29561b825c79fd1b8f33e0f30a5"},
+			{context: "54657374436f6e74657874", length: 32, value: "202e2a37a076d0e683cdbc27c03eaeeb2d73519eb018d8bdabe467743d1d3bfb"},
+		},
+	},
+	{
+		mode: 2, kdf: 0x0003, aead: 0x0002,
+		info:           "4f6465206f6e2061204772656369616e2055726e",
+		skEm:           "c38ab7cc90dfb49776bc0f1137eda624e62371bead515cbc93c69000eff747c5",
+		pkRm:           "c05b1ec51b2ddb9f226074582fd6e259cc9ca35e92c73a24c7b5062e2ac3f712",
+		skSm:           "163665f9be4038f7f4b78bf097690ce1820afeca2d7502d6b342c4df9132bcac",
+		pkSm:           "80ffae75685b9d176ad0ed7f721c64f3c274b50f5a1b113165c44915db7c5217",
+		enc:            "3e276b60dab1aeddce9176e30201795fc7c32736912f670c8f09e1334008a354",
+		sharedSecret:   "039e572d8d6928e925dd19e3400d080dad8e469723897558bdc5694196556787",
+		key:            "948cd9484623c2e148e2294619ca39e99ebee2bd59494841458c45b99e09367d",
+		baseNonce:      "a46aebcafe409e3c97ed0970",
+		exporterSecret: "8534e883089b983739244d4b6dfb5409e7bc8664cde57937b0322d9ddfb0047a92508ebe5932355004dc1050136d52ec5d8c6f47581a16995bb2c05a0188f1b4",
+		encryptions: []rfcEncryption{
+			{aad: "436f756e742d30", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "3866644bbf36102c2360070942108b1459b725a28c6bd3d4224deff4ae11c04b7bb484cc688395222c0287a010"},
+			{aad: "436f756e742d31", pt: "4265617574792069732074727574682c20747275746820626561757479", ct: "07256a9a29ec37e1dbc0308453de93e831061864f3d7b6f1192f921deba822212dea874769b4b98038f07145bf"},
+		},
+		exports: []rfcExport{
+			{context: "", length: 32, value: "53e2ea7a4836acfed06560f2c3e9e4769c64c327ebb8b935dbe48545eae3bac2"},
+			{context: "00", length: 32, value: "d16bdb8c2e89e98f01adb67b812a077be2a70ed601fe41d72fbd566792bb394c"},
+			{context: "54657374436f6e74657874", length: 32, value: "7080e8ab74a5c901cb4556cacb48570737ffb5acdf895c2c9e6e436cf865b773"},
+		},
+	},
+	{
+		mode: 0, kdf: 0x0001, aead: 0xffff,
+		info:           "4f6465206f6e2061204772656369616e2055726e",
+		skEm:           "095182b502f1f91f63ba584c7c3ec473d617b8b4c2cec3fad5af7fa6748165ed",
+		pkRm:           "194141ca6c3c3beb4792cd97ba0ea1faff09d98435012345766ee33aae2d7664",
+		enc:            "e5e8f9bfff6c2f29791fc351d2c25ce1299aa5eaca78a757c0b4fb4bcd830918",
+		sharedSecret:   "e81716ce8f73141d4f25ee9098efc968c91e5b8ce52ffff59d64039e82918b66",
+		exporterSecret: "79dc8e0509cf4a3364ca027e5a0138235281611ca910e435e8ed58167c72f79b",
+		encryptions:    []rfcEncryption{},
+		exports: []rfcExport{
+			{context: "", length: 32, value: "7a36221bd56d50fb51ee65edfd98d06a23c4dc87085aa5866cb7087244bd2a36"},
+			{context: "00", length: 32, value: "d5535b87099c6c3ce80dc112a2671c6ec8e811a2f284f948cec6dd1708ee33f0"},
+			{context: "54657374436f6e74657874", length: 32, value: "ffaabc85a776136ca0c378e5d084c9140ab552b78f039d2e8775f26efff4c70e"},
+		},
+	},
+}
+
+func x25519(t *testing.T, sk, pk []byte) []byte {
+	t.Helper()
+	priv, err := ecdh.X25519().NewPrivateKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ecdh.X25519().NewPublicKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := priv.ECDH(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestRFCVectors(t *testing.T) {
+	const x25519KEMID = 0x0020
+	x25519SuiteID := binary.BigEndian.AppendUint16([]byte("KEM"), x25519KEMID)
+	for _, v := range rfcVectors {
+		enc, pkRm := decodeHex(t, v.enc), decodeHex(t, v.pkRm)
+		dh := x25519(t, decodeHex(t, v.skEm), pkRm)
+		kemContext := append(append([]byte(nil), enc...), pkRm...)
+		if v.mode == modeAuth || v.mode == modeAuthPSK {
+			pkSm := decodeHex(t, v.pkSm)
+			dh = append(dh, x25519(t, decodeHex(t, v.skSm), pkRm)...)
+			kemContext = append(kemContext, pkSm...)
+		}
+		sharedSecret := extractAndExpand(sha256.New, x25519SuiteID, 32, dh, kemContext)
+		if got := hex.EncodeToString(sharedSecret); got != v.sharedSecret {
+			t.Errorf("mode %d: got shared secret %s, want %s", v.mode, got, v.sharedSecret)
+		}
+
+		suite, err := newSuite(x25519KEMID, v.kdf, v.aead)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := suite.keySchedule(v.mode, sharedSecret, decodeHex(t, v.info),
+			decodeOptionalHex(t, v.psk), decodeOptionalHex(t, v.pskID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(c.exporterSecret); got != v.exporterSecret {
+			t.Errorf("mode %d: got exporter secret %s, want %s", v.mode, got, v.exporterSecret)
+		}
+		if got := hex.EncodeToString(c.baseNonce); got != v.baseNonce {
+			t.Errorf("mode %d: got base nonce %s, want %s", v.mode, got, v.baseNonce)
+		}
+		sender := &Sender{c}
+		recipient := &Recipient{&context{suite: suite, aead: c.aead, baseNonce: c.baseNonce, exporterSecret: c.exporterSecret}}
+		for i, e := range v.encryptions {
+			ct, err := sender.Seal(decodeHex(t, e.aad), decodeHex(t, e.pt))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(ct); got != e.ct {
+				t.Errorf("mode %d: encryption %d: got %s, want %s", v.mode, i, got, e.ct)
+			}
+			pt, err := recipient.Open(decodeHex(t, e.aad), ct)
+			if err != nil || hex.EncodeToString(pt) != e.pt {
+				t.Errorf("mode %d: decryption %d failed: %v", v.mode, i, err)
+			}
+		}
+		if v.aead == ExportOnly {
+			if _, err := sender.Seal(nil, nil); err == nil {
+				t.Error("export-only context encrypted a message")
+			}
+		}
+		for _, e := range v.exports {
+			exported, err := recipient.Export(decodeHex(t, e.context), e.length)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(exported); got != e.value {
+				t.Errorf("mode %d: got export %s, want %s", v.mode, got, e.value)
+			}
+		}
+	}
+}
+
+func mustGenerateKey(t *testing.T) *PrivateKey {
+	t.Helper()
+	k, err := GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestModes(t *testing.T) {
+	skR, skS := mustGenerateKey(t), mustGenerateKey(t)
+	info, psk, pskID := []byte("info"), bytes.Repeat([]byte{7}, 32), []byte("psk id")
+	type setup func(s *Suite) ([]byte, *Sender, *Recipient, error)
+	for name, setup := range map[string]setup{
+		"base": func(s *Suite) ([]byte, *Sender, *Recipient, error) {
+			enc, sender, err := s.SetupBaseSender(nil, skR.Public(), info)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			r, err := s.SetupBaseRecipient(enc, skR, info)
+			return enc, sender, r, err
+		},
+		"psk": func(s *Suite) ([]byte, *Sender, *Recipient, error) {
+			enc, sender, err := s.SetupPSKSender(nil, skR.Public(), info, psk, pskID)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			r, err := s.SetupPSKRecipient(enc, skR, info, psk, pskID)
+			return enc, sender, r, err
+		},
+		"auth": func(s *Suite) ([]byte, *Sender, *Recipient, error) {
+			enc, sender, err := s.SetupAuthSender(nil, skR.Public(), info, skS)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			r, err := s.SetupAuthRecipient(enc, skR, info, skS.Public())
+			return enc, sender, r, err
+		},
+		"auth-psk": func(s *Suite) ([]byte, *Sender, *Recipient, error) {
+			enc, sender, err := s.SetupAuthPSKSender(nil, skR.Public(), info, psk, pskID, skS)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			r, err := s.SetupAuthPSKRecipient(enc, skR, info, psk, pskID, skS.Public())
+			return enc, sender, r, err
+		},
+	} {
+		for _, kdf := range []KDF{HKDFSHA256, HKDFSHA384, HKDFSHA512} {
+			for _, aead := range []AEAD{AES128GCM, AES256GCM} {
+				s, err := NewSuite(kdf, aead)
+				if err != nil {
+					t.Fatal(err)
+				}
+				enc, sender, recipient, err := setup(s)
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if len(enc) != EncapsulatedKeySize {
+					t.Errorf("%s: got encapsulated key length %d", name, len(enc))
+				}
+				for _, msg := range []string{"first", "second"} {
+					ct, err := sender.Seal([]byte("aad"), []byte(msg))
+					if err != nil {
+						t.Fatal(err)
+					}
+					pt, err := recipient.Open([]byte("aad"), ct)
+					if err != nil || string(pt) != msg {
+						t.Errorf("%s: got %q, %v", name, pt, err)
+					}
+				}
+				e1, err := sender.Export([]byte("ctx"), 42)
+				if err != nil {
+					t.Fatal(err)
+				}
+				e2, err := recipient.Export([]byte("ctx"), 42)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(e1, e2) {
+					t.Errorf("%s: exported secrets do not match", name)
+				}
+			}
+		}
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	s, err := NewSuite(HKDFSHA512, AES256GCM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skR, skS := mustGenerateKey(t), mustGenerateKey(t)
+	psk, pskID := bytes.Repeat([]byte{7}, 32), []byte("psk id")
+
+	enc, sender, err := s.SetupAuthSender(nil, skR.Public(), nil, skS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, err := sender.Seal(nil, []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := s.SetupAuthRecipient(enc, skR, nil, mustGenerateKey(t).Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Open(nil, ct); err == nil {
+		t.Error("opened a message from the wrong sender")
+	}
+	if r, err = s.SetupBaseRecipient(enc, skR, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Open(nil, ct); err == nil {
+		t.Error("opened an auth mode message in base mode")
+	}
+
+	enc, sender, err = s.SetupPSKSender(nil, skR.Public(), nil, psk, pskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct, err = sender.Seal(nil, []byte("message")); err != nil {
+		t.Fatal(err)
+	}
+	if r, err = s.SetupPSKRecipient(enc, skR, nil, bytes.Repeat([]byte{8}, 32), pskID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Open(nil, ct); err == nil {
+		t.Error("opened a message with the wrong PSK")
+	}
+	if _, _, err := s.SetupPSKSender(nil, skR.Public(), nil, nil, pskID); err == nil {
+		t.Error("set up a PSK context without a PSK")
+	}
+}
+
+func TestSingleShot(t *testing.T) {
+	s, err := NewSuite(HKDFSHA256, AES128GCM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skR := mustGenerateKey(t)
+	enc, ct, err := s.Seal(nil, skR.Public(), []byte("info"), []byte("aad"), []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := s.Open(enc, skR, []byte("info"), []byte("aad"), ct)
+	if err != nil || string(pt) != "message" {
+		t.Errorf("got %q, %v", pt, err)
+	}
+	if _, err := s.Open(enc, skR, []byte("other"), []byte("aad"), ct); err == nil {
+		t.Error("opened a message with the wrong info")
+	}
+	if _, err := s.Open(enc, mustGenerateKey(t), []byte("info"), []byte("aad"), ct); err == nil {
+		t.Error("opened a message with the wrong key")
+	}
+}
+
+func TestExportLength(t *testing.T) {
+	s, err := NewSuite(HKDFSHA256, ExportOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skR := mustGenerateKey(t)
+	enc, sender, err := s.SetupBaseSender(nil, skR.Public(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := s.SetupBaseRecipient(enc, skR, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{-1, 255*32 + 1} {
+		if _, err := sender.Export(nil, n); err == nil {
+			t.Errorf("sender exported %d bytes", n)
+		}
+		if _, err := recipient.Export(nil, n); err == nil {
+			t.Errorf("recipient exported %d bytes", n)
+		}
+	}
+	for _, n := range []int{0, 255 * 32} {
+		out, err := sender.Export(nil, n)
+		if err != nil || len(out) != n {
+			t.Errorf("got %d bytes, %v, for an export of %d bytes", len(out), err, n)
+		}
+	}
+}
+
+func TestKEM(t *testing.T) {
+	skR, skS := mustGenerateKey(t), mustGenerateKey(t)
+	ss, enc, err := Encap(nil, skR.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := skR.Decap(enc); err != nil || !bytes.Equal(got, ss) || len(ss) != SharedSecretSize {
+		t.Error("Decap did not recover the shared secret")
+	}
+	ss, enc, err = AuthEncap(nil, skR.Public(), skS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := skR.AuthDecap(enc, skS.Public()); err != nil || !bytes.Equal(got, ss) {
+		t.Error("AuthDecap did not recover the shared secret")
+	}
+
+	identity := ristretto255.NewIdentityElement().Bytes()
+	if _, err := skR.Decap(identity); err == nil {
+		t.Error("decapsulated the identity element")
+	}
+	if _, err := NewPublicKey(identity); err == nil {
+		t.Error("decoded the identity element as a public key")
+	}
+	if _, err := NewPrivateKey(make([]byte, PrivateKeySize)); err == nil {
+		t.Error("decoded zero as a private key")
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	ikm := bytes.Repeat([]byte{0x42}, 32)
+	k1, err := DeriveKey(ikm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := DeriveKey(ikm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(k1.Bytes(), k2.Bytes()) {
+		t.Error("DeriveKey is not deterministic")
+	}
+	k3, err := NewPrivateKey(k1.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(k3.Public().Bytes(), k1.Public().Bytes()) {
+		t.Error("private key did not round-trip")
+	}
+	if _, err := DeriveKey(ikm[:31]); err == nil {
+		t.Error("derived a key from short input keying material")
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hpke
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/ecdh"
+	"github.com/gtank/ristretto255/internal/hkdf"
+)
+
+// KEMID is the identifier of DHKEM(ristretto255, HKDF-SHA512). It is not
+// assigned by IANA, and is chosen from an unassigned range: both parties
+// must agree on it out of band.
+const KEMID uint16 = 0xff20
+
+const (
+	// EncapsulatedKeySize is the size in bytes of an encapsulated key (Nenc).
+	EncapsulatedKeySize = 32
+	// PublicKeySize is the size in bytes of an encoded PublicKey (Npk).
+	PublicKeySize = 32
+	// PrivateKeySize is the size in bytes of an encoded PrivateKey (Nsk).
+	PrivateKeySize = 32
+	// SharedSecretSize is the size in bytes of the KEM shared secret
+	// (Nsecret).
+	SharedSecretSize = sha512.Size
+)
+
+var kemSuiteID = binary.BigEndian.AppendUint16([]byte("KEM"), KEMID)
+
+// labeledExtract implements LabeledExtract from RFC 9180, Section 4.
+func labeledExtract(h func() hash.Hash, suiteID, salt []byte, label string, ikm []byte) []byte {
+	var in []byte
+	in = append(in, "HPKE-v1"...)
+	in = append(in, suiteID...)
+	in = append(in, label...)
+	in = append(in, ikm...)
+	return hkdf.Extract(h, salt, in)
+}
+
+// labeledExpand implements LabeledExpand from RFC 9180, Section 4.
+func labeledExpand(h func() hash.Hash, suiteID, prk []byte, label string, info []byte, n int) []byte {
+	var in []byte
+	in = binary.BigEndian.AppendUint16(in, uint16(n))
+	in = append(in, "HPKE-v1"...)
+	in = append(in, suiteID...)
+	in = append(in, label...)
+	in = append(in, info...)
+	return hkdf.Expand(h, prk, in, n)
+}
+
+// extractAndExpand implements ExtractAndExpand from RFC 9180, Section 4.1,
+// for a DHKEM with the given hash, suite identifier, and Nsecret.
+func extractAndExpand(h func() hash.Hash, suiteID []byte, nSecret int, dh, kemContext []byte) []byte {
+	prk := labeledExtract(h, suiteID, nil, "eae_prk", dh)
+	return labeledExpand(h, suiteID, prk, "shared_secret", kemContext, nSecret)
+}
+
+// PrivateKey is a DHKEM(ristretto255, HKDF-SHA512) private key.
+type PrivateKey struct {
+	k   *ecdh.PrivateKey
+	pub *PublicKey
+}
+
+// PublicKey is a DHKEM(ristretto255, HKDF-SHA512) public key.
+type PublicKey struct {
+	k *ecdh.PublicKey
+}
+
+// GenerateKey returns a new PrivateKey, reading randomness from rand. If rand
+// is nil, crypto/rand.Reader is used.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	k, err := ecdh.GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	return newPrivateKey(k), nil
+}
+
+// DeriveKey implements DeriveKeyPair, returning the PrivateKey derived from
+// ikm, which must be at least PrivateKeySize bytes of uniformly random input
+// keying material.
+func DeriveKey(ikm []byte) (*PrivateKey, error) {
+	if len(ikm) < PrivateKeySize {
+		return nil, errors.New("hpke: input keying material too short")
+	}
+	prk := labeledExtract(sha512.New, kemSuiteID, nil, "dkp_prk", ikm)
+	b := labeledExpand(sha512.New, kemSuiteID, prk, "sk", nil, 64)
+	s, err := ristretto255.NewScalar().SetUniformBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	// The encoding of s is canonical, so this only fails if s is zero.
+	k, err := ecdh.NewPrivateKey(s.Bytes())
+	if err != nil {
+		return nil, errors.New("hpke: derived key is zero")
+	}
+	return newPrivateKey(k), nil
+}
+
+// NewPrivateKey decodes a PrivateKey from its PrivateKeySize-byte encoding,
+// a canonical non-zero Scalar.
+func NewPrivateKey(b []byte) (*PrivateKey, error) {
+	k, err := ecdh.NewPrivateKey(b)
+	if err != nil {
+		return nil, errors.New("hpke: invalid private key")
+	}
+	return newPrivateKey(k), nil
+}
+
+func newPrivateKey(k *ecdh.PrivateKey) *PrivateKey {
+	return &PrivateKey{k: k, pub: &PublicKey{k: k.PublicKey()}}
+}
+
+// Bytes returns the PrivateKeySize-byte encoding of priv.
+func (priv *PrivateKey) Bytes() []byte {
+	return priv.k.Bytes()
+}
+
+// Public returns the public key corresponding to priv.
+func (priv *PrivateKey) Public() *PublicKey {
+	return priv.pub
+}
+
+// NewPublicKey decodes a PublicKeySize-byte public key encoding. The
+// identity element is rejected.
+func NewPublicKey(b []byte) (*PublicKey, error) {
+	k, err := ecdh.NewPublicKey(b)
+	if err != nil {
+		return nil, errors.New("hpke: invalid public key")
+	}
+	return &PublicKey{k: k}, nil
+}
+
+// Bytes returns the PublicKeySize-byte encoding of pk.
+func (pk *PublicKey) Bytes() []byte {
+	return pk.k.Bytes()
+}
+
+// dh returns the encoding of priv * pk, or an error if it is the identity
+// element.
+func dh(priv *PrivateKey, pk *PublicKey) ([]byte, error) {
+	out, err := priv.k.ECDH(pk.k)
+	if err != nil {
+		return nil, errors.New("hpke: DH result is the identity element")
+	}
+	return out, nil
+}
+
+// encap implements Encap and, if sender is not nil, AuthEncap with the
+// ephemeral key ephemeral.
+func encap(ephemeral *PrivateKey, pkR *PublicKey, sender *PrivateKey) (sharedSecret, enc []byte, err error) {
+	dhOut, err := dh(ephemeral, pkR)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc = ephemeral.pub.Bytes()
+	kemContext := append(append([]byte(nil), enc...), pkR.Bytes()...)
+	if sender != nil {
+		dhS, err := dh(sender, pkR)
+		if err != nil {
+			return nil, nil, err
+		}
+		dhOut = append(dhOut, dhS...)
+		kemContext = append(kemContext, sender.pub.Bytes()...)
+	}
+	return extractAndExpand(sha512.New, kemSuiteID, SharedSecretSize, dhOut, kemContext), enc, nil
+}
+
+// decap implements Decap and, if pkS is not nil, AuthDecap.
+func decap(enc []byte, priv *PrivateKey, pkS *PublicKey) ([]byte, error) {
+	pkE, err := NewPublicKey(enc)
+	if err != nil {
+		return nil, err
+	}
+	dhOut, err := dh(priv, pkE)
+	if err != nil {
+		return nil, err
+	}
+	kemContext := append(pkE.Bytes(), priv.pub.Bytes()...)
+	if pkS != nil {
+		dhS, err := dh(priv, pkS)
+		if err != nil {
+			return nil, err
+		}
+		dhOut = append(dhOut, dhS...)
+		kemContext = append(kemContext, pkS.Bytes()...)
+	}
+	return extractAndExpand(sha512.New, kemSuiteID, SharedSecretSize, dhOut, kemContext), nil
+}
+
+// Encap generates a SharedSecretSize-byte shared secret, and its
+// encapsulation to pkR. If rand is nil, crypto/rand.Reader is used.
+func Encap(rand io.Reader, pkR *PublicKey) (sharedSecret, enc []byte, err error) {
+	ephemeral, err := GenerateKey(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encap(ephemeral, pkR, nil)
+}
+
+// Decap returns the shared secret encapsulated in enc.
+func (priv *PrivateKey) Decap(enc []byte) (sharedSecret []byte, err error) {
+	return decap(enc, priv, nil)
+}
+
+// AuthEncap is like Encap, but also authenticates the shared secret as
+// coming from the holder of sender.
+func AuthEncap(rand io.Reader, pkR *PublicKey, sender *PrivateKey) (sharedSecret, enc []byte, err error) {
+	ephemeral, err := GenerateKey(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encap(ephemeral, pkR, sender)
+}
+
+// AuthDecap returns the shared secret encapsulated in enc by the holder of
+// the private key for pkS.
+func (priv *PrivateKey) AuthDecap(enc []byte, pkS *PublicKey) (sharedSecret []byte, err error) {
+	return decap(enc, priv, pkS)
+}
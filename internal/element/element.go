@@ -0,0 +1,31 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package element provides small helpers for working with ristretto255
+// Elements that are shared by the protocol packages in this module.
+package element
+
+import (
+	"errors"
+
+	"github.com/gtank/ristretto255"
+)
+
+// IsIdentity reports whether e is the identity element.
+func IsIdentity(e *ristretto255.Element) bool {
+	return e.Equal(ristretto255.NewIdentityElement()) == 1
+}
+
+// DecodeNonIdentity decodes a canonical 32-byte encoding of an Element,
+// rejecting the identity element, as required for public keys and shares.
+func DecodeNonIdentity(b []byte) (*ristretto255.Element, error) {
+	e, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	if IsIdentity(e) {
+		return nil, errors.New("ristretto255: identity element")
+	}
+	return e, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package h2c implements expand_message_xmd from RFC 9380, Section 5.3.1, and
+// the ristretto255 hash-to-group and hash-to-scalar operations built on it.
+package h2c
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"hash"
+
+	"github.com/gtank/ristretto255"
+)
+
+// ExpandMessageXMD implements expand_message_xmd with the hash function h,
+// returning n bytes.
+func ExpandMessageXMD(h func() hash.Hash, msg, dst []byte, n int) ([]byte, error) {
+	H := h()
+	bInBytes := H.Size()
+	sInBytes := H.BlockSize()
+
+	if len(dst) > 255 {
+		H.Write([]byte("H2C-OVERSIZE-DST-"))
+		H.Write(dst)
+		dst = H.Sum(nil)
+		H.Reset()
+	}
+	ell := (n + bInBytes - 1) / bInBytes
+	if ell > 255 || n > 65535 {
+		return nil, errors.New("h2c: requested output is too long")
+	}
+	dstPrime := append(append([]byte(nil), dst...), byte(len(dst)))
+
+	var lib [2]byte
+	binary.BigEndian.PutUint16(lib[:], uint16(n))
+
+	H.Write(make([]byte, sInBytes))
+	H.Write(msg)
+	H.Write(lib[:])
+	H.Write([]byte{0})
+	H.Write(dstPrime)
+	b0 := H.Sum(nil)
+
+	H.Reset()
+	H.Write(b0)
+	H.Write([]byte{1})
+	H.Write(dstPrime)
+	bi := H.Sum(nil)
+
+	out := make([]byte, 0, ell*bInBytes)
+	out = append(out, bi...)
+	for i := 2; i <= ell; i++ {
+		x := make([]byte, bInBytes)
+		for j := range x {
+			x[j] = b0[j] ^ bi[j]
+		}
+		H.Reset()
+		H.Write(x)
+		H.Write([]byte{byte(i)})
+		H.Write(dstPrime)
+		bi = H.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:n], nil
+}
+
+// HashToElement implements hash_to_ristretto255 from RFC 9380, Appendix B,
+// with expand_message_xmd and SHA-512.
+func HashToElement(msg, dst []byte) *ristretto255.Element {
+	b, err := ExpandMessageXMD(sha512.New, msg, dst, 64)
+	if err != nil {
+		panic("h2c: internal error: " + err.Error())
+	}
+	e, _ := ristretto255.NewIdentityElement().SetUniformBytes(b)
+	return e
+}
+
+// HashToScalar maps msg to a Scalar by reducing 64 bytes of
+// expand_message_xmd output with SHA-512, as done by the ristretto255
+// ciphersuites of RFC 9497 and RFC 9807.
+func HashToScalar(msg, dst []byte) *ristretto255.Scalar {
+	b, err := ExpandMessageXMD(sha512.New, msg, dst, 64)
+	if err != nil {
+		panic("h2c: internal error: " + err.Error())
+	}
+	s, _ := ristretto255.NewScalar().SetUniformBytes(b)
+	return s
+}
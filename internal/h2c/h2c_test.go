@@ -0,0 +1,38 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package h2c
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// Test vectors from RFC 9380, Appendix K.3.
+func TestExpandMessageXMD(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA512-256")
+	for _, tc := range []struct {
+		msg string
+		n   int
+		out string
+	}{
+		{"", 0x20, "6b9a7312411d92f921c6f68ca0b6380730a1a4d982c507211a90964c394179ba"},
+		{"abc", 0x20, "0da749f12fbe5483eb066a5f595055679b976e93abe9be6f0f6318bce7aca8dc"},
+		{"q128_" + strings.Repeat("q", 128), 0x20, "7336234ee9983902440f6bc35b348352013becd88938d2afec44311caf8356b3"},
+		{"abc", 0x80, "7f1dddd13c08b543f2e2037b14cefb255b44c83cc397c1786d975653e36a6b11" +
+			"bdd7732d8b38adb4a0edc26a0cef4bb45217135456e58fbca1703cd6032cb134" +
+			"7ee720b87972d63fbf232587043ed2901bce7f22610c0419751c065922b48843" +
+			"1851041310ad659e4b23520e1772ab29dcdeb2002222a363f0c2b1c972b3efe1"},
+	} {
+		out, err := ExpandMessageXMD(sha512.New, []byte(tc.msg), dst, tc.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(out); got != tc.out {
+			t.Errorf("msg %q, len %d: got %s, want %s", tc.msg, tc.n, got, tc.out)
+		}
+	}
+}
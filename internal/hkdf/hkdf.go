@@ -0,0 +1,43 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hkdf implements the HMAC-based key derivation function from
+// RFC 5869, as used by the key exchange protocols in this module.
+package hkdf
+
+import (
+	"crypto/hmac"
+	"hash"
+)
+
+// Extract implements HKDF-Extract with the hash function h. An empty salt is
+// replaced by a string of zeros of the hash length.
+func Extract(h func() hash.Hash, salt, ikm []byte) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, h().Size())
+	}
+	mac := hmac.New(h, salt)
+	mac.Write(ikm)
+	return mac.Sum(nil)
+}
+
+// Expand implements HKDF-Expand with the hash function h, returning n bytes.
+// It panics if n is larger than 255 times the hash length.
+func Expand(h func() hash.Hash, prk, info []byte, n int) []byte {
+	mac := hmac.New(h, prk)
+	if n > 255*mac.Size() {
+		panic("hkdf: requested output is too long")
+	}
+	out := make([]byte, 0, n+mac.Size())
+	var prev []byte
+	for i := byte(1); len(out) < n; i++ {
+		mac.Reset()
+		mac.Write(prev)
+		mac.Write(info)
+		mac.Write([]byte{i})
+		prev = mac.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:n]
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hkdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+// Test vectors from RFC 5869, Appendix A.1 and A.3.
+func TestRFC5869Vectors(t *testing.T) {
+	for _, tc := range []struct {
+		ikm, salt, info string
+		n               int
+		prk, okm        string
+	}{
+		{
+			ikm:  "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			salt: "000102030405060708090a0b0c",
+			info: "f0f1f2f3f4f5f6f7f8f9",
+			n:    42,
+			prk:  "077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5",
+			okm:  "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+		},
+		{
+			ikm:  "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			salt: "",
+			info: "",
+			n:    42,
+			prk:  "19ef24a32c717b167f33a91d6f648bdf96596776afdb6377ac434c1c293ccb04",
+			okm:  "8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8",
+		},
+	} {
+		ikm, _ := hex.DecodeString(tc.ikm)
+		salt, _ := hex.DecodeString(tc.salt)
+		info, _ := hex.DecodeString(tc.info)
+		prk := Extract(sha256.New, salt, ikm)
+		if got := hex.EncodeToString(prk); got != tc.prk {
+			t.Errorf("got PRK %s, want %s", got, tc.prk)
+		}
+		okm := Expand(sha256.New, prk, info, tc.n)
+		if got := hex.EncodeToString(okm); got != tc.okm {
+			t.Errorf("got OKM %s, want %s", got, tc.okm)
+		}
+		if !bytes.Equal(Expand(sha256.New, prk, info, 10), okm[:10]) {
+			t.Error("short output is not a prefix of the long output")
+		}
+	}
+}
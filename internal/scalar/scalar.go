@@ -0,0 +1,114 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package scalar provides small helpers for working with ristretto255
+// Scalars that are shared by the protocol packages in this module.
+package scalar
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"github.com/gtank/ristretto255"
+)
+
+// Random returns a uniformly distributed Scalar read from r. If r is nil,
+// crypto/rand.Reader is used.
+func Random(r io.Reader) (*ristretto255.Scalar, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	var b [64]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+	return ristretto255.NewScalar().SetUniformBytes(b[:])
+}
+
+// RandomNonZero is like Random, but never returns zero, which is not a
+// valid private key or nonce in most protocols.
+func RandomNonZero(r io.Reader) (*ristretto255.Scalar, error) {
+	for {
+		s, err := Random(r)
+		if err != nil {
+			return nil, err
+		}
+		if !IsZero(s) {
+			return s, nil
+		}
+	}
+}
+
+// MustRandom is like Random with crypto/rand.Reader, but panics if the
+// system random number generator fails.
+func MustRandom() *ristretto255.Scalar {
+	s, err := Random(nil)
+	if err != nil {
+		panic("ristretto255: failed to read random bytes: " + err.Error())
+	}
+	return s
+}
+
+// FromUint64 returns a new Scalar set to v.
+func FromUint64(v uint64) *ristretto255.Scalar {
+	var b [32]byte
+	binary.LittleEndian.PutUint64(b[:8], v)
+	s, err := ristretto255.NewScalar().SetCanonicalBytes(b[:])
+	if err != nil {
+		panic("ristretto255: internal error: small scalar is not canonical")
+	}
+	return s
+}
+
+// FromInt64 returns a new Scalar set to v mod l.
+func FromInt64(v int64) *ristretto255.Scalar {
+	if v >= 0 {
+		return FromUint64(uint64(v))
+	}
+	return ristretto255.NewScalar().Negate(FromUint64(uint64(-v)))
+}
+
+// One returns a new Scalar set to 1.
+func One() *ristretto255.Scalar {
+	return FromUint64(1)
+}
+
+// IsZero reports whether s is zero.
+func IsZero(s *ristretto255.Scalar) bool {
+	return s.Equal(ristretto255.NewScalar()) == 1
+}
+
+// Powers returns [1, x, x^2, ..., x^(n-1)].
+func Powers(x *ristretto255.Scalar, n int) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, n)
+	acc := One()
+	for i := range out {
+		out[i] = ristretto255.NewScalar().Set(acc)
+		acc.Multiply(acc, x)
+	}
+	return out
+}
+
+// InnerProduct returns sum(a[i] * b[i]). The slices must have equal length.
+func InnerProduct(a, b []*ristretto255.Scalar) *ristretto255.Scalar {
+	if len(a) != len(b) {
+		panic("ristretto255: InnerProduct invoked with mismatched slice lengths")
+	}
+	out := ristretto255.NewScalar()
+	t := ristretto255.NewScalar()
+	for i := range a {
+		out.Add(out, t.Multiply(a[i], b[i]))
+	}
+	return out
+}
+
+// Sum returns the sum of all the Scalars in s.
+func Sum(s []*ristretto255.Scalar) *ristretto255.Scalar {
+	out := ristretto255.NewScalar()
+	for _, x := range s {
+		out.Add(out, x)
+	}
+	return out
+}
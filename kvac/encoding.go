@@ -0,0 +1,256 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kvac
+
+import (
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/elgamal"
+	"github.com/gtank/ristretto255/sigma"
+)
+
+// The messages are encoded as the concatenation of their Elements and
+// Scalars, in order, followed by the challenge and responses of the proof.
+// Their shape depends on the Params, so they are decoded by Params methods.
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) elements(es ...*ristretto255.Element) {
+	for _, el := range es {
+		e.buf = append(e.buf, el.Bytes()...)
+	}
+}
+
+func (e *encoder) scalars(ss ...*ristretto255.Scalar) {
+	for _, s := range ss {
+		e.buf = append(e.buf, s.Bytes()...)
+	}
+}
+
+func (e *encoder) proof(p *sigma.CompactProof) {
+	e.scalars(p.Challenge)
+	e.scalars(p.Responses...)
+}
+
+// decoder reads canonical Elements and Scalars from buf, recording the first
+// error in err, including running out of input.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) next() []byte {
+	if len(d.buf) < 32 {
+		d.err = errFormat
+		return make([]byte, 32)
+	}
+	b := d.buf[:32]
+	d.buf = d.buf[32:]
+	return b
+}
+
+func (d *decoder) element() *ristretto255.Element {
+	el, err := ristretto255.NewIdentityElement().SetCanonicalBytes(d.next())
+	if err != nil {
+		d.err = errFormat
+	}
+	return el
+}
+
+func (d *decoder) elements(n int) []*ristretto255.Element {
+	out := make([]*ristretto255.Element, n)
+	for i := range out {
+		out[i] = d.element()
+	}
+	return out
+}
+
+func (d *decoder) scalar() *ristretto255.Scalar {
+	s, err := ristretto255.NewScalar().SetCanonicalBytes(d.next())
+	if err != nil {
+		d.err = errFormat
+	}
+	return s
+}
+
+func (d *decoder) scalars(n int) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, n)
+	for i := range out {
+		out[i] = d.scalar()
+	}
+	return out
+}
+
+func (d *decoder) ciphertext() *elgamal.Ciphertext {
+	return &elgamal.Ciphertext{C1: d.element(), C2: d.element()}
+}
+
+// proof decodes the rest of the input as a challenge and responses.
+func (d *decoder) proof() *sigma.CompactProof {
+	if len(d.buf) < 32 || len(d.buf)%32 != 0 {
+		d.err = errFormat
+		return nil
+	}
+	s := d.scalars(len(d.buf) / 32)
+	return &sigma.CompactProof{Challenge: s[0], Responses: s[1:]}
+}
+
+// attribute encodes a as a Scalar or an Element, according to its kind.
+func (e *encoder) attribute(a *Attribute) {
+	if a.s != nil {
+		e.scalars(a.s)
+	} else {
+		e.elements(a.e)
+	}
+}
+
+func (d *decoder) attribute(k Kind) *Attribute {
+	if k == ScalarKind {
+		return &Attribute{s: d.scalar()}
+	}
+	return &Attribute{e: d.element()}
+}
+
+// Bytes returns the encoding of the issuance, t || U || V || proof.
+func (iss *Issuance) Bytes() []byte {
+	var e encoder
+	e.scalars(iss.T)
+	e.elements(iss.U, iss.V)
+	e.proof(iss.Proof)
+	return e.buf
+}
+
+// ParseIssuance decodes an Issuance for p.
+func (p *Params) ParseIssuance(b []byte) (*Issuance, error) {
+	d := decoder{buf: b}
+	iss := &Issuance{T: d.scalar(), U: d.element(), V: d.element()}
+	iss.Proof = d.proof()
+	if d.err != nil {
+		return nil, errFormat
+	}
+	return iss, nil
+}
+
+// Bytes returns the encoding of the request: D, then for each attribute a
+// zero byte followed by the attribute, or a one byte followed by its
+// encryption, then the proof.
+func (req *BlindRequest) Bytes() []byte {
+	var e encoder
+	e.elements(req.D)
+	for i := range req.Revealed {
+		if req.Revealed[i] != nil {
+			e.buf = append(e.buf, 0)
+			e.attribute(req.Revealed[i])
+		} else {
+			e.buf = append(e.buf, 1)
+			e.buf = append(e.buf, req.Encrypted[i].Bytes()...)
+		}
+	}
+	e.proof(req.Proof)
+	return e.buf
+}
+
+// ParseBlindRequest decodes a BlindRequest for p.
+func (p *Params) ParseBlindRequest(b []byte) (*BlindRequest, error) {
+	d := decoder{buf: b}
+	req := &BlindRequest{
+		D:         d.element(),
+		Revealed:  make([]*Attribute, p.NumAttributes()),
+		Encrypted: make([]*elgamal.Ciphertext, p.NumAttributes()),
+	}
+	for i := range p.kinds {
+		if d.err != nil || len(d.buf) == 0 {
+			return nil, errFormat
+		}
+		tag := d.buf[0]
+		d.buf = d.buf[1:]
+		switch tag {
+		case 0:
+			req.Revealed[i] = d.attribute(p.kinds[i])
+		case 1:
+			req.Encrypted[i] = d.ciphertext()
+		default:
+			return nil, errFormat
+		}
+	}
+	req.Proof = d.proof()
+	if d.err != nil {
+		return nil, errFormat
+	}
+	return req, nil
+}
+
+// Bytes returns the encoding of the issuance, t || U || EV || proof.
+func (iss *BlindIssuance) Bytes() []byte {
+	var e encoder
+	e.scalars(iss.T)
+	e.elements(iss.U, iss.EV.C1, iss.EV.C2)
+	e.proof(iss.Proof)
+	return e.buf
+}
+
+// ParseBlindIssuance decodes a BlindIssuance for p.
+func (p *Params) ParseBlindIssuance(b []byte) (*BlindIssuance, error) {
+	d := decoder{buf: b}
+	iss := &BlindIssuance{T: d.scalar(), U: d.element(), EV: d.ciphertext()}
+	iss.Proof = d.proof()
+	if d.err != nil {
+		return nil, errFormat
+	}
+	return iss, nil
+}
+
+// Bytes returns the encoding of the presentation,
+// C_x0 || C_x1 || C_V || C_y_1 || ... || C_y_n || proof.
+func (pres *Presentation) Bytes() []byte {
+	var e encoder
+	e.elements(pres.Cx0, pres.Cx1, pres.CV)
+	e.elements(pres.Cy...)
+	e.proof(pres.Proof)
+	return e.buf
+}
+
+// ParsePresentation decodes a Presentation for p.
+func (p *Params) ParsePresentation(b []byte) (*Presentation, error) {
+	d := decoder{buf: b}
+	pres := &Presentation{Cx0: d.element(), Cx1: d.element(), CV: d.element()}
+	pres.Cy = d.elements(p.NumAttributes())
+	pres.Proof = d.proof()
+	if d.err != nil {
+		return nil, errFormat
+	}
+	return pres, nil
+}
+
+// Bytes returns the encoding of the credential, t || U || V followed by the
+// attributes.
+func (c *Credential) Bytes() []byte {
+	var e encoder
+	e.scalars(c.t)
+	e.elements(c.U, c.V)
+	for _, a := range c.attrs {
+		e.attribute(a)
+	}
+	return e.buf
+}
+
+// ParseCredential decodes a Credential issued under pk. It does not check
+// the MAC, which only the issuer can do.
+func (pk *PublicKey) ParseCredential(b []byte) (*Credential, error) {
+	p := pk.params
+	if len(b) != 32*(3+p.NumAttributes()) {
+		return nil, errFormat
+	}
+	d := decoder{buf: b}
+	c := &Credential{pk: pk, t: d.scalar(), U: d.element(), V: d.element()}
+	for i := range p.kinds {
+		c.attrs = append(c.attrs, d.attribute(p.kinds[i]))
+	}
+	if d.err != nil {
+		return nil, errFormat
+	}
+	return c, nil
+}
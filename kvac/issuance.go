@@ -0,0 +1,322 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kvac
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/elgamal"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/sigma"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+// Issuance is the issuer's response to a clear issuance request: the MAC
+// (t, U, V) and a proof that it was computed with the secret key committed
+// to in the PublicKey.
+type Issuance struct {
+	T     *ristretto255.Scalar
+	U, V  *ristretto255.Element
+	Proof *sigma.CompactProof
+}
+
+// macSecrets returns fresh t and U = b * G.
+func macSecrets() (*ristretto255.Scalar, *ristretto255.Element, error) {
+	t, err := scalar.Random(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := scalar.Random(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, ristretto255.NewIdentityElement().ScalarBaseMult(b), nil
+}
+
+// mac returns W + (x0 + x1 * t) * U + sum(y_i * M_i), over the i for which
+// M[i] is not nil.
+func (sk *SecretKey) mac(t *ristretto255.Scalar, U *ristretto255.Element, M []*ristretto255.Element) *ristretto255.Element {
+	x := ristretto255.NewScalar().Multiply(sk.x1, t)
+	scalars := []*ristretto255.Scalar{x.Add(x, sk.x0)}
+	points := []*ristretto255.Element{U}
+	for i := range M {
+		if M[i] != nil {
+			scalars = append(scalars, sk.y[i])
+			points = append(points, M[i])
+		}
+	}
+	V := ristretto255.NewIdentityElement().MultiScalarMult(scalars, points)
+	return V.Add(V, sk.W)
+}
+
+func issuanceStatement(pk *PublicKey, sk *SecretKey, M []*ristretto255.Element, iss *Issuance) *statement {
+	s := newStatement("kvac-v1 issuance")
+	k := keyStatement(s, pk, sk)
+	terms := []sigma.Term{
+		{Scalar: k.w, Point: k.Gw},
+		{Scalar: k.x0, Point: s.point("U", iss.U)},
+		{Scalar: k.x1, Point: s.point("tU", ristretto255.NewIdentityElement().ScalarMult(iss.T, iss.U))},
+	}
+	for i := range M {
+		terms = append(terms, sigma.Term{Scalar: k.y[i], Point: s.point(fmt.Sprintf("M%d", i), M[i])})
+	}
+	s.r.Constrain(s.point("V", iss.V), terms...)
+	return s
+}
+
+func (p *Params) elements(attrs []*Attribute) []*ristretto255.Element {
+	M := make([]*ristretto255.Element, len(attrs))
+	for i, a := range attrs {
+		M[i] = p.element(i, a)
+	}
+	return M
+}
+
+// Issue returns a credential on attrs, all known to the issuer.
+func (sk *SecretKey) Issue(attrs []*Attribute) (*Issuance, error) {
+	p := sk.params
+	if err := p.checkAttributes(attrs, false); err != nil {
+		return nil, err
+	}
+	t, U, err := macSecrets()
+	if err != nil {
+		return nil, err
+	}
+	M := p.elements(attrs)
+	iss := &Issuance{T: t, U: U, V: sk.mac(t, U, M)}
+	if iss.Proof, err = issuanceStatement(sk.pk, sk, M, iss).prove(transcript.New("kvac-v1")); err != nil {
+		return nil, err
+	}
+	return iss, nil
+}
+
+// VerifyIssuance checks the issuer's proof, and returns the Credential on
+// attrs.
+func (pk *PublicKey) VerifyIssuance(attrs []*Attribute, iss *Issuance) (*Credential, error) {
+	p := pk.params
+	if err := p.checkAttributes(attrs, false); err != nil {
+		return nil, err
+	}
+	if iss.U.Equal(ristretto255.NewIdentityElement()) == 1 {
+		return nil, errVerification
+	}
+	if err := issuanceStatement(pk, nil, p.elements(attrs), iss).verify(transcript.New("kvac-v1"), iss.Proof); err != nil {
+		return nil, err
+	}
+	return &Credential{pk: pk, attrs: attrs, t: iss.T, U: iss.U, V: iss.V}, nil
+}
+
+// BlindRequest is a request for a credential on attributes some of which
+// are hidden from the issuer, encrypted with ElGamal under the ephemeral
+// key D, together with a proof that the user knows them.
+type BlindRequest struct {
+	D *ristretto255.Element
+	// Revealed holds the attributes in the clear, and nil for hidden ones.
+	Revealed []*Attribute
+	// Encrypted holds the encryptions of the hidden attributes M_i, and nil
+	// for revealed ones.
+	Encrypted []*elgamal.Ciphertext
+	Proof     *sigma.CompactProof
+}
+
+// BlindRequestState is the user's state for a blind issuance.
+type BlindRequestState struct {
+	pk    *PublicKey
+	attrs []*Attribute
+	d     *elgamal.PrivateKey
+	req   *BlindRequest
+}
+
+// requestStatement declares the statement of a BlindRequest: D = d * G,
+// and for each hidden attribute E_i = (r_i * G, m_i * G_m_i + r_i * D) if it
+// is a Scalar, or E_i = (r_i * G, M_i + r_i * D) if it is an Element. d, r
+// and attrs are nil for the verifier.
+func requestStatement(pk *PublicKey, req *BlindRequest, d *ristretto255.Scalar, r []*ristretto255.Scalar, attrs []*Attribute) *statement {
+	p := pk.params
+	if r == nil {
+		r = make([]*ristretto255.Scalar, p.NumAttributes())
+	}
+	s := newStatement("kvac-v1 blind request")
+	s.point("C_W", pk.CW)
+	s.point("I", pk.I)
+	G := s.point("G", ristretto255.NewGeneratorElement())
+	D := s.point("D", req.D)
+	s.r.Constrain(D, sigma.Term{Scalar: s.secret("d", d), Point: G})
+	for i := range p.kinds {
+		if req.Revealed[i] != nil {
+			s.point(fmt.Sprintf("M%d", i), p.element(i, req.Revealed[i]))
+			continue
+		}
+		E := req.Encrypted[i]
+		ri := s.secret(fmt.Sprintf("r%d", i), r[i])
+		s.r.Constrain(s.point(fmt.Sprintf("E%d_1", i), E.C1), sigma.Term{Scalar: ri, Point: G})
+		E2 := s.point(fmt.Sprintf("E%d_2", i), E.C2)
+		if p.kinds[i] == ScalarKind {
+			var m *ristretto255.Scalar
+			if attrs != nil {
+				m = attrs[i].s
+			}
+			mi := s.secret(fmt.Sprintf("m%d", i), m)
+			s.r.Constrain(E2,
+				sigma.Term{Scalar: mi, Point: s.point(fmt.Sprintf("G_m%d", i), p.Gm[i])},
+				sigma.Term{Scalar: ri, Point: D})
+		}
+	}
+	return s
+}
+
+// NewBlindRequest returns a request for a credential on attrs from the
+// issuer with public key pk, where the attributes with hidden[i] set are
+// not revealed to the issuer.
+func NewBlindRequest(pk *PublicKey, attrs []*Attribute, hidden []bool) (*BlindRequestState, *BlindRequest, error) {
+	p := pk.params
+	if err := p.checkAttributes(attrs, false); err != nil {
+		return nil, nil, err
+	}
+	if len(hidden) != len(attrs) {
+		return nil, nil, errors.New("kvac: mismatched hidden attributes mask")
+	}
+	d, err := elgamal.GenerateKey(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req := &BlindRequest{
+		D:         d.Public().P,
+		Revealed:  make([]*Attribute, len(attrs)),
+		Encrypted: make([]*elgamal.Ciphertext, len(attrs)),
+	}
+	r := make([]*ristretto255.Scalar, len(attrs))
+	for i, a := range attrs {
+		if !hidden[i] {
+			req.Revealed[i] = a
+			continue
+		}
+		if req.Encrypted[i], r[i], err = d.Public().Encrypt(p.element(i, a)); err != nil {
+			return nil, nil, err
+		}
+	}
+	if req.Proof, err = requestStatement(pk, req, d.Scalar(), r, attrs).prove(transcript.New("kvac-v1")); err != nil {
+		return nil, nil, err
+	}
+	return &BlindRequestState{pk: pk, attrs: attrs, d: d, req: req}, req, nil
+}
+
+// checkBlindRequest checks the structure and the proof of req.
+func (pk *PublicKey) checkBlindRequest(req *BlindRequest) error {
+	p := pk.params
+	if len(req.Revealed) != p.NumAttributes() || len(req.Encrypted) != p.NumAttributes() {
+		return errAttributes
+	}
+	for i := range p.kinds {
+		if (req.Revealed[i] == nil) == (req.Encrypted[i] == nil) {
+			return errAttributes
+		}
+		if req.Revealed[i] != nil && !p.checkAttribute(i, req.Revealed[i]) {
+			return errAttributes
+		}
+	}
+	if req.D.Equal(ristretto255.NewIdentityElement()) == 1 {
+		return errVerification
+	}
+	return requestStatement(pk, req, nil, nil, nil).verify(transcript.New("kvac-v1"), req.Proof)
+}
+
+// BlindIssuance is the issuer's response to a BlindRequest: t, U, the
+// encryption of V under D, and a proof that it was computed with the secret
+// key committed to in the PublicKey.
+type BlindIssuance struct {
+	T     *ristretto255.Scalar
+	U     *ristretto255.Element
+	EV    *elgamal.Ciphertext
+	Proof *sigma.CompactProof
+}
+
+// blindIssuanceStatement declares
+//
+//	EV_1 = s * G + sum_hidden(y_i * E_i_1)
+//	EV_2 = w * G_w + x0 * U + x1 * tU + sum_revealed(y_i * M_i) + s * D + sum_hidden(y_i * E_i_2)
+//
+// where s is the randomness of the encryption of V. sk and sRand are nil
+// for the verifier.
+func blindIssuanceStatement(pk *PublicKey, sk *SecretKey, sRand *ristretto255.Scalar, req *BlindRequest, iss *BlindIssuance) *statement {
+	p := pk.params
+	st := newStatement("kvac-v1 blind issuance")
+	k := keyStatement(st, pk, sk)
+	s := st.secret("s", sRand)
+	G := st.point("G", ristretto255.NewGeneratorElement())
+	D := st.point("D", req.D)
+	terms1 := []sigma.Term{{Scalar: s, Point: G}}
+	terms2 := []sigma.Term{
+		{Scalar: k.w, Point: k.Gw},
+		{Scalar: k.x0, Point: st.point("U", iss.U)},
+		{Scalar: k.x1, Point: st.point("tU", ristretto255.NewIdentityElement().ScalarMult(iss.T, iss.U))},
+		{Scalar: s, Point: D},
+	}
+	for i := range p.kinds {
+		if req.Revealed[i] != nil {
+			M := st.point(fmt.Sprintf("M%d", i), p.element(i, req.Revealed[i]))
+			terms2 = append(terms2, sigma.Term{Scalar: k.y[i], Point: M})
+			continue
+		}
+		E := req.Encrypted[i]
+		terms1 = append(terms1, sigma.Term{Scalar: k.y[i], Point: st.point(fmt.Sprintf("E%d_1", i), E.C1)})
+		terms2 = append(terms2, sigma.Term{Scalar: k.y[i], Point: st.point(fmt.Sprintf("E%d_2", i), E.C2)})
+	}
+	st.r.Constrain(st.point("EV_1", iss.EV.C1), terms1...)
+	st.r.Constrain(st.point("EV_2", iss.EV.C2), terms2...)
+	return st
+}
+
+// IssueBlind checks req, and returns a credential on its attributes.
+func (sk *SecretKey) IssueBlind(req *BlindRequest) (*BlindIssuance, error) {
+	p := sk.params
+	if err := sk.pk.checkBlindRequest(req); err != nil {
+		return nil, err
+	}
+	t, U, err := macSecrets()
+	if err != nil {
+		return nil, err
+	}
+	s, err := scalar.Random(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// EV = E(W + (x0 + x1 * t) * U + sum_revealed(y_i * M_i); s) +
+	// sum_hidden(y_i * E_i).
+	M := make([]*ristretto255.Element, p.NumAttributes())
+	for i, a := range req.Revealed {
+		if a != nil {
+			M[i] = p.element(i, a)
+		}
+	}
+	D := &elgamal.PublicKey{P: req.D}
+	EV := D.EncryptWithRandomness(sk.mac(t, U, M), s)
+	for i, E := range req.Encrypted {
+		if E != nil {
+			EV.Add(EV, elgamal.NewCiphertext().ScalarMult(sk.y[i], E))
+		}
+	}
+
+	iss := &BlindIssuance{T: t, U: U, EV: EV}
+	if iss.Proof, err = blindIssuanceStatement(sk.pk, sk, s, req, iss).prove(transcript.New("kvac-v1")); err != nil {
+		return nil, err
+	}
+	return iss, nil
+}
+
+// Finish checks the issuer's proof, and returns the Credential obtained by
+// decrypting V.
+func (st *BlindRequestState) Finish(iss *BlindIssuance) (*Credential, error) {
+	if iss.U.Equal(ristretto255.NewIdentityElement()) == 1 {
+		return nil, errVerification
+	}
+	if err := blindIssuanceStatement(st.pk, nil, nil, st.req, iss).verify(transcript.New("kvac-v1"), iss.Proof); err != nil {
+		return nil, err
+	}
+	return &Credential{pk: st.pk, attrs: st.attrs, t: iss.T, U: iss.U, V: st.d.Decrypt(iss.EV)}, nil
+}
@@ -0,0 +1,327 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package kvac implements keyed-verification anonymous credentials based on
+// the algebraic MAC of Chase, Perrin and Zaverucha, "The Signal Private
+// Group System and Anonymous Credentials Supporting Efficient Verifiable
+// Encryption" (CCS 2020), which refines MAC_GGM from Chase, Meiklejohn and
+// Zaverucha (CCS 2014).
+//
+// A credential is a MAC (t, U, V) over a list of attributes, each either a
+// Scalar m, encoded as the Element M = m * G_m, or an Element M:
+//
+//	V = W + (x0 + x1 * t) * U + sum(y_i * M_i)
+//
+// The issuer holds the secret key, and is also the verifier: presentations
+// can only be checked with the secret key. Credentials can be issued in the
+// clear, or blindly, with some attributes encrypted to the issuer. In both
+// cases the issuer proves that the MAC was computed with the key committed
+// to in the PublicKey, so that it can't tag users with per-user keys.
+//
+// A Presentation proves possession of a credential in zero knowledge,
+// revealing a chosen subset of the attributes. Presentations of the same
+// credential are unlinkable. Hidden Scalar attributes are committed to as
+// C_y_i = z * G_y_i + m_i * G_m_i, so applications can extend the proof with
+// statements about them.
+package kvac
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/h2c"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/sigma"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+const generatorDST = "ristretto255-KVAC-v1-generators"
+
+var (
+	errVerification = errors.New("kvac: verification failed")
+	errFormat       = errors.New("kvac: malformed encoding")
+	errAttributes   = errors.New("kvac: attributes do not match parameters")
+)
+
+// Kind is the type of an attribute.
+type Kind int
+
+const (
+	// ScalarKind attributes are Scalars, encoded as m * G_m.
+	ScalarKind Kind = iota
+	// ElementKind attributes are arbitrary Elements.
+	ElementKind
+)
+
+// Params are the public parameters of a credential type: the kinds of its
+// attributes, and the generators derived from them.
+type Params struct {
+	kinds                 []Kind
+	Gw, Gwp, Gx0, Gx1, GV *ristretto255.Element
+	Gy, Gm                []*ristretto255.Element
+}
+
+// NewParams returns the parameters for credentials with attributes of the
+// given kinds, in order. The generators are derived with
+// hash_to_ristretto255, so their discrete logs are unknown to everyone.
+func NewParams(kinds ...Kind) (*Params, error) {
+	if len(kinds) == 0 {
+		return nil, errors.New("kvac: credentials need at least one attribute")
+	}
+	for _, k := range kinds {
+		if k != ScalarKind && k != ElementKind {
+			return nil, errors.New("kvac: unknown attribute kind")
+		}
+	}
+	gen := func(name string) *ristretto255.Element {
+		return h2c.HashToElement([]byte(name), []byte(generatorDST))
+	}
+	p := &Params{
+		kinds: append([]Kind(nil), kinds...),
+		Gw:    gen("G_w"),
+		Gwp:   gen("G_w'"),
+		Gx0:   gen("G_x0"),
+		Gx1:   gen("G_x1"),
+		GV:    gen("G_V"),
+	}
+	for i := range kinds {
+		p.Gy = append(p.Gy, gen(fmt.Sprintf("G_y%d", i)))
+		p.Gm = append(p.Gm, gen(fmt.Sprintf("G_m%d", i)))
+	}
+	return p, nil
+}
+
+// NumAttributes returns the number of attributes of credentials for p.
+func (p *Params) NumAttributes() int { return len(p.kinds) }
+
+// Kind returns the kind of the i-th attribute.
+func (p *Params) Kind(i int) Kind { return p.kinds[i] }
+
+// Attribute is a credential attribute, either a Scalar or an Element.
+type Attribute struct {
+	s *ristretto255.Scalar
+	e *ristretto255.Element
+}
+
+// NewScalarAttribute returns an attribute of ScalarKind.
+func NewScalarAttribute(m *ristretto255.Scalar) *Attribute {
+	return &Attribute{s: ristretto255.NewScalar().Set(m)}
+}
+
+// NewElementAttribute returns an attribute of ElementKind.
+func NewElementAttribute(M *ristretto255.Element) *Attribute {
+	return &Attribute{e: ristretto255.NewIdentityElement().Set(M)}
+}
+
+// Scalar returns the value of a ScalarKind attribute, or nil.
+func (a *Attribute) Scalar() *ristretto255.Scalar { return a.s }
+
+// Element returns the value of an ElementKind attribute, or nil.
+func (a *Attribute) Element() *ristretto255.Element { return a.e }
+
+// element returns M_i, the Element the i-th attribute is MACed as.
+func (p *Params) element(i int, a *Attribute) *ristretto255.Element {
+	if p.kinds[i] == ScalarKind {
+		return ristretto255.NewIdentityElement().ScalarMult(a.s, p.Gm[i])
+	}
+	return a.e
+}
+
+// checkAttribute reports whether a is a valid i-th attribute.
+func (p *Params) checkAttribute(i int, a *Attribute) bool {
+	if p.kinds[i] == ScalarKind {
+		return a.s != nil && a.e == nil
+	}
+	return a.e != nil && a.s == nil
+}
+
+// checkAttributes checks attrs against p. If partial is true, nil entries
+// are allowed, for attributes that are not revealed.
+func (p *Params) checkAttributes(attrs []*Attribute, partial bool) error {
+	if len(attrs) != len(p.kinds) {
+		return errAttributes
+	}
+	for i, a := range attrs {
+		if a == nil && partial {
+			continue
+		}
+		if a == nil || !p.checkAttribute(i, a) {
+			return errAttributes
+		}
+	}
+	return nil
+}
+
+// SecretKey is the issuer's secret key (w, w', x0, x1, y_1, ..., y_n).
+type SecretKey struct {
+	params        *Params
+	w, wp, x0, x1 *ristretto255.Scalar
+	y             []*ristretto255.Scalar
+	W             *ristretto255.Element
+	pk            *PublicKey
+}
+
+// PublicKey is the issuer's public commitment to its secret key,
+// C_W = w * G_w + w' * G_w' and I = G_V - x0 * G_x0 - x1 * G_x1 -
+// sum(y_i * G_y_i).
+type PublicKey struct {
+	params *Params
+	CW, I  *ristretto255.Element
+}
+
+// GenerateKey returns a new SecretKey for params, reading randomness from
+// rand. If rand is nil, crypto/rand.Reader is used.
+func GenerateKey(params *Params, rand io.Reader) (*SecretKey, error) {
+	s := make([]*ristretto255.Scalar, 4+params.NumAttributes())
+	for i := range s {
+		var err error
+		if s[i], err = scalar.Random(rand); err != nil {
+			return nil, err
+		}
+	}
+	return newSecretKey(params, s), nil
+}
+
+func newSecretKey(params *Params, s []*ristretto255.Scalar) *SecretKey {
+	sk := &SecretKey{params: params, w: s[0], wp: s[1], x0: s[2], x1: s[3], y: s[4:]}
+	sk.W = ristretto255.NewIdentityElement().ScalarMult(sk.w, params.Gw)
+
+	CW := ristretto255.NewIdentityElement().MultiScalarMult(
+		[]*ristretto255.Scalar{sk.w, sk.wp}, []*ristretto255.Element{params.Gw, params.Gwp})
+	scalars := append([]*ristretto255.Scalar{sk.x0, sk.x1}, sk.y...)
+	points := append([]*ristretto255.Element{params.Gx0, params.Gx1}, params.Gy...)
+	I := ristretto255.NewIdentityElement().MultiScalarMult(scalars, points)
+	sk.pk = &PublicKey{params: params, CW: CW, I: I.Subtract(params.GV, I)}
+	return sk
+}
+
+// Public returns the PublicKey corresponding to sk.
+func (sk *SecretKey) Public() *PublicKey { return sk.pk }
+
+// Bytes returns the encoding of the secret key, w || w' || x0 || x1 || y_1
+// || ... || y_n.
+func (sk *SecretKey) Bytes() []byte {
+	var e encoder
+	e.scalars(sk.w, sk.wp, sk.x0, sk.x1)
+	e.scalars(sk.y...)
+	return e.buf
+}
+
+// NewSecretKey decodes a SecretKey for p from its encoding.
+func (p *Params) NewSecretKey(b []byte) (*SecretKey, error) {
+	if len(b) != 32*(4+p.NumAttributes()) {
+		return nil, errFormat
+	}
+	d := decoder{buf: b}
+	s := d.scalars(4 + p.NumAttributes())
+	if d.err != nil {
+		return nil, errFormat
+	}
+	return newSecretKey(p, s), nil
+}
+
+// Bytes returns the 64-byte encoding of the public key, C_W || I.
+func (pk *PublicKey) Bytes() []byte {
+	return append(pk.CW.Bytes(), pk.I.Bytes()...)
+}
+
+// NewPublicKey decodes a PublicKey for p from its 64-byte encoding.
+func (p *Params) NewPublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != 64 {
+		return nil, errFormat
+	}
+	d := decoder{buf: b}
+	pk := &PublicKey{params: p, CW: d.element(), I: d.element()}
+	if d.err != nil {
+		return nil, errFormat
+	}
+	return pk, nil
+}
+
+// Credential is a MAC on a list of attributes, held by the user.
+type Credential struct {
+	pk    *PublicKey
+	attrs []*Attribute
+	t     *ristretto255.Scalar
+	U, V  *ristretto255.Element
+}
+
+// Attributes returns the attributes of the credential.
+func (c *Credential) Attributes() []*Attribute { return c.attrs }
+
+// statement builds a sigma.Relation together with its public Elements and,
+// for the prover, its witness.
+type statement struct {
+	r       *sigma.Relation
+	points  []*ristretto255.Element
+	witness []*ristretto255.Scalar
+}
+
+func newStatement(label string) *statement {
+	return &statement{r: sigma.NewRelation(label)}
+}
+
+func (s *statement) secret(name string, x *ristretto255.Scalar) sigma.ScalarVar {
+	s.witness = append(s.witness, x)
+	return s.r.SecretScalar(name)
+}
+
+func (s *statement) point(name string, P *ristretto255.Element) sigma.PointVar {
+	s.points = append(s.points, P)
+	return s.r.Point(name)
+}
+
+func (s *statement) prove(t *transcript.Transcript) (*sigma.CompactProof, error) {
+	return s.r.ProveCompact(t, s.points, s.witness)
+}
+
+func (s *statement) verify(t *transcript.Transcript, proof *sigma.CompactProof) error {
+	if proof == nil || s.r.VerifyCompact(t, s.points, proof) != nil {
+		return errVerification
+	}
+	return nil
+}
+
+// keyVars are the variables of the secret key in a statement.
+type keyVars struct {
+	w, x0, x1 sigma.ScalarVar
+	y         []sigma.ScalarVar
+	Gw        sigma.PointVar
+}
+
+// keyStatement adds the secret key variables to s, and the equations
+// C_W = w * G_w + w' * G_w' and G_V - I = x0 * G_x0 + x1 * G_x1 +
+// sum(y_i * G_y_i). sk is nil for the verifier.
+func keyStatement(s *statement, pk *PublicKey, sk *SecretKey) keyVars {
+	p := pk.params
+	wit := make([]*ristretto255.Scalar, 4+p.NumAttributes())
+	if sk != nil {
+		wit = append([]*ristretto255.Scalar{sk.w, sk.wp, sk.x0, sk.x1}, sk.y...)
+	}
+	v := keyVars{
+		w:  s.secret("w", wit[0]),
+		x0: s.secret("x0", wit[2]),
+		x1: s.secret("x1", wit[3]),
+	}
+	wp := s.secret("w'", wit[1])
+	for i := range p.kinds {
+		v.y = append(v.y, s.secret(fmt.Sprintf("y%d", i), wit[4+i]))
+	}
+
+	v.Gw = s.point("G_w", p.Gw)
+	s.r.Constrain(s.point("C_W", pk.CW),
+		sigma.Term{Scalar: v.w, Point: v.Gw},
+		sigma.Term{Scalar: wp, Point: s.point("G_w'", p.Gwp)})
+	terms := []sigma.Term{
+		{Scalar: v.x0, Point: s.point("G_x0", p.Gx0)},
+		{Scalar: v.x1, Point: s.point("G_x1", p.Gx1)},
+	}
+	for i := range p.kinds {
+		terms = append(terms, sigma.Term{Scalar: v.y[i], Point: s.point(fmt.Sprintf("G_y%d", i), p.Gy[i])})
+	}
+	s.r.Constrain(s.point("G_V-I", ristretto255.NewIdentityElement().Subtract(p.GV, pk.I)), terms...)
+	return v
+}
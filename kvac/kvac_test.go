@@ -0,0 +1,264 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kvac
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/h2c"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+// setup returns a key for credentials on (user ID, expiry, group key).
+func setup(t *testing.T) (*Params, *SecretKey, []*Attribute) {
+	params, err := NewParams(ScalarKind, ScalarKind, ElementKind)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, err := GenerateKey(params, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs := []*Attribute{
+		NewScalarAttribute(scalar.MustRandom()),
+		NewScalarAttribute(scalar.FromUint64(20261231)),
+		NewElementAttribute(h2c.HashToElement([]byte("group 42"), []byte("test"))),
+	}
+	return params, sk, attrs
+}
+
+func context(nonce string) *transcript.Transcript {
+	t := transcript.New("test presentation")
+	t.AppendMessage("nonce", []byte(nonce))
+	return t
+}
+
+func checkPresentations(t *testing.T, sk *SecretKey, cred *Credential) {
+	t.Helper()
+	attrs := cred.Attributes()
+	for _, reveal := range [][]bool{
+		{false, false, false},
+		{false, true, false},
+		{true, true, true},
+		{false, false, true},
+	} {
+		pres, err := cred.Present(context("nonce"), reveal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		revealed := make([]*Attribute, len(attrs))
+		for i := range attrs {
+			if reveal[i] {
+				revealed[i] = attrs[i]
+			}
+		}
+		if err := sk.VerifyPresentation(context("nonce"), revealed, pres); err != nil {
+			t.Fatalf("reveal %v: %v", reveal, err)
+		}
+		if err := sk.VerifyPresentation(context("other nonce"), revealed, pres); err == nil {
+			t.Errorf("reveal %v: presentation verified with another context", reveal)
+		}
+
+		decoded, err := sk.params.ParsePresentation(pres.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sk.VerifyPresentation(context("nonce"), revealed, decoded); err != nil {
+			t.Errorf("reveal %v: decoded presentation: %v", reveal, err)
+		}
+
+		// Claiming a different value for a revealed attribute fails, and so
+		// does claiming to reveal an attribute that the proof hides.
+		for i := range attrs {
+			lie := append([]*Attribute(nil), revealed...)
+			if reveal[i] {
+				lie[i] = otherAttribute(sk.params, i)
+			} else {
+				lie[i] = attrs[i]
+			}
+			if err := sk.VerifyPresentation(context("nonce"), lie, pres); err == nil {
+				t.Errorf("reveal %v: presentation verified with altered attribute %d", reveal, i)
+			}
+		}
+	}
+}
+
+func otherAttribute(p *Params, i int) *Attribute {
+	if p.Kind(i) == ScalarKind {
+		return NewScalarAttribute(scalar.MustRandom())
+	}
+	return NewElementAttribute(ristretto255.NewIdentityElement().ScalarBaseMult(scalar.MustRandom()))
+}
+
+func TestClearIssuance(t *testing.T) {
+	params, sk, attrs := setup(t)
+	iss, err := sk.Issue(attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := params.ParseIssuance(iss.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := sk.Public().VerifyIssuance(attrs, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPresentations(t, sk, cred)
+
+	// A credential on other attributes doesn't verify.
+	other := append([]*Attribute(nil), attrs...)
+	other[1] = NewScalarAttribute(scalar.FromUint64(20991231))
+	if _, err := sk.Public().VerifyIssuance(other, iss); err == nil {
+		t.Error("issuance verified for other attributes")
+	}
+}
+
+func TestIssuerKeyConsistency(t *testing.T) {
+	// An issuer that MACs with a key other than the published one, for
+	// example to tag a user, can't produce a valid issuance proof.
+	_, sk, attrs := setup(t)
+	rogue, _ := GenerateKey(sk.params, nil)
+	iss, err := rogue.Issue(attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sk.Public().VerifyIssuance(attrs, iss); err == nil {
+		t.Error("issuance verified under a different public key")
+	}
+
+	// A presentation of a credential from another key is rejected.
+	cred, _ := rogue.Public().VerifyIssuance(attrs, iss)
+	pres, _ := cred.Present(context("nonce"), []bool{false, false, false})
+	if err := sk.VerifyPresentation(context("nonce"), make([]*Attribute, 3), pres); err == nil {
+		t.Error("presentation verified under a different key")
+	}
+}
+
+func TestForgedCredential(t *testing.T) {
+	_, sk, attrs := setup(t)
+	iss, _ := sk.Issue(attrs)
+	cred, _ := sk.Public().VerifyIssuance(attrs, iss)
+
+	// Changing an attribute of a valid credential invalidates the MAC.
+	cred.attrs = append([]*Attribute(nil), attrs...)
+	cred.attrs[0] = NewScalarAttribute(scalar.MustRandom())
+	pres, err := cred.Present(context("nonce"), []bool{false, false, false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sk.VerifyPresentation(context("nonce"), make([]*Attribute, 3), pres); err == nil {
+		t.Error("presentation of a modified credential verified")
+	}
+}
+
+func TestBlindIssuance(t *testing.T) {
+	params, sk, attrs := setup(t)
+	hidden := []bool{true, false, true}
+	state, req, err := NewBlindRequest(sk.Public(), attrs, hidden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decodedReq, err := params.ParseBlindRequest(req.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decodedReq.Revealed[0] != nil || decodedReq.Revealed[1].Scalar().Equal(attrs[1].Scalar()) != 1 {
+		t.Fatal("request did not round-trip")
+	}
+
+	iss, err := sk.IssueBlind(decodedReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decodedIss, err := params.ParseBlindIssuance(iss.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := state.Finish(decodedIss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPresentations(t, sk, cred)
+
+	stored, err := sk.Public().ParseCredential(cred.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPresentations(t, sk, stored)
+}
+
+func TestBlindRequestRejected(t *testing.T) {
+	_, sk, attrs := setup(t)
+	_, req, err := NewBlindRequest(sk.Public(), attrs, []bool{true, false, false})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Swapping a revealed attribute after the proof was made.
+	swapped := *req
+	swapped.Revealed = append([]*Attribute(nil), req.Revealed...)
+	swapped.Revealed[1] = NewScalarAttribute(scalar.FromUint64(1))
+	if _, err := sk.IssueBlind(&swapped); err == nil {
+		t.Error("issued on a request with an altered revealed attribute")
+	}
+
+	// Replacing the encryption of a hidden attribute.
+	replaced := *req
+	replaced.Encrypted = append(replaced.Encrypted[:0:0], req.Encrypted...)
+	replaced.Encrypted[0] = replaced.Encrypted[0].AddPlaintext(replaced.Encrypted[0], ristretto255.NewGeneratorElement())
+	if _, err := sk.IssueBlind(&replaced); err == nil {
+		t.Error("issued on a request with an altered ciphertext")
+	}
+
+	// A request for a different credential type.
+	other, _ := NewParams(ScalarKind, ScalarKind)
+	otherSK, _ := GenerateKey(other, nil)
+	if _, err := otherSK.IssueBlind(req); err == nil {
+		t.Error("issued on a request with the wrong number of attributes")
+	}
+}
+
+func TestKeyEncoding(t *testing.T) {
+	params, sk, attrs := setup(t)
+	sk2, err := params.NewSecretKey(sk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk2, err := params.NewPublicKey(sk.Public().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	iss, _ := sk2.Issue(attrs)
+	cred, err := pk2.VerifyIssuance(attrs, iss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPresentations(t, sk, cred)
+
+	if _, err := params.NewSecretKey(sk.Bytes()[1:]); err == nil {
+		t.Error("accepted a short secret key")
+	}
+	if _, err := params.ParsePresentation(make([]byte, 31)); err == nil {
+		t.Error("accepted a short presentation")
+	}
+}
+
+func TestAttributeValidation(t *testing.T) {
+	_, sk, attrs := setup(t)
+	wrongKind := append([]*Attribute(nil), attrs...)
+	wrongKind[2] = NewScalarAttribute(scalar.One())
+	if _, err := sk.Issue(wrongKind); err == nil {
+		t.Error("issued on an attribute of the wrong kind")
+	}
+	if _, err := sk.Issue(attrs[:2]); err == nil {
+		t.Error("issued on too few attributes")
+	}
+	if _, err := NewParams(); err == nil {
+		t.Error("accepted parameters without attributes")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kvac
+
+import (
+	"fmt"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/sigma"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+// Presentation is a zero-knowledge proof of possession of a credential,
+// made of commitments randomized with a fresh z,
+//
+//	C_x0 = z * G_x0 + U
+//	C_x1 = z * G_x1 + t * U
+//	C_V  = z * G_V + V
+//	C_y_i = z * G_y_i + M_i
+//
+// and a proof that Z = z * I, where the verifier computes
+// Z = C_V - W - x0 * C_x0 - x1 * C_x1 - sum(y_i * C_y_i).
+type Presentation struct {
+	Cx0, Cx1, CV *ristretto255.Element
+	Cy           []*ristretto255.Element
+	Proof        *sigma.CompactProof
+}
+
+// presentationStatement declares
+//
+//	Z = z * I
+//	C_x1 = t * C_x0 + z0 * G_x0 + z * G_x1, with z0 = -t * z
+//	C_y_i - M_i = z * G_y_i                  for revealed attributes
+//	C_y_i = z * G_y_i + m_i * G_m_i          for hidden Scalar attributes
+//
+// revealed holds the revealed attributes, and nil for hidden ones. cred and
+// zVal are nil for the verifier.
+func presentationStatement(pk *PublicKey, pres *Presentation, Z *ristretto255.Element, revealed []*Attribute, cred *Credential, zVal *ristretto255.Scalar) *statement {
+	p := pk.params
+	var z0Val, tVal *ristretto255.Scalar
+	if cred != nil {
+		z0Val = ristretto255.NewScalar().Multiply(cred.t, zVal)
+		z0Val.Negate(z0Val)
+		tVal = cred.t
+	}
+
+	s := newStatement("kvac-v1 presentation")
+	z := s.secret("z", zVal)
+	z0 := s.secret("z0", z0Val)
+	t := s.secret("t", tVal)
+
+	s.r.Constrain(s.point("Z", Z), sigma.Term{Scalar: z, Point: s.point("I", pk.I)})
+	s.point("C_V", pres.CV)
+	s.r.Constrain(s.point("C_x1", pres.Cx1),
+		sigma.Term{Scalar: t, Point: s.point("C_x0", pres.Cx0)},
+		sigma.Term{Scalar: z0, Point: s.point("G_x0", p.Gx0)},
+		sigma.Term{Scalar: z, Point: s.point("G_x1", p.Gx1)})
+
+	for i := range p.kinds {
+		Gy := s.point(fmt.Sprintf("G_y%d", i), p.Gy[i])
+		switch {
+		case revealed[i] != nil:
+			P := ristretto255.NewIdentityElement().Subtract(pres.Cy[i], p.element(i, revealed[i]))
+			s.r.Constrain(s.point(fmt.Sprintf("C_y%d-M%d", i, i), P), sigma.Term{Scalar: z, Point: Gy})
+		case p.kinds[i] == ScalarKind:
+			var m *ristretto255.Scalar
+			if cred != nil {
+				m = cred.attrs[i].s
+			}
+			s.r.Constrain(s.point(fmt.Sprintf("C_y%d", i), pres.Cy[i]),
+				sigma.Term{Scalar: z, Point: Gy},
+				sigma.Term{Scalar: s.secret(fmt.Sprintf("m%d", i), m), Point: s.point(fmt.Sprintf("G_m%d", i), p.Gm[i])})
+		default:
+			// Hidden Element attributes are only bound by the MAC.
+			s.point(fmt.Sprintf("C_y%d", i), pres.Cy[i])
+		}
+	}
+	return s
+}
+
+// Present returns a presentation of c that reveals the attributes with
+// reveal[i] set, bound to the transcript t. The verifier must use a
+// transcript in the same state, for example after absorbing a nonce it
+// chose, to prevent replays.
+func (c *Credential) Present(t *transcript.Transcript, reveal []bool) (*Presentation, error) {
+	p := c.pk.params
+	if len(reveal) != len(c.attrs) {
+		return nil, errAttributes
+	}
+	z, err := scalar.Random(nil)
+	if err != nil {
+		return nil, err
+	}
+	commit := func(G, P *ristretto255.Element) *ristretto255.Element {
+		C := ristretto255.NewIdentityElement().ScalarMult(z, G)
+		return C.Add(C, P)
+	}
+	pres := &Presentation{
+		Cx0: commit(p.Gx0, c.U),
+		Cx1: commit(p.Gx1, ristretto255.NewIdentityElement().ScalarMult(c.t, c.U)),
+		CV:  commit(p.GV, c.V),
+	}
+	revealed := make([]*Attribute, len(c.attrs))
+	for i, a := range c.attrs {
+		pres.Cy = append(pres.Cy, commit(p.Gy[i], p.element(i, a)))
+		if reveal[i] {
+			revealed[i] = a
+		}
+	}
+	Z := ristretto255.NewIdentityElement().ScalarMult(z, c.pk.I)
+	if pres.Proof, err = presentationStatement(c.pk, pres, Z, revealed, c, z).prove(t); err != nil {
+		return nil, err
+	}
+	return pres, nil
+}
+
+// VerifyPresentation checks pres against the transcript t, and the revealed
+// attributes, which hold nil for the hidden ones.
+func (sk *SecretKey) VerifyPresentation(t *transcript.Transcript, revealed []*Attribute, pres *Presentation) error {
+	p := sk.params
+	if err := p.checkAttributes(revealed, true); err != nil {
+		return err
+	}
+	if len(pres.Cy) != p.NumAttributes() {
+		return errVerification
+	}
+
+	// Z = C_V - W - x0 * C_x0 - x1 * C_x1 - sum(y_i * C_y_i).
+	scalars := append([]*ristretto255.Scalar{sk.x0, sk.x1}, sk.y...)
+	points := append([]*ristretto255.Element{pres.Cx0, pres.Cx1}, pres.Cy...)
+	Z := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(scalars, points)
+	Z.Add(Z, sk.W)
+	Z.Subtract(pres.CV, Z)
+
+	return presentationStatement(sk.pk, pres, Z, revealed, nil, nil).verify(t, pres.Proof)
+}
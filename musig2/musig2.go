@@ -0,0 +1,231 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package musig2 implements the MuSig2 two-round multi-signature scheme of
+// Nick, Ruffing and Seurin (CRYPTO 2021) over ristretto255, following the
+// structure of BIP 327.
+//
+// The signers' public keys are aggregated into a single public key X. In the
+// first round, which can be run ahead of time, each signer publishes a pair
+// of nonce commitments. In the second round, once the message is known, each
+// signer publishes a partial signature. The sum of the partial signatures is
+// an ordinary schnorrsig.Signature under X, indistinguishable from one
+// produced by a single signer.
+package musig2
+
+import (
+	"errors"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/schnorrsig"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+// PublicNonceSize is the size in bytes of an encoded PublicNonce.
+const PublicNonceSize = 64
+
+var errPartial = errors.New("musig2: invalid partial signature")
+
+// KeyAggContext holds the aggregation of a list of public keys.
+type KeyAggContext struct {
+	keys   []*ristretto255.Element
+	coeffs []*ristretto255.Scalar
+	X      *ristretto255.Element
+}
+
+// AggregateKeys returns the aggregation of keys, X = sum(a_i * X_i), where
+// the coefficients a_i are derived from the whole list to prevent rogue-key
+// attacks. The order of keys matters, and keys must be distinct.
+func AggregateKeys(keys []*ristretto255.Element) (*KeyAggContext, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("musig2: no keys to aggregate")
+	}
+	t := transcript.New("ristretto255-MuSig2-v1-keyagg")
+	t.AppendUint64("n", uint64(len(keys)))
+	seen := make(map[[32]byte]bool)
+	identity := ristretto255.NewIdentityElement()
+	for _, X := range keys {
+		if X.Equal(identity) == 1 {
+			return nil, errors.New("musig2: identity public key")
+		}
+		enc := [32]byte(X.Bytes())
+		if seen[enc] {
+			return nil, errors.New("musig2: duplicate public key")
+		}
+		seen[enc] = true
+		t.AppendElement("X", X)
+	}
+
+	ctx := &KeyAggContext{keys: append([]*ristretto255.Element(nil), keys...)}
+	for _, X := range keys {
+		ti := t.Clone()
+		ti.AppendElement("X_i", X)
+		ctx.coeffs = append(ctx.coeffs, ti.ChallengeScalar("a_i"))
+	}
+	ctx.X = ristretto255.NewIdentityElement().VarTimeMultiScalarMult(ctx.coeffs, ctx.keys)
+	if ctx.X.Equal(identity) == 1 {
+		return nil, errors.New("musig2: aggregate public key is the identity")
+	}
+	return ctx, nil
+}
+
+// index returns the position of X in the key list, or -1.
+func (ctx *KeyAggContext) index(X *ristretto255.Element) int {
+	for i, Xi := range ctx.keys {
+		if Xi.Equal(X) == 1 {
+			return i
+		}
+	}
+	return -1
+}
+
+// SecretNonce is the secret half of a signer's nonce. It must be used for
+// at most one partial signature: signing two different messages with the
+// same SecretNonce reveals the secret key.
+type SecretNonce struct {
+	k1, k2 *ristretto255.Scalar
+}
+
+// PublicNonce is a signer's pair of nonce commitments R1 = k1 * G and
+// R2 = k2 * G, or the aggregation of the nonces of all signers.
+type PublicNonce struct {
+	R1, R2 *ristretto255.Element
+}
+
+// NewNonce returns a fresh nonce pair. The PublicNonce is sent to the other
+// signers, and can be sent before the message is known.
+func NewNonce() (*SecretNonce, *PublicNonce, error) {
+	k1, err := scalar.Random(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	k2, err := scalar.Random(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &SecretNonce{k1: k1, k2: k2}, &PublicNonce{
+		R1: ristretto255.NewIdentityElement().ScalarBaseMult(k1),
+		R2: ristretto255.NewIdentityElement().ScalarBaseMult(k2),
+	}, nil
+}
+
+// AggregateNonces returns the sum of the signers' public nonces.
+func AggregateNonces(nonces []*PublicNonce) *PublicNonce {
+	agg := &PublicNonce{R1: ristretto255.NewIdentityElement(), R2: ristretto255.NewIdentityElement()}
+	for _, n := range nonces {
+		agg.R1.Add(agg.R1, n.R1)
+		agg.R2.Add(agg.R2, n.R2)
+	}
+	return agg
+}
+
+// Bytes returns the 64-byte encoding of the nonce, R1 || R2.
+func (n *PublicNonce) Bytes() []byte {
+	out := make([]byte, 0, PublicNonceSize)
+	out = append(out, n.R1.Bytes()...)
+	return append(out, n.R2.Bytes()...)
+}
+
+// SetBytes sets n to the decoded 64-byte encoding b, and returns n. If b is
+// not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (n *PublicNonce) SetBytes(b []byte) (*PublicNonce, error) {
+	if len(b) != PublicNonceSize {
+		return nil, errors.New("musig2: invalid nonce length")
+	}
+	R1, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[:32])
+	if err != nil {
+		return nil, errors.New("musig2: invalid nonce encoding")
+	}
+	R2, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[32:])
+	if err != nil {
+		return nil, errors.New("musig2: invalid nonce encoding")
+	}
+	n.R1, n.R2 = R1, R2
+	return n, nil
+}
+
+// Session is the second round of a signing session for a message.
+type Session struct {
+	ctx *KeyAggContext
+	msg []byte
+	// b is the nonce coefficient, R = R1 + b * R2 is the final nonce, and c
+	// is the signature challenge.
+	b, c *ristretto255.Scalar
+	R    *ristretto255.Element
+}
+
+// NewSession starts the second round of signing msg, with the aggregation
+// of all the signers' public nonces.
+func NewSession(ctx *KeyAggContext, aggNonce *PublicNonce, msg []byte) *Session {
+	t := transcript.New("ristretto255-MuSig2-v1-noncecoef")
+	t.AppendElement("X", ctx.X)
+	t.AppendElement("R1", aggNonce.R1)
+	t.AppendElement("R2", aggNonce.R2)
+	t.AppendMessage("msg", msg)
+	b := t.ChallengeScalar("b")
+
+	R := ristretto255.NewIdentityElement().ScalarMult(b, aggNonce.R2)
+	R.Add(R, aggNonce.R1)
+	return &Session{
+		ctx: ctx,
+		msg: append([]byte(nil), msg...),
+		b:   b,
+		c:   schnorrsig.Challenge(R, ctx.X, msg),
+		R:   R,
+	}
+}
+
+// Sign returns the partial signature s_i = k1 + b * k2 + c * a_i * x of the
+// signer with secret key x. The nonce is cleared, and can't be used again.
+func (s *Session) Sign(nonce *SecretNonce, x *ristretto255.Scalar) (*ristretto255.Scalar, error) {
+	if nonce.k1 == nil {
+		return nil, errors.New("musig2: nonce already used")
+	}
+	i := s.ctx.index(ristretto255.NewIdentityElement().ScalarBaseMult(x))
+	if i < 0 {
+		return nil, errors.New("musig2: signing key is not in the key list")
+	}
+	k1, k2 := nonce.k1, nonce.k2
+	nonce.k1, nonce.k2 = nil, nil
+
+	si := ristretto255.NewScalar().Multiply(s.c, s.ctx.coeffs[i])
+	si.Multiply(si, x)
+	si.Add(si, k1)
+	return si.Add(si, k2.Multiply(s.b, k2)), nil
+}
+
+// VerifyPartial returns nil if si is a valid partial signature by the signer
+// with public key X and public nonce nonce, that is, if
+// s_i * G = R1 + b * R2 + c * a_i * X, and an error otherwise.
+//
+// Checking partial signatures identifies a misbehaving signer when the
+// aggregate signature is invalid.
+func (s *Session) VerifyPartial(si *ristretto255.Scalar, nonce *PublicNonce, X *ristretto255.Element) error {
+	i := s.ctx.index(X)
+	if i < 0 {
+		return errPartial
+	}
+	ca := ristretto255.NewScalar().Multiply(s.c, s.ctx.coeffs[i])
+	negS := ristretto255.NewScalar().Negate(si)
+	check := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(
+		[]*ristretto255.Scalar{s.b, ca, negS},
+		[]*ristretto255.Element{nonce.R2, X, ristretto255.NewGeneratorElement()})
+	check.Add(check, nonce.R1)
+	if check.Equal(ristretto255.NewIdentityElement()) != 1 {
+		return errPartial
+	}
+	return nil
+}
+
+// Aggregate returns the signature (R, sum(s_i)) for the partial signatures
+// of all signers. It is a valid schnorrsig.Signature by the aggregate public
+// key if all partial signatures are valid.
+func (s *Session) Aggregate(partials []*ristretto255.Scalar) *schnorrsig.Signature {
+	return &schnorrsig.Signature{
+		R: ristretto255.NewIdentityElement().Set(s.R),
+		S: scalar.Sum(partials),
+	}
+}
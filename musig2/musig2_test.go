@@ -0,0 +1,149 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package musig2
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/schnorrsig"
+)
+
+type signer struct {
+	x   *ristretto255.Scalar
+	X   *ristretto255.Element
+	sec *SecretNonce
+	pub *PublicNonce
+}
+
+func newSigners(t *testing.T, n int) ([]*signer, []*ristretto255.Element) {
+	var signers []*signer
+	var keys []*ristretto255.Element
+	for i := 0; i < n; i++ {
+		x := scalar.MustRandom()
+		s := &signer{x: x, X: ristretto255.NewIdentityElement().ScalarBaseMult(x)}
+		var err error
+		if s.sec, s.pub, err = NewNonce(); err != nil {
+			t.Fatal(err)
+		}
+		signers = append(signers, s)
+		keys = append(keys, s.X)
+	}
+	return signers, keys
+}
+
+func TestMuSig2(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7} {
+		signers, keys := newSigners(t, n)
+		ctx, err := AggregateKeys(keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Nonces are exchanged before the message is known.
+		var pubs []*PublicNonce
+		for _, s := range signers {
+			pubs = append(pubs, s.pub)
+		}
+		msg := []byte("co-signed transaction")
+		session := NewSession(ctx, AggregateNonces(pubs), msg)
+
+		var partials []*ristretto255.Scalar
+		for _, s := range signers {
+			si, err := session.Sign(s.sec, s.x)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := session.VerifyPartial(si, s.pub, s.X); err != nil {
+				t.Errorf("n = %d: %v", n, err)
+			}
+			partials = append(partials, si)
+		}
+
+		sig := session.Aggregate(partials)
+		if err := schnorrsig.Verify(ctx.X, msg, sig); err != nil {
+			t.Errorf("n = %d: aggregate signature: %v", n, err)
+		}
+		if err := schnorrsig.Verify(ctx.X, []byte("other"), sig); err == nil {
+			t.Errorf("n = %d: aggregate signature verified for another message", n)
+		}
+	}
+}
+
+func TestInvalidPartial(t *testing.T) {
+	signers, keys := newSigners(t, 2)
+	ctx, _ := AggregateKeys(keys)
+	session := NewSession(ctx, AggregateNonces([]*PublicNonce{signers[0].pub, signers[1].pub}), []byte("msg"))
+
+	s0, _ := session.Sign(signers[0].sec, signers[0].x)
+	s1, _ := session.Sign(signers[1].sec, signers[1].x)
+	bad := ristretto255.NewScalar().Add(s1, scalar.One())
+	if err := session.VerifyPartial(bad, signers[1].pub, signers[1].X); err == nil {
+		t.Error("invalid partial signature verified")
+	}
+	if err := session.VerifyPartial(s0, signers[1].pub, signers[1].X); err == nil {
+		t.Error("partial signature verified for another signer")
+	}
+	sig := session.Aggregate([]*ristretto255.Scalar{s0, bad})
+	if err := schnorrsig.Verify(ctx.X, []byte("msg"), sig); err == nil {
+		t.Error("aggregate of an invalid partial signature verified")
+	}
+}
+
+func TestNonceReuse(t *testing.T) {
+	signers, keys := newSigners(t, 2)
+	ctx, _ := AggregateKeys(keys)
+	agg := AggregateNonces([]*PublicNonce{signers[0].pub, signers[1].pub})
+	if _, err := NewSession(ctx, agg, []byte("one")).Sign(signers[0].sec, signers[0].x); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSession(ctx, agg, []byte("two")).Sign(signers[0].sec, signers[0].x); err == nil {
+		t.Error("nonce was used twice")
+	}
+
+	outsider := scalar.MustRandom()
+	if _, err := NewSession(ctx, agg, []byte("one")).Sign(signers[1].sec, outsider); err == nil {
+		t.Error("signed with a key outside the key list")
+	}
+}
+
+func TestAggregateKeys(t *testing.T) {
+	_, keys := newSigners(t, 3)
+	a, _ := AggregateKeys(keys)
+	b, _ := AggregateKeys([]*ristretto255.Element{keys[1], keys[0], keys[2]})
+	if a.X.Equal(b.X) == 1 {
+		t.Error("aggregate key does not depend on key order")
+	}
+	naive := ristretto255.NewIdentityElement().Add(keys[0], keys[1])
+	naive.Add(naive, keys[2])
+	if a.X.Equal(naive) == 1 {
+		t.Error("aggregate key is the plain sum of keys")
+	}
+
+	if _, err := AggregateKeys(nil); err == nil {
+		t.Error("aggregated an empty key list")
+	}
+	if _, err := AggregateKeys([]*ristretto255.Element{keys[0], keys[1], keys[0]}); err == nil {
+		t.Error("aggregated duplicate keys")
+	}
+	if _, err := AggregateKeys([]*ristretto255.Element{keys[0], ristretto255.NewIdentityElement()}); err == nil {
+		t.Error("aggregated the identity")
+	}
+}
+
+func TestPublicNonceEncoding(t *testing.T) {
+	_, pub, _ := NewNonce()
+	decoded, err := new(PublicNonce).SetBytes(pub.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.R1.Equal(pub.R1) != 1 || decoded.R2.Equal(pub.R2) != 1 {
+		t.Error("nonce did not round-trip")
+	}
+	if _, err := new(PublicNonce).SetBytes(pub.Bytes()[:63]); err == nil {
+		t.Error("accepted a short nonce")
+	}
+}
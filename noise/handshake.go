@@ -0,0 +1,313 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noise
+
+import (
+	"errors"
+	"io"
+	"slices"
+)
+
+// Config is the configuration of one party of a handshake.
+type Config struct {
+	Cipher  CipherFunc
+	Hash    HashFunc
+	Pattern HandshakePattern
+
+	// Initiator is true for the party that sends the first message.
+	Initiator bool
+
+	// Prologue is optional data that both parties must agree on.
+	Prologue []byte
+
+	// StaticKey is the local static key, required if the pattern sends it
+	// or if the peer knows it in advance.
+	StaticKey *PrivateKey
+
+	// PeerStatic is the encoded static public key of the peer, required if
+	// the pattern has the peer's static key as a pre-message.
+	PeerStatic []byte
+
+	// PresharedKey is an optional 32-byte pre-shared key, mixed in at
+	// PresharedKeyPlacement as in Section 9.
+	PresharedKey          []byte
+	PresharedKeyPlacement int
+
+	// Rand is the source of the ephemeral keys. If nil, crypto/rand.Reader
+	// is used.
+	Rand io.Reader
+
+	// ephemeralKey, if set, is used as the local ephemeral key, and dh, if
+	// set, replaces the ristretto255 DH function, for the tests.
+	ephemeralKey *PrivateKey
+	dh           dhFunc
+}
+
+// HandshakeState runs a handshake for one party.
+type HandshakeState struct {
+	ss        *symmetricState
+	dh        dhFunc
+	pattern   HandshakePattern
+	initiator bool
+	rand      io.Reader
+	psk       []byte
+
+	s, e   *PrivateKey
+	rs, re []byte
+
+	msgIndex int
+}
+
+// NewHandshakeState returns a HandshakeState for cfg.
+func NewHandshakeState(cfg Config) (*HandshakeState, error) {
+	if cfg.Cipher == nil || cfg.Hash == nil || len(cfg.Pattern.Messages) == 0 {
+		return nil, errors.New("noise: incomplete configuration")
+	}
+	pattern := cfg.Pattern
+	if cfg.PresharedKey != nil {
+		if len(cfg.PresharedKey) != 32 {
+			return nil, errors.New("noise: invalid pre-shared key length")
+		}
+		if cfg.PresharedKeyPlacement < 0 || cfg.PresharedKeyPlacement > len(pattern.Messages) {
+			return nil, errors.New("noise: invalid pre-shared key placement")
+		}
+		pattern = pattern.withPSK(cfg.PresharedKeyPlacement)
+	}
+
+	hs := &HandshakeState{
+		dh:        cfg.dh,
+		pattern:   pattern,
+		initiator: cfg.Initiator,
+		rand:      cfg.Rand,
+		psk:       append([]byte(nil), cfg.PresharedKey...),
+		s:         cfg.StaticKey,
+		e:         cfg.ephemeralKey,
+	}
+	if hs.dh == nil {
+		hs.dh = dhRistretto255
+	}
+	if cfg.PeerStatic != nil {
+		if err := hs.dh.checkPublicKey(cfg.PeerStatic); err != nil {
+			return nil, err
+		}
+		hs.rs = append([]byte(nil), cfg.PeerStatic...)
+	}
+
+	localPre, peerPre := pattern.InitiatorPreMessages, pattern.ResponderPreMessages
+	if !cfg.Initiator {
+		localPre, peerPre = peerPre, localPre
+	}
+	if hs.s == nil && hs.sendsStatic(localPre) {
+		return nil, errors.New("noise: pattern requires a local static key")
+	}
+	if hs.rs == nil && slices.Contains(peerPre, TokenS) {
+		return nil, errors.New("noise: pattern requires the peer static key")
+	}
+	if slices.Contains(localPre, TokenE) || slices.Contains(peerPre, TokenE) {
+		return nil, errors.New("noise: ephemeral pre-messages are not supported")
+	}
+
+	name := "Noise_" + pattern.Name + "_" + hs.dh.dhName() + "_" + cfg.Cipher.CipherName() + "_" + cfg.Hash.HashName()
+	hs.ss = newSymmetricState(cfg.Cipher, cfg.Hash, name)
+	hs.ss.mixHash(cfg.Prologue)
+	for i, pre := range [][]Token{pattern.InitiatorPreMessages, pattern.ResponderPreMessages} {
+		if !slices.Contains(pre, TokenS) {
+			continue
+		}
+		if (i == 0) == cfg.Initiator {
+			hs.ss.mixHash(hs.s.PublicKey())
+		} else {
+			hs.ss.mixHash(hs.rs)
+		}
+	}
+	return hs, nil
+}
+
+// sendsStatic reports whether the local party needs its static key, because
+// it is in localPre or is sent in one of its messages.
+func (hs *HandshakeState) sendsStatic(localPre []Token) bool {
+	if slices.Contains(localPre, TokenS) {
+		return true
+	}
+	for i, m := range hs.pattern.Messages {
+		if (i%2 == 0) == hs.initiator && slices.Contains(m, TokenS) {
+			return true
+		}
+	}
+	return false
+}
+
+// PeerStatic returns the encoded static public key of the peer, or nil if
+// it is not known yet.
+func (hs *HandshakeState) PeerStatic() []byte {
+	if hs.rs == nil {
+		return nil
+	}
+	return append([]byte(nil), hs.rs...)
+}
+
+// ChannelBinding returns the handshake hash h, which uniquely identifies
+// the session once the handshake is complete.
+func (hs *HandshakeState) ChannelBinding() []byte {
+	return append([]byte(nil), hs.ss.h...)
+}
+
+// mixDH mixes the DH of the local key k and the peer key pub into the
+// chaining key.
+func (hs *HandshakeState) mixDH(k *PrivateKey, pub []byte) error {
+	if k == nil || pub == nil {
+		return errors.New("noise: missing key for DH")
+	}
+	out, err := hs.dh.dh(k, pub)
+	if err != nil {
+		return err
+	}
+	hs.ss.mixKey(out)
+	return nil
+}
+
+// processDH processes a DH token. The first letter of es and se refers to
+// the initiator key, and the second to the responder key.
+func (hs *HandshakeState) processDH(t Token) error {
+	switch {
+	case t == TokenEE:
+		return hs.mixDH(hs.e, hs.re)
+	case t == TokenSS:
+		return hs.mixDH(hs.s, hs.rs)
+	case (t == TokenES) == hs.initiator:
+		return hs.mixDH(hs.e, hs.rs)
+	default:
+		return hs.mixDH(hs.s, hs.re)
+	}
+}
+
+// nextMessage returns the tokens of the next message, or an error if it's
+// not the turn of the local party to send (if sending is true) or receive.
+func (hs *HandshakeState) nextMessage(sending bool) ([]Token, error) {
+	if hs.msgIndex >= len(hs.pattern.Messages) {
+		return nil, errors.New("noise: handshake already complete")
+	}
+	if (hs.msgIndex%2 == 0) != (hs.initiator == sending) {
+		return nil, errors.New("noise: unexpected handshake message direction")
+	}
+	m := hs.pattern.Messages[hs.msgIndex]
+	hs.msgIndex++
+	return m, nil
+}
+
+// split returns the CipherStates if the handshake is complete.
+func (hs *HandshakeState) split() (*CipherState, *CipherState) {
+	if hs.msgIndex < len(hs.pattern.Messages) {
+		return nil, nil
+	}
+	return hs.ss.split()
+}
+
+// WriteMessage appends the next handshake message, carrying payload, to
+// out, and returns the result. After the last message of the pattern, it
+// also returns the CipherStates for the messages from the initiator to the
+// responder, and from the responder to the initiator.
+//
+// If WriteMessage returns an error, the handshake must be aborted.
+func (hs *HandshakeState) WriteMessage(out, payload []byte) ([]byte, *CipherState, *CipherState, error) {
+	tokens, err := hs.nextMessage(true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	start := len(out)
+	for _, t := range tokens {
+		switch t {
+		case TokenE:
+			if hs.e == nil {
+				if hs.e, err = hs.dh.generateKey(hs.rand); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+			out = append(out, hs.e.PublicKey()...)
+			hs.ss.mixHash(hs.e.PublicKey())
+			if hs.psk != nil {
+				hs.ss.mixKey(hs.e.PublicKey())
+			}
+		case TokenS:
+			if out, err = hs.ss.encryptAndHash(out, hs.s.PublicKey()); err != nil {
+				return nil, nil, nil, err
+			}
+		case TokenPSK:
+			hs.ss.mixKeyAndHash(hs.psk)
+		default:
+			if err := hs.processDH(t); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+	if out, err = hs.ss.encryptAndHash(out, payload); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(out)-start > MaxMessageSize {
+		return nil, nil, nil, errors.New("noise: message too large")
+	}
+	c1, c2 := hs.split()
+	return out, c1, c2, nil
+}
+
+// ReadMessage processes the next handshake message, and appends its payload
+// to out. After the last message of the pattern, it also returns the
+// CipherStates as for WriteMessage.
+//
+// If ReadMessage returns an error, the handshake must be aborted.
+func (hs *HandshakeState) ReadMessage(out, message []byte) ([]byte, *CipherState, *CipherState, error) {
+	if len(message) > MaxMessageSize {
+		return nil, nil, nil, errors.New("noise: message too large")
+	}
+	tokens, err := hs.nextMessage(false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, t := range tokens {
+		switch t {
+		case TokenE:
+			if len(message) < DHLen {
+				return nil, nil, nil, errors.New("noise: message too short")
+			}
+			if err := hs.dh.checkPublicKey(message[:DHLen]); err != nil {
+				return nil, nil, nil, err
+			}
+			hs.re = append([]byte(nil), message[:DHLen]...)
+			hs.ss.mixHash(message[:DHLen])
+			if hs.psk != nil {
+				hs.ss.mixKey(message[:DHLen])
+			}
+			message = message[DHLen:]
+		case TokenS:
+			n := DHLen
+			if hs.ss.cs.hasKey {
+				n += TagSize
+			}
+			if len(message) < n {
+				return nil, nil, nil, errors.New("noise: message too short")
+			}
+			rs, err := hs.ss.decryptAndHash(nil, message[:n])
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if err := hs.dh.checkPublicKey(rs); err != nil {
+				return nil, nil, nil, err
+			}
+			hs.rs = rs
+			message = message[n:]
+		case TokenPSK:
+			hs.ss.mixKeyAndHash(hs.psk)
+		default:
+			if err := hs.processDH(t); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+	if out, err = hs.ss.decryptAndHash(out, message); err != nil {
+		return nil, nil, nil, err
+	}
+	c1, c2 := hs.split()
+	return out, c1, c2, nil
+}
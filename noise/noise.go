@@ -0,0 +1,363 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package noise implements the Noise Protocol Framework, revision 34, with a
+// ristretto255 DH function in place of X25519 or X448.
+//
+// The DH function, named "ristretto255" in protocol names, multiplies the
+// peer public Element by the local private Scalar, and returns the 32-byte
+// encoding of the result. Public keys that are not canonical encodings of
+// non-identity Elements are rejected, and so are DH results equal to the
+// identity element. Private keys are canonical Scalar encodings.
+//
+// The cipher and hash functions are pluggable. CipherAESGCM, HashSHA256, and
+// HashSHA512 are provided, and other functions, such as ChaChaPoly and
+// BLAKE2b, can be supplied by implementing CipherFunc and HashFunc.
+package noise
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math"
+
+	"github.com/gtank/ristretto255/ecdh"
+	"github.com/gtank/ristretto255/internal/hkdf"
+)
+
+const (
+	// DHLen is the size in bytes of public keys and DH outputs.
+	DHLen = 32
+	// PrivateKeySize is the size in bytes of an encoded PrivateKey.
+	PrivateKeySize = 32
+	// MaxMessageSize is the maximum size in bytes of a Noise message.
+	MaxMessageSize = 65535
+	// TagSize is the size in bytes of the authentication tag of a
+	// CipherFunc.
+	TagSize = 16
+)
+
+const dhName = "ristretto255"
+
+var (
+	errInvalidPublicKey = errors.New("noise: invalid public key")
+	errDecrypt          = errors.New("noise: decryption failed")
+)
+
+// PrivateKey is a static or ephemeral ristretto255 DH key.
+type PrivateKey struct {
+	priv, pub []byte
+}
+
+// GenerateKey returns a new random PrivateKey. If rand is nil,
+// crypto/rand.Reader is used.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	k, err := ecdh.GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	return newPrivateKey(k), nil
+}
+
+// NewPrivateKey decodes a PrivateKey from its PrivateKeySize-byte encoding.
+func NewPrivateKey(b []byte) (*PrivateKey, error) {
+	k, err := ecdh.NewPrivateKey(b)
+	if err != nil {
+		return nil, errors.New("noise: invalid private key")
+	}
+	return newPrivateKey(k), nil
+}
+
+func newPrivateKey(k *ecdh.PrivateKey) *PrivateKey {
+	return &PrivateKey{priv: k.Bytes(), pub: k.PublicKey().Bytes()}
+}
+
+// Bytes returns the PrivateKeySize-byte encoding of k.
+func (k *PrivateKey) Bytes() []byte {
+	return append([]byte(nil), k.priv...)
+}
+
+// PublicKey returns the DHLen-byte encoding of the public key of k.
+func (k *PrivateKey) PublicKey() []byte {
+	return append([]byte(nil), k.pub...)
+}
+
+// dhFunc is a Noise DH function with DHLen-byte public keys and outputs.
+// The handshake only depends on this interface, so that the tests can also
+// run it with X25519 against published test vectors.
+type dhFunc interface {
+	// dhName returns the name of the function in protocol names.
+	dhName() string
+	generateKey(rand io.Reader) (*PrivateKey, error)
+	// checkPublicKey returns an error if pub is not a valid public key.
+	checkPublicKey(pub []byte) error
+	dh(k *PrivateKey, pub []byte) ([]byte, error)
+}
+
+// dhRistretto255 is the ristretto255 DH function.
+var dhRistretto255 dhFunc = ristretto255DH{}
+
+type ristretto255DH struct{}
+
+func (ristretto255DH) dhName() string { return dhName }
+
+func (ristretto255DH) generateKey(rand io.Reader) (*PrivateKey, error) {
+	return GenerateKey(rand)
+}
+
+func (ristretto255DH) checkPublicKey(pub []byte) error {
+	if _, err := ecdh.NewPublicKey(pub); err != nil {
+		return errInvalidPublicKey
+	}
+	return nil
+}
+
+// dh returns the encoding of k * pub, or an error if pub is invalid or the
+// result is the identity element.
+func (ristretto255DH) dh(k *PrivateKey, pub []byte) ([]byte, error) {
+	peer, err := ecdh.NewPublicKey(pub)
+	if err != nil {
+		return nil, errInvalidPublicKey
+	}
+	priv, err := ecdh.NewPrivateKey(k.priv)
+	if err != nil {
+		return nil, errors.New("noise: invalid private key")
+	}
+	out, err := priv.ECDH(peer)
+	if err != nil {
+		return nil, errors.New("noise: DH result is the identity element")
+	}
+	return out, nil
+}
+
+// CipherFunc is a Noise cipher function, an AEAD with a 32-byte key, a
+// 64-bit nonce, and a TagSize-byte tag.
+type CipherFunc interface {
+	// CipherName returns the name of the function in protocol names.
+	CipherName() string
+	// Cipher returns a Cipher keyed with k.
+	Cipher(k [32]byte) Cipher
+}
+
+// Cipher is an AEAD keyed by a CipherFunc.
+type Cipher interface {
+	// Encrypt appends the encryption of plaintext with nonce n and the
+	// associated data ad to out, and returns the result.
+	Encrypt(out []byte, n uint64, ad, plaintext []byte) []byte
+	// Decrypt appends the decryption of ciphertext with nonce n and the
+	// associated data ad to out, and returns the result, or an error if
+	// authentication fails.
+	Decrypt(out []byte, n uint64, ad, ciphertext []byte) ([]byte, error)
+}
+
+// HashFunc is a Noise hash function.
+type HashFunc interface {
+	// HashName returns the name of the function in protocol names.
+	HashName() string
+	// Hash returns a new instance of the function.
+	Hash() hash.Hash
+}
+
+// CipherAESGCM is the AESGCM cipher function, AES-256 in GCM mode.
+var CipherAESGCM CipherFunc = cipherAESGCM{}
+
+type cipherAESGCM struct{}
+
+func (cipherAESGCM) CipherName() string { return "AESGCM" }
+
+func (cipherAESGCM) Cipher(k [32]byte) Cipher {
+	block, err := aes.NewCipher(k[:])
+	if err != nil {
+		panic("noise: internal error: " + err.Error())
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic("noise: internal error: " + err.Error())
+	}
+	return aesGCM{aead}
+}
+
+type aesGCM struct {
+	aead cipher.AEAD
+}
+
+// nonce returns the AESGCM encoding of n, four zero bytes followed by the
+// big-endian encoding of n.
+func (aesGCM) nonce(n uint64) []byte {
+	var nonce [12]byte
+	binary.BigEndian.PutUint64(nonce[4:], n)
+	return nonce[:]
+}
+
+func (c aesGCM) Encrypt(out []byte, n uint64, ad, plaintext []byte) []byte {
+	return c.aead.Seal(out, c.nonce(n), plaintext, ad)
+}
+
+func (c aesGCM) Decrypt(out []byte, n uint64, ad, ciphertext []byte) ([]byte, error) {
+	return c.aead.Open(out, c.nonce(n), ciphertext, ad)
+}
+
+// HashSHA256 is the SHA256 hash function.
+var HashSHA256 HashFunc = hashFunc{"SHA256", sha256.New}
+
+// HashSHA512 is the SHA512 hash function.
+var HashSHA512 HashFunc = hashFunc{"SHA512", sha512.New}
+
+type hashFunc struct {
+	name string
+	new  func() hash.Hash
+}
+
+func (h hashFunc) HashName() string { return h.name }
+func (h hashFunc) Hash() hash.Hash  { return h.new() }
+
+// CipherState encrypts and decrypts transport messages in one direction
+// after a handshake is complete.
+type CipherState struct {
+	c      Cipher
+	cf     CipherFunc
+	k      [32]byte
+	hasKey bool
+	n      uint64
+}
+
+func (cs *CipherState) initializeKey(k []byte) {
+	copy(cs.k[:], k)
+	cs.c = cs.cf.Cipher(cs.k)
+	cs.hasKey = true
+	cs.n = 0
+}
+
+// Encrypt appends the encryption of plaintext with the associated data ad
+// to out, and returns the result. It returns an error once the nonce space
+// is exhausted.
+func (cs *CipherState) Encrypt(out, ad, plaintext []byte) ([]byte, error) {
+	if !cs.hasKey {
+		return append(out, plaintext...), nil
+	}
+	if cs.n == math.MaxUint64 {
+		return nil, errors.New("noise: nonce exhausted")
+	}
+	out = cs.c.Encrypt(out, cs.n, ad, plaintext)
+	cs.n++
+	return out, nil
+}
+
+// Decrypt appends the decryption of ciphertext with the associated data ad
+// to out, and returns the result. If authentication fails, the nonce is not
+// incremented.
+func (cs *CipherState) Decrypt(out, ad, ciphertext []byte) ([]byte, error) {
+	if !cs.hasKey {
+		return append(out, ciphertext...), nil
+	}
+	if cs.n == math.MaxUint64 {
+		return nil, errors.New("noise: nonce exhausted")
+	}
+	out, err := cs.c.Decrypt(out, cs.n, ad, ciphertext)
+	if err != nil {
+		return nil, errDecrypt
+	}
+	cs.n++
+	return out, nil
+}
+
+// Rekey replaces the key with a new one derived from it, as in Section 11.3
+// of the specification. The nonce is not reset.
+func (cs *CipherState) Rekey() {
+	var zeros [32]byte
+	k := cs.c.Encrypt(nil, math.MaxUint64, nil, zeros[:])
+	copy(cs.k[:], k)
+	cs.c = cs.cf.Cipher(cs.k)
+}
+
+// symmetricState is the SymmetricState object of Section 5.2.
+type symmetricState struct {
+	cs   CipherState
+	hf   HashFunc
+	ck   []byte
+	h    []byte
+	size int
+}
+
+func newSymmetricState(cf CipherFunc, hf HashFunc, protocolName string) *symmetricState {
+	s := &symmetricState{cs: CipherState{cf: cf}, hf: hf, size: hf.Hash().Size()}
+	if len(protocolName) <= s.size {
+		s.h = make([]byte, s.size)
+		copy(s.h, protocolName)
+	} else {
+		s.h = s.hash([]byte(protocolName))
+	}
+	s.ck = append([]byte(nil), s.h...)
+	return s
+}
+
+func (s *symmetricState) hash(data ...[]byte) []byte {
+	h := s.hf.Hash()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// hkdf returns the n outputs of the Noise HKDF function with the chaining
+// key and ikm, which are the HashLen-byte blocks of RFC 5869 HKDF with the
+// chaining key as the salt and an empty info.
+func (s *symmetricState) hkdf(ikm []byte, n int) [][]byte {
+	prk := hkdf.Extract(s.hf.Hash, s.ck, ikm)
+	b := hkdf.Expand(s.hf.Hash, prk, nil, n*s.size)
+	out := make([][]byte, n)
+	for i := range out {
+		out[i] = b[i*s.size : (i+1)*s.size]
+	}
+	return out
+}
+
+func (s *symmetricState) mixKey(ikm []byte) {
+	out := s.hkdf(ikm, 2)
+	s.ck = out[0]
+	s.cs.initializeKey(out[1][:32])
+}
+
+func (s *symmetricState) mixHash(data []byte) {
+	s.h = s.hash(s.h, data)
+}
+
+func (s *symmetricState) mixKeyAndHash(ikm []byte) {
+	out := s.hkdf(ikm, 3)
+	s.ck = out[0]
+	s.mixHash(out[1])
+	s.cs.initializeKey(out[2][:32])
+}
+
+func (s *symmetricState) encryptAndHash(out, plaintext []byte) ([]byte, error) {
+	n := len(out)
+	out, err := s.cs.Encrypt(out, s.h, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	s.mixHash(out[n:])
+	return out, nil
+}
+
+func (s *symmetricState) decryptAndHash(out, ciphertext []byte) ([]byte, error) {
+	out, err := s.cs.Decrypt(out, s.h, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	s.mixHash(ciphertext)
+	return out, nil
+}
+
+func (s *symmetricState) split() (*CipherState, *CipherState) {
+	out := s.hkdf(nil, 2)
+	c1, c2 := &CipherState{cf: s.cs.cf}, &CipherState{cf: s.cs.cf}
+	c1.initializeKey(out[0][:32])
+	c2.initializeKey(out[1][:32])
+	return c1, c2
+}
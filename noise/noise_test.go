@@ -0,0 +1,660 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noise
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdh"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+)
+
+var update = flag.Bool("update", false, "regenerate testdata/vectors.json")
+
+var testPatterns = map[string]HandshakePattern{}
+
+func init() {
+	for _, p := range []HandshakePattern{
+		HandshakeN, HandshakeK, HandshakeX,
+		HandshakeNN, HandshakeNK, HandshakeNX,
+		HandshakeKN, HandshakeKK, HandshakeKX,
+		HandshakeXN, HandshakeXK, HandshakeXX,
+		HandshakeIN, HandshakeIK, HandshakeIX,
+	} {
+		testPatterns[p.Name] = p
+	}
+}
+
+// oneWay reports whether p is a one-way pattern, after which only the
+// initiator sends transport messages.
+func (p HandshakePattern) oneWay() bool {
+	return len(p.Messages) == 1
+}
+
+// dhX25519 is the 25519 DH function, used to check the handshake against
+// the test vectors of other implementations.
+type dhX25519 struct{}
+
+func (dhX25519) dhName() string { return "25519" }
+
+func (dhX25519) generateKey(rand io.Reader) (*PrivateKey, error) {
+	priv := make([]byte, 32)
+	if _, err := io.ReadFull(rand, priv); err != nil {
+		return nil, err
+	}
+	return newX25519Key(priv)
+}
+
+func newX25519Key(priv []byte) (*PrivateKey, error) {
+	k, err := ecdh.X25519().NewPrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{priv: k.Bytes(), pub: k.PublicKey().Bytes()}, nil
+}
+
+func (dhX25519) checkPublicKey(pub []byte) error {
+	_, err := ecdh.X25519().NewPublicKey(pub)
+	return err
+}
+
+func (dhX25519) dh(k *PrivateKey, pub []byte) ([]byte, error) {
+	priv, err := ecdh.X25519().NewPrivateKey(k.priv)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := ecdh.X25519().NewPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return priv.ECDH(peer)
+}
+
+// hexBytes is a byte slice that is hex-encoded in JSON.
+type hexBytes []byte
+
+func (b hexBytes) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(b)), nil
+}
+
+func (b *hexBytes) UnmarshalText(text []byte) error {
+	var err error
+	*b, err = hex.DecodeString(string(text))
+	return err
+}
+
+// vector is a known-answer transcript in the format of the Noise test
+// vectors shared by cacophony, snow, and other implementations.
+type vector struct {
+	ProtocolName     string          `json:"protocol_name"`
+	InitPrologue     hexBytes        `json:"init_prologue"`
+	InitPSKs         []hexBytes      `json:"init_psks,omitempty"`
+	InitStatic       hexBytes        `json:"init_static,omitempty"`
+	InitEphemeral    hexBytes        `json:"init_ephemeral"`
+	InitRemoteStatic hexBytes        `json:"init_remote_static,omitempty"`
+	RespPrologue     hexBytes        `json:"resp_prologue"`
+	RespPSKs         []hexBytes      `json:"resp_psks,omitempty"`
+	RespStatic       hexBytes        `json:"resp_static,omitempty"`
+	RespEphemeral    hexBytes        `json:"resp_ephemeral,omitempty"`
+	RespRemoteStatic hexBytes        `json:"resp_remote_static,omitempty"`
+	HandshakeHash    hexBytes        `json:"handshake_hash"`
+	Messages         []vectorMessage `json:"messages"`
+
+	// alternateTransport is set for the flynn/noise vectors, whose transport
+	// messages alternate directions starting with the initiator, even after
+	// a one-way pattern or an initiator's last handshake message.
+	alternateTransport bool
+}
+
+type vectorMessage struct {
+	Payload    hexBytes `json:"payload"`
+	Ciphertext hexBytes `json:"ciphertext"`
+}
+
+// parseProtocolName returns the pattern, PSK placement (or -1), DH
+// function, cipher, and hash of a protocol name.
+func parseProtocolName(name string) (HandshakePattern, int, dhFunc, CipherFunc, HashFunc, error) {
+	parts := strings.Split(name, "_")
+	dhs := map[string]dhFunc{dhName: dhRistretto255, "25519": dhX25519{}}
+	if len(parts) != 5 || parts[0] != "Noise" || dhs[parts[2]] == nil {
+		return HandshakePattern{}, 0, nil, nil, nil, fmt.Errorf("unsupported protocol %q", name)
+	}
+	patternName, placement := parts[1], -1
+	if i := strings.Index(patternName, "psk"); i >= 0 {
+		p, err := strconv.Atoi(patternName[i+3:])
+		if err != nil {
+			return HandshakePattern{}, 0, nil, nil, nil, fmt.Errorf("unsupported modifier in %q", name)
+		}
+		patternName, placement = patternName[:i], p
+	}
+	pattern, ok := testPatterns[patternName]
+	if !ok {
+		return HandshakePattern{}, 0, nil, nil, nil, fmt.Errorf("unknown pattern in %q", name)
+	}
+	if parts[3] != CipherAESGCM.CipherName() {
+		return HandshakePattern{}, 0, nil, nil, nil, fmt.Errorf("unknown cipher in %q", name)
+	}
+	hashes := map[string]HashFunc{"SHA256": HashSHA256, "SHA512": HashSHA512}
+	hf, ok := hashes[parts[4]]
+	if !ok {
+		return HandshakePattern{}, 0, nil, nil, nil, fmt.Errorf("unknown hash in %q", name)
+	}
+	return pattern, placement, dhs[parts[2]], CipherAESGCM, hf, nil
+}
+
+func optionalKey(t *testing.T, df dhFunc, b []byte) *PrivateKey {
+	t.Helper()
+	if b == nil {
+		return nil
+	}
+	newKey := NewPrivateKey
+	if df != dhRistretto255 {
+		newKey = newX25519Key
+	}
+	k, err := newKey(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// runVector runs the handshake and transport messages of v. If generate is
+// true, it fills in the ciphertexts and the handshake hash, instead of
+// checking them.
+func runVector(t *testing.T, v *vector, generate bool) {
+	pattern, placement, df, cf, hf, err := parseProtocolName(v.ProtocolName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	initCfg := Config{
+		Cipher: cf, Hash: hf, Pattern: pattern, Initiator: true,
+		Prologue:     v.InitPrologue,
+		StaticKey:    optionalKey(t, df, v.InitStatic),
+		PeerStatic:   v.InitRemoteStatic,
+		ephemeralKey: optionalKey(t, df, v.InitEphemeral),
+		dh:           df,
+	}
+	respCfg := Config{
+		Cipher: cf, Hash: hf, Pattern: pattern,
+		Prologue:     v.RespPrologue,
+		StaticKey:    optionalKey(t, df, v.RespStatic),
+		PeerStatic:   v.RespRemoteStatic,
+		ephemeralKey: optionalKey(t, df, v.RespEphemeral),
+		dh:           df,
+	}
+	if placement >= 0 {
+		initCfg.PresharedKey, initCfg.PresharedKeyPlacement = v.InitPSKs[0], placement
+		respCfg.PresharedKey, respCfg.PresharedKeyPlacement = v.RespPSKs[0], placement
+	}
+	initiator, err := NewHandshakeState(initCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	responder, err := NewHandshakeState(respCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var initSend, initRecv, respSend, respRecv *CipherState
+	handshakeLen := len(pattern.Messages)
+	for i := range v.Messages {
+		m := &v.Messages[i]
+		fromInitiator := i%2 == 0 || pattern.oneWay()
+		if v.alternateTransport && i >= handshakeLen {
+			fromInitiator = (i-handshakeLen)%2 == 0
+		}
+		var ct, pt []byte
+		if i < handshakeLen {
+			writer, reader := initiator, responder
+			if !fromInitiator {
+				writer, reader = responder, initiator
+			}
+			var c1, c2 *CipherState
+			if ct, c1, c2, err = writer.WriteMessage(nil, m.Payload); err != nil {
+				t.Fatalf("message %d: %v", i, err)
+			}
+			var d1, d2 *CipherState
+			if pt, d1, d2, err = reader.ReadMessage(nil, ct); err != nil {
+				t.Fatalf("message %d: %v", i, err)
+			}
+			if i == handshakeLen-1 {
+				if c1 == nil || d1 == nil {
+					t.Fatal("handshake did not complete")
+				}
+				if fromInitiator {
+					initSend, initRecv, respRecv, respSend = c1, c2, d1, d2
+				} else {
+					respRecv, respSend, initSend, initRecv = c1, c2, d1, d2
+				}
+			}
+		} else {
+			writer, reader := initSend, respRecv
+			if !fromInitiator {
+				writer, reader = respSend, initRecv
+			}
+			if ct, err = writer.Encrypt(nil, nil, m.Payload); err != nil {
+				t.Fatal(err)
+			}
+			if pt, err = reader.Decrypt(nil, nil, ct); err != nil {
+				t.Fatalf("message %d: %v", i, err)
+			}
+		}
+		if !bytes.Equal(pt, m.Payload) {
+			t.Errorf("message %d: got payload %x, want %x", i, pt, m.Payload)
+		}
+		if generate {
+			m.Ciphertext = ct
+		} else if !bytes.Equal(ct, m.Ciphertext) {
+			t.Errorf("message %d: got ciphertext %x, want %x", i, ct, m.Ciphertext)
+		}
+	}
+
+	if !bytes.Equal(initiator.ChannelBinding(), responder.ChannelBinding()) {
+		t.Error("handshake hashes do not match")
+	}
+	if generate {
+		v.HandshakeHash = initiator.ChannelBinding()
+	} else if v.HandshakeHash != nil && !bytes.Equal(initiator.ChannelBinding(), v.HandshakeHash) {
+		t.Errorf("got handshake hash %x, want %x", initiator.ChannelBinding(), v.HandshakeHash)
+	}
+}
+
+func TestVectors(t *testing.T) {
+	if *update {
+		generateVectors(t)
+	}
+	data, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file struct{ Vectors []vector }
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Vectors) == 0 {
+		t.Fatal("no test vectors")
+	}
+	for i := range file.Vectors {
+		v := &file.Vectors[i]
+		t.Run(v.ProtocolName, func(t *testing.T) { runVector(t, v, false) })
+	}
+}
+
+// readFlynnVectors parses the test vectors of github.com/flynn/noise, which
+// list the private keys, the PSK, and the messages of each run, but not the
+// handshake hash.
+func readFlynnVectors(t *testing.T, r io.Reader) []vector {
+	var vectors []vector
+	var v *vector
+	var initStatic, respStatic []byte
+	// finish fills in the keys that the pattern of v needs.
+	finish := func() {
+		if v == nil {
+			return
+		}
+		pattern, _, _, _, _, err := parseProtocolName(v.ProtocolName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if needsStatic(pattern, true) {
+			v.InitStatic = initStatic
+		}
+		if needsStatic(pattern, false) {
+			v.RespStatic = respStatic
+		}
+		if slices.Contains(pattern.ResponderPreMessages, TokenS) {
+			v.InitRemoteStatic = optionalKey(t, dhX25519{}, respStatic).PublicKey()
+		}
+		if slices.Contains(pattern.InitiatorPreMessages, TokenS) {
+			v.RespRemoteStatic = optionalKey(t, dhX25519{}, initStatic).PublicKey()
+		}
+		vectors = append(vectors, *v)
+		v, initStatic, respStatic = nil, nil, nil
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		b, err := hex.DecodeString(value)
+		if !ok || (key != "handshake" && err != nil) {
+			t.Fatalf("malformed line %q", line)
+		}
+		if key == "handshake" {
+			finish()
+			v = &vector{ProtocolName: value, alternateTransport: true}
+			continue
+		}
+		if v == nil {
+			t.Fatalf("line %q outside of a vector", line)
+		}
+		switch {
+		case key == "init_static":
+			initStatic = b
+		case key == "resp_static":
+			respStatic = b
+		case key == "gen_init_ephemeral":
+			v.InitEphemeral = b
+		case key == "gen_resp_ephemeral":
+			v.RespEphemeral = b
+		case key == "prologue":
+			v.InitPrologue, v.RespPrologue = b, b
+		case key == "preshared_key":
+			v.InitPSKs, v.RespPSKs = []hexBytes{b}, []hexBytes{b}
+		case strings.HasSuffix(key, "_payload"):
+			v.Messages = append(v.Messages, vectorMessage{Payload: b})
+		case strings.HasSuffix(key, "_ciphertext"):
+			v.Messages[len(v.Messages)-1].Ciphertext = b
+		default:
+			t.Fatalf("unknown key %q", key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	finish()
+	return vectors
+}
+
+// TestFlynnVectors runs the handshake code with X25519 in place of
+// ristretto255 against the vectors of github.com/flynn/noise, to check it
+// against another implementation of the framework.
+func TestFlynnVectors(t *testing.T) {
+	f, err := os.Open("testdata/flynn-vectors.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	vectors := readFlynnVectors(t, f)
+	if len(vectors) == 0 {
+		t.Fatal("no test vectors")
+	}
+	for i := range vectors {
+		v := &vectors[i]
+		t.Run(v.ProtocolName, func(t *testing.T) { runVector(t, v, false) })
+	}
+}
+
+// testKey returns a deterministic private key for the vectors.
+func testKey(label string) hexBytes {
+	h := sha512.Sum512([]byte(label))
+	k, err := ristretto255.NewScalar().SetUniformBytes(h[:])
+	if err != nil {
+		panic(err)
+	}
+	return k.Bytes()
+}
+
+// needsStatic reports whether the initiator or the responder of pattern
+// needs a static key.
+func needsStatic(pattern HandshakePattern, initiator bool) bool {
+	pre := pattern.InitiatorPreMessages
+	if !initiator {
+		pre = pattern.ResponderPreMessages
+	}
+	if slices.Contains(pre, TokenS) {
+		return true
+	}
+	for i, m := range pattern.Messages {
+		if (i%2 == 0) == initiator && slices.Contains(m, TokenS) {
+			return true
+		}
+	}
+	return false
+}
+
+// generateVectors writes testdata/vectors.json with transcripts of this
+// implementation, to detect regressions and to check other implementations
+// of the same DH function.
+func generateVectors(t *testing.T) {
+	var names []string
+	for _, p := range []string{"N", "K", "X", "NN", "NK", "NX", "KN", "KK", "KX", "XN", "XK", "XX", "IN", "IK", "IX"} {
+		names = append(names, "Noise_"+p+"_ristretto255_AESGCM_SHA256")
+	}
+	for _, p := range []string{"NN", "IK", "XX"} {
+		names = append(names, "Noise_"+p+"_ristretto255_AESGCM_SHA512")
+	}
+	for _, p := range []string{"Npsk0", "NNpsk0", "NNpsk2", "NKpsk0", "IKpsk2", "XXpsk3"} {
+		names = append(names, "Noise_"+p+"_ristretto255_AESGCM_SHA256")
+	}
+
+	payloads := []string{
+		"4c756477696720766f6e204d69736573",
+		"4d757272617920526f746862617264",
+		"462e20412e20486179656b",
+		"4361726c204d656e676572",
+		"4a65616e2d426170746973746520536179",
+		"457567656e2042f6686d20766f6e2042617765726b",
+	}
+
+	var file struct {
+		Vectors []vector `json:"vectors"`
+	}
+	for _, name := range names {
+		pattern, placement, _, _, _, err := parseProtocolName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v := vector{
+			ProtocolName:  name,
+			InitPrologue:  []byte("John Galt"),
+			RespPrologue:  []byte("John Galt"),
+			InitEphemeral: testKey("initiator ephemeral"),
+			RespEphemeral: testKey("responder ephemeral"),
+		}
+		initStatic, respStatic := testKey("initiator static"), testKey("responder static")
+		initPub, respPub := optionalKey(t, dhRistretto255, initStatic).PublicKey(), optionalKey(t, dhRistretto255, respStatic).PublicKey()
+		if needsStatic(pattern, true) {
+			v.InitStatic = initStatic
+		}
+		if needsStatic(pattern, false) {
+			v.RespStatic = respStatic
+		}
+		if slices.Contains(pattern.ResponderPreMessages, TokenS) {
+			v.InitRemoteStatic = respPub
+		}
+		if slices.Contains(pattern.InitiatorPreMessages, TokenS) {
+			v.RespRemoteStatic = initPub
+		}
+		if pattern.oneWay() {
+			v.RespEphemeral = nil
+		}
+		if placement >= 0 {
+			psk := hexBytes(bytes.Repeat([]byte{0x54}, 32))
+			v.InitPSKs, v.RespPSKs = []hexBytes{psk}, []hexBytes{psk}
+		}
+		for _, p := range payloads {
+			b, _ := hex.DecodeString(p)
+			v.Messages = append(v.Messages, vectorMessage{Payload: b})
+		}
+		runVector(t, &v, true)
+		file.Vectors = append(file.Vectors, v)
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("testdata/vectors.json", append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newPair(t *testing.T, pattern HandshakePattern, initCfg, respCfg Config) (*HandshakeState, *HandshakeState) {
+	t.Helper()
+	initCfg.Cipher, initCfg.Hash, initCfg.Pattern, initCfg.Initiator = CipherAESGCM, HashSHA256, pattern, true
+	respCfg.Cipher, respCfg.Hash, respCfg.Pattern = CipherAESGCM, HashSHA256, pattern
+	initiator, err := NewHandshakeState(initCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	responder, err := NewHandshakeState(respCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return initiator, responder
+}
+
+func mustGenerateKey(t *testing.T) *PrivateKey {
+	t.Helper()
+	k, err := GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestXX(t *testing.T) {
+	initStatic, respStatic := mustGenerateKey(t), mustGenerateKey(t)
+	initiator, responder := newPair(t, HandshakeXX,
+		Config{StaticKey: initStatic}, Config{StaticKey: respStatic})
+
+	msg, _, _, err := initiator.WriteMessage(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := responder.ReadMessage(nil, msg); err != nil {
+		t.Fatal(err)
+	}
+	msg, _, _, err = responder.WriteMessage(nil, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := initiator.ReadMessage(nil, msg); err != nil {
+		t.Fatal(err)
+	}
+	msg, initSend, initRecv, err := initiator.WriteMessage(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, respRecv, respSend, err := responder.ReadMessage(nil, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(initiator.PeerStatic(), respStatic.PublicKey()) ||
+		!bytes.Equal(responder.PeerStatic(), initStatic.PublicKey()) {
+		t.Error("static keys were not exchanged")
+	}
+
+	if _, _, _, err := initiator.WriteMessage(nil, nil); err == nil {
+		t.Error("wrote a message after the handshake")
+	}
+
+	initSend.Rekey()
+	respRecv.Rekey()
+	ct, err := initSend.Encrypt(nil, []byte("ad"), []byte("ping"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pt, err := respRecv.Decrypt(nil, []byte("ad"), ct); err != nil || string(pt) != "ping" {
+		t.Errorf("transport message failed after rekey: %q, %v", pt, err)
+	}
+	ct, err = respSend.Encrypt(nil, nil, []byte("pong"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct[0] ^= 1
+	if _, err := initRecv.Decrypt(nil, nil, ct); err == nil {
+		t.Error("decrypted a tampered transport message")
+	}
+	ct[0] ^= 1
+	if pt, err := initRecv.Decrypt(nil, nil, ct); err != nil || string(pt) != "pong" {
+		t.Errorf("transport message failed after a tampered one: %q, %v", pt, err)
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	respStatic := mustGenerateKey(t)
+	psk := bytes.Repeat([]byte{1}, 32)
+	for _, tc := range []struct {
+		name             string
+		initCfg, respCfg Config
+	}{
+		{"prologue",
+			Config{PeerStatic: respStatic.PublicKey(), Prologue: []byte("a")},
+			Config{StaticKey: respStatic, Prologue: []byte("b")}},
+		{"peer static",
+			Config{PeerStatic: mustGenerateKey(t).PublicKey()},
+			Config{StaticKey: respStatic}},
+		{"psk",
+			Config{PeerStatic: respStatic.PublicKey(), PresharedKey: psk},
+			Config{StaticKey: respStatic, PresharedKey: bytes.Repeat([]byte{2}, 32)}},
+	} {
+		initiator, responder := newPair(t, HandshakeNK, tc.initCfg, tc.respCfg)
+		msg, _, _, err := initiator.WriteMessage(nil, []byte("payload"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, _, err := responder.ReadMessage(nil, msg); err == nil {
+			t.Errorf("%s: mismatched handshake succeeded", tc.name)
+		}
+	}
+}
+
+func TestInvalidPublicKeys(t *testing.T) {
+	initiator, responder := newPair(t, HandshakeNN, Config{}, Config{})
+	msg, _, _, err := initiator.WriteMessage(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity := ristretto255.NewIdentityElement().Bytes()
+	if _, _, _, err := responder.ReadMessage(nil, append(identity, msg[DHLen:]...)); err == nil {
+		t.Error("accepted the identity element as an ephemeral key")
+	}
+	nonCanonical := bytes.Repeat([]byte{0xff}, DHLen)
+	_, responder = newPair(t, HandshakeNN, Config{}, Config{})
+	if _, _, _, err := responder.ReadMessage(nil, nonCanonical); err == nil {
+		t.Error("accepted a non-canonical ephemeral key")
+	}
+	if _, err := NewHandshakeState(Config{
+		Cipher: CipherAESGCM, Hash: HashSHA256, Pattern: HandshakeNK,
+		Initiator: true, PeerStatic: identity,
+	}); err == nil {
+		t.Error("accepted the identity element as a static key")
+	}
+}
+
+func TestConfig(t *testing.T) {
+	base := func(p HandshakePattern, initiator bool) Config {
+		return Config{Cipher: CipherAESGCM, Hash: HashSHA256, Pattern: p, Initiator: initiator}
+	}
+	noCipher := base(HandshakeNN, true)
+	noCipher.Cipher = nil
+	shortPSK := base(HandshakeNN, true)
+	shortPSK.PresharedKey = []byte("short")
+	badPlacement := base(HandshakeNN, true)
+	badPlacement.PresharedKey, badPlacement.PresharedKeyPlacement = make([]byte, 32), 3
+	for _, cfg := range []Config{
+		noCipher,
+		base(HandshakeXX, true), // missing local static key
+		base(HandshakeNK, true), // missing peer static key
+		base(HandshakeNK, false),
+		shortPSK,
+		badPlacement,
+	} {
+		if _, err := NewHandshakeState(cfg); err == nil {
+			t.Errorf("accepted invalid configuration %+v", cfg)
+		}
+	}
+
+	initiator, _ := newPair(t, HandshakeNN, Config{}, Config{})
+	if _, _, _, err := initiator.ReadMessage(nil, make([]byte, DHLen)); err == nil {
+		t.Error("initiator read the first message")
+	}
+}
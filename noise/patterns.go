@@ -0,0 +1,163 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package noise
+
+import "strconv"
+
+// Token is a handshake pattern token.
+type Token int
+
+const (
+	TokenE Token = iota
+	TokenS
+	TokenEE
+	TokenES
+	TokenSE
+	TokenSS
+	TokenPSK
+)
+
+// HandshakePattern is a Noise handshake pattern. Messages alternate between
+// the initiator and the responder, starting with the initiator.
+type HandshakePattern struct {
+	Name                 string
+	InitiatorPreMessages []Token
+	ResponderPreMessages []Token
+	Messages             [][]Token
+}
+
+// The one-way handshake patterns of Section 7.4.
+var (
+	HandshakeN = HandshakePattern{
+		Name:                 "N",
+		ResponderPreMessages: []Token{TokenS},
+		Messages:             [][]Token{{TokenE, TokenES}},
+	}
+	HandshakeK = HandshakePattern{
+		Name:                 "K",
+		InitiatorPreMessages: []Token{TokenS},
+		ResponderPreMessages: []Token{TokenS},
+		Messages:             [][]Token{{TokenE, TokenES, TokenSS}},
+	}
+	HandshakeX = HandshakePattern{
+		Name:                 "X",
+		ResponderPreMessages: []Token{TokenS},
+		Messages:             [][]Token{{TokenE, TokenES, TokenS, TokenSS}},
+	}
+)
+
+// The interactive handshake patterns of Section 7.5.
+var (
+	HandshakeNN = HandshakePattern{
+		Name: "NN",
+		Messages: [][]Token{
+			{TokenE},
+			{TokenE, TokenEE},
+		},
+	}
+	HandshakeNK = HandshakePattern{
+		Name:                 "NK",
+		ResponderPreMessages: []Token{TokenS},
+		Messages: [][]Token{
+			{TokenE, TokenES},
+			{TokenE, TokenEE},
+		},
+	}
+	HandshakeNX = HandshakePattern{
+		Name: "NX",
+		Messages: [][]Token{
+			{TokenE},
+			{TokenE, TokenEE, TokenS, TokenES},
+		},
+	}
+	HandshakeKN = HandshakePattern{
+		Name:                 "KN",
+		InitiatorPreMessages: []Token{TokenS},
+		Messages: [][]Token{
+			{TokenE},
+			{TokenE, TokenEE, TokenSE},
+		},
+	}
+	HandshakeKK = HandshakePattern{
+		Name:                 "KK",
+		InitiatorPreMessages: []Token{TokenS},
+		ResponderPreMessages: []Token{TokenS},
+		Messages: [][]Token{
+			{TokenE, TokenES, TokenSS},
+			{TokenE, TokenEE, TokenSE},
+		},
+	}
+	HandshakeKX = HandshakePattern{
+		Name:                 "KX",
+		InitiatorPreMessages: []Token{TokenS},
+		Messages: [][]Token{
+			{TokenE},
+			{TokenE, TokenEE, TokenSE, TokenS, TokenES},
+		},
+	}
+	HandshakeXN = HandshakePattern{
+		Name: "XN",
+		Messages: [][]Token{
+			{TokenE},
+			{TokenE, TokenEE},
+			{TokenS, TokenSE},
+		},
+	}
+	HandshakeXK = HandshakePattern{
+		Name:                 "XK",
+		ResponderPreMessages: []Token{TokenS},
+		Messages: [][]Token{
+			{TokenE, TokenES},
+			{TokenE, TokenEE},
+			{TokenS, TokenSE},
+		},
+	}
+	HandshakeXX = HandshakePattern{
+		Name: "XX",
+		Messages: [][]Token{
+			{TokenE},
+			{TokenE, TokenEE, TokenS, TokenES},
+			{TokenS, TokenSE},
+		},
+	}
+	HandshakeIN = HandshakePattern{
+		Name: "IN",
+		Messages: [][]Token{
+			{TokenE, TokenS},
+			{TokenE, TokenEE, TokenSE},
+		},
+	}
+	HandshakeIK = HandshakePattern{
+		Name:                 "IK",
+		ResponderPreMessages: []Token{TokenS},
+		Messages: [][]Token{
+			{TokenE, TokenES, TokenS, TokenSS},
+			{TokenE, TokenEE, TokenSE},
+		},
+	}
+	HandshakeIX = HandshakePattern{
+		Name: "IX",
+		Messages: [][]Token{
+			{TokenE, TokenS},
+			{TokenE, TokenEE, TokenSE, TokenS, TokenES},
+		},
+	}
+)
+
+// withPSK returns p with a psk token at placement, as in Section 9.
+func (p HandshakePattern) withPSK(placement int) HandshakePattern {
+	msgs := make([][]Token, len(p.Messages))
+	for i, m := range p.Messages {
+		msgs[i] = append([]Token(nil), m...)
+	}
+	if placement == 0 {
+		msgs[0] = append([]Token{TokenPSK}, msgs[0]...)
+	} else {
+		msgs[placement-1] = append(msgs[placement-1], TokenPSK)
+	}
+	p.Messages = msgs
+	p.Name += "psk" + strconv.Itoa(placement)
+	return p
+}
@@ -0,0 +1,368 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package oneofmany implements the one-out-of-many proofs of Groth and
+// Kohlweiss (EUROCRYPT 2015) over ristretto255 Pedersen commitments.
+//
+// A Proof shows that the prover knows the index l and the opening r of a
+// commitment C[l] = 0 * B + r * BBlinding in a public set C, without
+// revealing l. Proofs for a set of N commitments have 4*lg(N) Elements and
+// 3*lg(N) + 1 Scalars.
+//
+// To prove that a commitment V opens to the same value as one of the set,
+// or that a public key is in a set, run the proof over the set C[i] - V.
+package oneofmany
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/bulletproofs"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+var (
+	errVerification = errors.New("oneofmany: proof verification failed")
+	errFormat       = errors.New("oneofmany: malformed proof encoding")
+)
+
+// Proof is a one-out-of-many proof for a set of up to 2^m commitments.
+type Proof struct {
+	// Commitments to the bits of the index, to the blinding values a, to
+	// the products of the bits and a, and to the low order coefficients.
+	CL, CA, CB, CD []*ristretto255.Element
+	// Responses f_j = l_j * x + a_j and their blinding factors.
+	F, ZA, ZB []*ristretto255.Scalar
+	ZD        *ristretto255.Scalar
+}
+
+// complete reports whether all the fields of p are set, with m entries in
+// each slice, as they are for any Proof over a set of 2^m commitments
+// returned by Prove or SetBytes.
+func (p *Proof) complete(m int) bool {
+	if p == nil || p.ZD == nil {
+		return false
+	}
+	for _, es := range [][]*ristretto255.Element{p.CL, p.CA, p.CB, p.CD} {
+		if len(es) != m || slices.Contains(es, nil) {
+			return false
+		}
+	}
+	for _, ss := range [][]*ristretto255.Scalar{p.F, p.ZA, p.ZB} {
+		if len(ss) != m || slices.Contains(ss, nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// padSet returns lg(N) and the set padded to a power of two by repeating its
+// last element, which doesn't change the statement.
+func padSet(set []*ristretto255.Element) (int, []*ristretto255.Element, error) {
+	if len(set) < 2 {
+		return 0, nil, errors.New("oneofmany: set must have at least two elements")
+	}
+	m := 0
+	for 1<<m < len(set) {
+		m++
+	}
+	if m > 32 {
+		return 0, nil, errors.New("oneofmany: set is too large")
+	}
+	padded := append([]*ristretto255.Element(nil), set...)
+	for len(padded) < 1<<m {
+		padded = append(padded, set[len(set)-1])
+	}
+	return m, padded, nil
+}
+
+func absorbStatement(t *transcript.Transcript, pc *bulletproofs.PedersenGens, set []*ristretto255.Element) {
+	t.AppendMessage("dom-sep", []byte("oneofmany v1"))
+	t.AppendElement("B", pc.B)
+	t.AppendElement("B_blinding", pc.BBlinding)
+	buf := make([]byte, 0, 32*len(set))
+	for _, C := range set {
+		buf = append(buf, C.Bytes()...)
+	}
+	t.AppendMessage("set", buf)
+}
+
+// Prove returns a proof that set[index] = r * BBlinding.
+//
+// Proving time is dominated by lg(N) constant time multi-scalar
+// multiplications of size N.
+func Prove(t *transcript.Transcript, pc *bulletproofs.PedersenGens, set []*ristretto255.Element, index int, r *ristretto255.Scalar) (*Proof, error) {
+	if index < 0 || index >= len(set) {
+		return nil, errors.New("oneofmany: index out of range")
+	}
+	m, set, err := padSet(set)
+	if err != nil {
+		return nil, err
+	}
+	N := len(set)
+	absorbStatement(t, pc, set)
+
+	rnd := func(n int) ([]*ristretto255.Scalar, error) {
+		out := make([]*ristretto255.Scalar, n)
+		for i := range out {
+			if out[i], err = scalar.Random(nil); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	rj, err := rnd(m)
+	if err != nil {
+		return nil, err
+	}
+	a, err := rnd(m)
+	if err != nil {
+		return nil, err
+	}
+	s, err := rnd(m)
+	if err != nil {
+		return nil, err
+	}
+	tj, err := rnd(m)
+	if err != nil {
+		return nil, err
+	}
+	rho, err := rnd(m)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &Proof{}
+	bits := make([]*ristretto255.Scalar, m)
+	for j := 0; j < m; j++ {
+		bits[j] = scalar.FromUint64(uint64(index>>j) & 1)
+		proof.CL = append(proof.CL, pc.Commit(bits[j], rj[j]))
+		proof.CA = append(proof.CA, pc.Commit(a[j], s[j]))
+		la := ristretto255.NewScalar().Multiply(bits[j], a[j])
+		proof.CB = append(proof.CB, pc.Commit(la, tj[j]))
+	}
+
+	// p_i(x) = prod_j f_{j,i_j}(x), where f_{j,1}(x) = l_j x + a_j and
+	// f_{j,0}(x) = x - f_{j,1}(x). Each polynomial has m+1 coefficients.
+	polys := [][]*ristretto255.Scalar{{scalar.One()}}
+	for j := 0; j < m; j++ {
+		f1 := []*ristretto255.Scalar{a[j], bits[j]}
+		f0 := []*ristretto255.Scalar{
+			ristretto255.NewScalar().Negate(a[j]),
+			ristretto255.NewScalar().Subtract(scalar.One(), bits[j]),
+		}
+		next := make([][]*ristretto255.Scalar, 2*len(polys))
+		for i, p := range polys {
+			next[i] = mulLinear(p, f0)
+			next[i+len(polys)] = mulLinear(p, f1)
+		}
+		polys = next
+	}
+
+	coeffs := make([]*ristretto255.Scalar, N+1)
+	points := make([]*ristretto255.Element, N+1)
+	copy(points, set)
+	points[N] = pc.BBlinding
+	for k := 0; k < m; k++ {
+		for i := 0; i < N; i++ {
+			coeffs[i] = polys[i][k]
+		}
+		coeffs[N] = rho[k]
+		proof.CD = append(proof.CD, ristretto255.NewIdentityElement().MultiScalarMult(coeffs, points))
+	}
+
+	for j := 0; j < m; j++ {
+		t.AppendElement("C_l", proof.CL[j])
+		t.AppendElement("C_a", proof.CA[j])
+		t.AppendElement("C_b", proof.CB[j])
+		t.AppendElement("C_d", proof.CD[j])
+	}
+	x := t.ChallengeScalar("x")
+
+	tmp := ristretto255.NewScalar()
+	for j := 0; j < m; j++ {
+		f := ristretto255.NewScalar().Multiply(bits[j], x)
+		f.Add(f, a[j])
+		proof.F = append(proof.F, f)
+
+		za := ristretto255.NewScalar().Multiply(rj[j], x)
+		proof.ZA = append(proof.ZA, za.Add(za, s[j]))
+
+		zb := ristretto255.NewScalar().Subtract(x, f)
+		zb.Multiply(zb, rj[j])
+		proof.ZB = append(proof.ZB, zb.Add(zb, tj[j]))
+	}
+
+	// z_d = r * x^m - sum(rho_k * x^k)
+	xk := scalar.One()
+	zd := ristretto255.NewScalar()
+	for k := 0; k < m; k++ {
+		zd.Subtract(zd, tmp.Multiply(rho[k], xk))
+		xk.Multiply(xk, x)
+	}
+	proof.ZD = zd.Add(zd, tmp.Multiply(r, xk))
+
+	return proof, nil
+}
+
+// mulLinear returns p(x) * (c[0] + c[1] x).
+func mulLinear(p, c []*ristretto255.Scalar) []*ristretto255.Scalar {
+	out := make([]*ristretto255.Scalar, len(p)+1)
+	for i := range out {
+		out[i] = ristretto255.NewScalar()
+	}
+	tmp := ristretto255.NewScalar()
+	for i := range p {
+		out[i].Add(out[i], tmp.Multiply(p[i], c[0]))
+		out[i+1].Add(out[i+1], tmp.Multiply(p[i], c[1]))
+	}
+	return out
+}
+
+// Verify checks that proof shows knowledge of the opening to zero of one of
+// the commitments in set.
+func Verify(t *transcript.Transcript, pc *bulletproofs.PedersenGens, set []*ristretto255.Element, proof *Proof) error {
+	return VerifyBatch([]*transcript.Transcript{t}, pc, set, []*Proof{proof})
+}
+
+// VerifyBatch checks several proofs against the same set, each with its own
+// transcript. The work proportional to the size of the set is shared by
+// all the proofs, and all the checks are combined with random weights into
+// a single VarTimeMultiScalarMult.
+func VerifyBatch(ts []*transcript.Transcript, pc *bulletproofs.PedersenGens, set []*ristretto255.Element, proofs []*Proof) error {
+	if len(ts) != len(proofs) {
+		return errVerification
+	}
+	m, set, err := padSet(set)
+	if err != nil {
+		return err
+	}
+	N := len(set)
+
+	setScalars := make([]*ristretto255.Scalar, N)
+	for i := range setScalars {
+		setScalars[i] = ristretto255.NewScalar()
+	}
+	gScalar, hScalar := ristretto255.NewScalar(), ristretto255.NewScalar()
+	var scalars []*ristretto255.Scalar
+	var points []*ristretto255.Element
+	tmp := ristretto255.NewScalar()
+
+	for k, proof := range proofs {
+		if !proof.complete(m) {
+			return errVerification
+		}
+		t := ts[k]
+		absorbStatement(t, pc, set)
+		for j := 0; j < m; j++ {
+			t.AppendElement("C_l", proof.CL[j])
+			t.AppendElement("C_a", proof.CA[j])
+			t.AppendElement("C_b", proof.CB[j])
+			t.AppendElement("C_d", proof.CD[j])
+		}
+		x := t.ChallengeScalar("x")
+
+		for j := 0; j < m; j++ {
+			w1, w2 := scalar.MustRandom(), scalar.MustRandom()
+			xMinusF := ristretto255.NewScalar().Subtract(x, proof.F[j])
+
+			// w1 * (x C_l + C_a - f B - z_a H)
+			// + w2 * ((x - f) C_l + C_b - z_b H) = 0
+			cl := ristretto255.NewScalar().Multiply(w1, x)
+			cl.Add(cl, tmp.Multiply(w2, xMinusF))
+			scalars = append(scalars, cl, w1, w2)
+			points = append(points, proof.CL[j], proof.CA[j], proof.CB[j])
+
+			gScalar.Subtract(gScalar, tmp.Multiply(w1, proof.F[j]))
+			hScalar.Subtract(hScalar, tmp.Multiply(w1, proof.ZA[j]))
+			hScalar.Subtract(hScalar, tmp.Multiply(w2, proof.ZB[j]))
+		}
+
+		// w3 * (sum(p_i(x) C_i) - sum(x^k C_d,k) - z_d H) = 0, where
+		// p_i(x) = prod_j f_{j,i_j}.
+		w3 := scalar.MustRandom()
+		p := []*ristretto255.Scalar{w3}
+		for j := 0; j < m; j++ {
+			f1 := proof.F[j]
+			f0 := ristretto255.NewScalar().Subtract(x, f1)
+			next := make([]*ristretto255.Scalar, 2*len(p))
+			for i := range p {
+				next[i] = ristretto255.NewScalar().Multiply(p[i], f0)
+				next[i+len(p)] = ristretto255.NewScalar().Multiply(p[i], f1)
+			}
+			p = next
+		}
+		for i := range setScalars {
+			setScalars[i].Add(setScalars[i], p[i])
+		}
+		xk := ristretto255.NewScalar().Set(w3)
+		for j := 0; j < m; j++ {
+			scalars = append(scalars, ristretto255.NewScalar().Negate(xk))
+			points = append(points, proof.CD[j])
+			xk.Multiply(xk, x)
+		}
+		hScalar.Subtract(hScalar, tmp.Multiply(w3, proof.ZD))
+	}
+
+	scalars = append(scalars, setScalars...)
+	points = append(points, set...)
+	scalars = append(scalars, gScalar, hScalar)
+	points = append(points, pc.B, pc.BBlinding)
+
+	check := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(scalars, points)
+	if check.Equal(ristretto255.NewIdentityElement()) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+// Bytes returns the encoding of the proof: for each bit, C_l, C_a, C_b,
+// C_d, f, z_a and z_b, followed by z_d, for a total of 224 * lg(N) + 32
+// bytes.
+func (p *Proof) Bytes() []byte {
+	out := make([]byte, 0, 224*len(p.CL)+32)
+	for j := range p.CL {
+		for _, e := range []*ristretto255.Element{p.CL[j], p.CA[j], p.CB[j], p.CD[j]} {
+			out = append(out, e.Bytes()...)
+		}
+		for _, s := range []*ristretto255.Scalar{p.F[j], p.ZA[j], p.ZB[j]} {
+			out = append(out, s.Bytes()...)
+		}
+	}
+	return append(out, p.ZD.Bytes()...)
+}
+
+// SetBytes sets p to the decoded encoding b, and returns p. If b is not a
+// canonical encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (p *Proof) SetBytes(b []byte) (*Proof, error) {
+	if len(b) < 224+32 || (len(b)-32)%224 != 0 {
+		return nil, errFormat
+	}
+	q := &Proof{}
+	for ; len(b) > 32; b = b[224:] {
+		for i, e := range []*[]*ristretto255.Element{&q.CL, &q.CA, &q.CB, &q.CD} {
+			el, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[32*i : 32*(i+1)])
+			if err != nil {
+				return nil, errFormat
+			}
+			*e = append(*e, el)
+		}
+		for i, s := range []*[]*ristretto255.Scalar{&q.F, &q.ZA, &q.ZB} {
+			sc, err := ristretto255.NewScalar().SetCanonicalBytes(b[32*(4+i) : 32*(5+i)])
+			if err != nil {
+				return nil, errFormat
+			}
+			*s = append(*s, sc)
+		}
+	}
+	var err error
+	if q.ZD, err = ristretto255.NewScalar().SetCanonicalBytes(b); err != nil {
+		return nil, errFormat
+	}
+	*p = *q
+	return p, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oneofmany
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/bulletproofs"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+func randomSet(pc *bulletproofs.PedersenGens, n int) []*ristretto255.Element {
+	set := make([]*ristretto255.Element, n)
+	for i := range set {
+		set[i] = pc.Commit(scalar.MustRandom(), scalar.MustRandom())
+	}
+	return set
+}
+
+func TestOneOfMany(t *testing.T) {
+	pc := bulletproofs.DefaultPedersenGens()
+
+	for _, n := range []int{2, 3, 8, 13, 64} {
+		set := randomSet(pc, n)
+		for _, index := range []int{0, n / 2, n - 1} {
+			r := scalar.MustRandom()
+			set[index] = pc.Commit(ristretto255.NewScalar(), r)
+
+			proof, err := Prove(transcript.New("test"), pc, set, index, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(transcript.New("test"), pc, set, proof); err != nil {
+				t.Errorf("n = %d, index = %d: valid proof failed to verify: %v", n, index, err)
+			}
+			decoded, err := new(Proof).SetBytes(proof.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(transcript.New("test"), pc, set, decoded); err != nil {
+				t.Errorf("n = %d, index = %d: decoded proof failed to verify: %v", n, index, err)
+			}
+
+			other := append([]*ristretto255.Element(nil), set...)
+			other[index] = pc.Commit(scalar.One(), r)
+			if err := Verify(transcript.New("test"), pc, other, proof); err == nil {
+				t.Errorf("n = %d, index = %d: proof verified for a different set", n, index)
+			}
+			if err := Verify(transcript.New("other"), pc, set, proof); err == nil {
+				t.Errorf("n = %d, index = %d: proof verified with a different transcript", n, index)
+			}
+		}
+	}
+}
+
+func TestWrongOpening(t *testing.T) {
+	pc := bulletproofs.DefaultPedersenGens()
+	set := randomSet(pc, 16)
+	r := scalar.MustRandom()
+	// Commitment to a nonzero value.
+	set[5] = pc.Commit(scalar.One(), r)
+
+	proof, err := Prove(transcript.New("test"), pc, set, 5, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(transcript.New("test"), pc, set, proof); err == nil {
+		t.Error("proof for a nonzero commitment verified")
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	pc := bulletproofs.DefaultPedersenGens()
+	set := randomSet(pc, 32)
+
+	indices := []int{1, 7, 30}
+	openings := make(map[int]*ristretto255.Scalar)
+	for _, index := range indices {
+		openings[index] = scalar.MustRandom()
+		set[index] = pc.Commit(ristretto255.NewScalar(), openings[index])
+	}
+
+	var proofs []*Proof
+	var ts []*transcript.Transcript
+	for _, index := range append(indices, 7) {
+		proof, err := Prove(transcript.New("test"), pc, set, index, openings[index])
+		if err != nil {
+			t.Fatal(err)
+		}
+		proofs = append(proofs, proof)
+		ts = append(ts, transcript.New("test"))
+	}
+	clone := func() []*transcript.Transcript {
+		out := make([]*transcript.Transcript, len(ts))
+		for i := range ts {
+			out[i] = ts[i].Clone()
+		}
+		return out
+	}
+	if err := VerifyBatch(clone(), pc, set, proofs); err != nil {
+		t.Errorf("valid batch failed to verify: %v", err)
+	}
+
+	if err := VerifyBatch(clone()[1:], pc, set, proofs); err == nil {
+		t.Error("batch with fewer transcripts than proofs verified")
+	}
+	if err := VerifyBatch(clone(), pc, set, append(proofs[:3:3], nil)); err == nil {
+		t.Error("batch with a nil proof verified")
+	}
+	incomplete := *proofs[2]
+	incomplete.F = append(incomplete.F[:len(incomplete.F)-1:len(incomplete.F)-1], nil)
+	if err := VerifyBatch(clone(), pc, set, append(proofs[:2:2], &incomplete, proofs[3])); err == nil {
+		t.Error("batch with a missing response verified")
+	}
+
+	proofs[2].ZD = scalar.MustRandom()
+	if err := VerifyBatch(clone(), pc, set, proofs); err == nil {
+		t.Error("batch with an invalid proof verified")
+	}
+}
+
+func TestSetBytesErrors(t *testing.T) {
+	for _, l := range []int{0, 32, 224, 224 + 64} {
+		if _, err := new(Proof).SetBytes(make([]byte, l)); err == nil {
+			t.Errorf("accepted a proof of %d bytes", l)
+		}
+	}
+}
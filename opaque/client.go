@@ -0,0 +1,169 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opaque
+
+import (
+	"crypto/hmac"
+	"errors"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// Client runs the Client side of registration and login.
+type Client struct {
+	cfg *Config
+}
+
+// NewClient returns a Client with the given configuration.
+func NewClient(cfg *Config) *Client {
+	return &Client{cfg: cfg}
+}
+
+// ClientRegistration holds the secret state of a pending registration.
+type ClientRegistration struct {
+	cfg      *Config
+	password []byte
+	blind    *ristretto255.Scalar
+	blinded  *ristretto255.Element
+}
+
+// Register starts the registration of password, and returns the request to
+// send to the Server.
+func (c *Client) Register(password []byte) (*ClientRegistration, *RegistrationRequest, error) {
+	blind, err := scalar.Random(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.register(password, blind)
+}
+
+func (c *Client) register(password []byte, blind *ristretto255.Scalar) (*ClientRegistration, *RegistrationRequest, error) {
+	blinded, err := oprfSuite.BlindWithScalar(password, blind)
+	if err != nil {
+		return nil, nil, err
+	}
+	st := &ClientRegistration{
+		cfg:      c.cfg,
+		password: append([]byte(nil), password...),
+		blind:    blind,
+		blinded:  blinded,
+	}
+	return st, &RegistrationRequest{Blinded: blinded}, nil
+}
+
+// Finalize processes the Server response, and returns the record to upload
+// to the Server and the export key. The identities are optional, and must
+// match the ones used at login.
+func (st *ClientRegistration) Finalize(resp *RegistrationResponse, serverIdentity, clientIdentity []byte) (record *RegistrationRecord, exportKey []byte, err error) {
+	nonce, err := randomBytes(nil, NonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return st.finalize(resp, serverIdentity, clientIdentity, nonce)
+}
+
+func (st *ClientRegistration) finalize(resp *RegistrationResponse, serverIdentity, clientIdentity, nonce []byte) (*RegistrationRecord, []byte, error) {
+	rwd, err := st.cfg.randomizedPassword(st.password, st.blind, st.blinded, resp.Evaluated)
+	if err != nil {
+		return nil, nil, err
+	}
+	envelope, clientPublicKey, maskingKey, exportKey, err := store(rwd, resp.ServerPublicKey.Bytes(), serverIdentity, clientIdentity, nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	pk, err := decodeElements(clientPublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	record := &RegistrationRecord{ClientPublicKey: pk[0], MaskingKey: maskingKey, Envelope: envelope}
+	return record, exportKey, nil
+}
+
+// ClientLogin holds the secret state of a pending login.
+type ClientLogin struct {
+	cfg      *Config
+	password []byte
+	blind    *ristretto255.Scalar
+	secret   *ristretto255.Scalar
+	ke1      *KE1
+}
+
+// Login starts a login with password, and returns the KE1 message to send
+// to the Server.
+func (c *Client) Login(password []byte) (*ClientLogin, *KE1, error) {
+	blind, err := scalar.Random(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := randomBytes(nil, NonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	seed, err := randomBytes(nil, SeedSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyshare, err := deriveKeyPair(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.login(password, blind, nonce, keyshare)
+}
+
+func (c *Client) login(password []byte, blind *ristretto255.Scalar, nonce []byte, keyshare *PrivateKey) (*ClientLogin, *KE1, error) {
+	blinded, err := oprfSuite.BlindWithScalar(password, blind)
+	if err != nil {
+		return nil, nil, err
+	}
+	ke1 := &KE1{Blinded: blinded, ClientKeyshare: keyshare.pub}
+	copy(ke1.ClientNonce[:], nonce)
+	st := &ClientLogin{
+		cfg:      c.cfg,
+		password: append([]byte(nil), password...),
+		blind:    blind,
+		secret:   keyshare.k,
+		ke1:      ke1,
+	}
+	return st, ke1, nil
+}
+
+// Finish processes the Server KE2 message. On success, it returns the KE3
+// message to send to the Server, the session key, and the export key. The
+// identities must match the ones used at registration.
+func (st *ClientLogin) Finish(ke2 *KE2, serverIdentity, clientIdentity []byte) (ke3 *KE3, sessionKey, exportKey []byte, err error) {
+	if st.ke1 == nil {
+		return nil, nil, nil, errors.New("opaque: login already finished")
+	}
+	ke1 := st.ke1
+	st.ke1 = nil
+
+	rwd, err := st.cfg.randomizedPassword(st.password, st.blind, ke1.Blinded, ke2.Evaluated)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	maskingKey := expand(rwd, KeySize, []byte("MaskingKey"))
+	unmasked := xor(credentialResponsePad(maskingKey, ke2.MaskingNonce[:]), ke2.MaskedResponse)
+	serverPublicKey, err := decodeElements(unmasked[:PublicKeySize])
+	if err != nil {
+		return nil, nil, nil, errEnvelope
+	}
+	clientKey, sid, cid, exportKey, err := recoverEnvelope(rwd, unmasked[:PublicKeySize], unmasked[PublicKeySize:], serverIdentity, clientIdentity)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var ikm []byte
+	ikm = append(ikm, diffieHellman(st.secret, ke2.ServerKeyshare)...)
+	ikm = append(ikm, diffieHellman(st.secret, serverPublicKey[0])...)
+	ikm = append(ikm, diffieHellman(clientKey.k, ke2.ServerKeyshare)...)
+	pre := preamble(st.cfg.Context, cid, ke1, sid, ke2)
+	km2, km3, sessionKey := deriveKeys(ikm, pre)
+	serverMAC, clientMAC := macs(km2, km3, pre)
+	if !hmac.Equal(serverMAC, ke2.ServerMAC) {
+		return nil, nil, nil, errServerAuth
+	}
+	return &KE3{ClientMAC: clientMAC}, sessionKey, exportKey, nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opaque
+
+import (
+	"errors"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/element"
+)
+
+const (
+	// RegistrationRequestSize is the size in bytes of an encoded
+	// RegistrationRequest.
+	RegistrationRequestSize = 32
+	// RegistrationResponseSize is the size in bytes of an encoded
+	// RegistrationResponse.
+	RegistrationResponseSize = 32 + PublicKeySize
+	// RegistrationRecordSize is the size in bytes of an encoded
+	// RegistrationRecord.
+	RegistrationRecordSize = PublicKeySize + KeySize + EnvelopeSize
+	// KE1Size is the size in bytes of an encoded KE1.
+	KE1Size = 32 + NonceSize + PublicKeySize
+	// KE2Size is the size in bytes of an encoded KE2.
+	KE2Size = 32 + NonceSize + PublicKeySize + EnvelopeSize + NonceSize + PublicKeySize + MACSize
+	// KE3Size is the size in bytes of an encoded KE3.
+	KE3Size = MACSize
+)
+
+// RegistrationRequest is the first registration message, from the Client.
+type RegistrationRequest struct {
+	Blinded *ristretto255.Element
+}
+
+// RegistrationResponse is the second registration message, from the Server.
+type RegistrationResponse struct {
+	Evaluated       *ristretto255.Element
+	ServerPublicKey *ristretto255.Element
+}
+
+// RegistrationRecord is the final registration message, which the Client
+// uploads and the Server stores for use at login.
+type RegistrationRecord struct {
+	ClientPublicKey *ristretto255.Element
+	MaskingKey      []byte
+	Envelope        []byte
+}
+
+// KE1 is the first login message, from the Client.
+type KE1 struct {
+	Blinded        *ristretto255.Element
+	ClientNonce    [NonceSize]byte
+	ClientKeyshare *ristretto255.Element
+}
+
+// KE2 is the second login message, from the Server.
+type KE2 struct {
+	Evaluated      *ristretto255.Element
+	MaskingNonce   [NonceSize]byte
+	MaskedResponse []byte
+	ServerNonce    [NonceSize]byte
+	ServerKeyshare *ristretto255.Element
+	ServerMAC      []byte
+}
+
+// KE3 is the final login message, from the Client.
+type KE3 struct {
+	ClientMAC []byte
+}
+
+// decodeElements decodes len(b) / 32 non-identity Elements.
+func decodeElements(b []byte) ([]*ristretto255.Element, error) {
+	out := make([]*ristretto255.Element, len(b)/32)
+	for i := range out {
+		e, err := element.DecodeNonIdentity(b[32*i : 32*i+32])
+		if err != nil {
+			return nil, errors.New("opaque: invalid element encoding")
+		}
+		out[i] = e
+	}
+	return out, nil
+}
+
+// Bytes returns the RegistrationRequestSize-byte encoding of req.
+func (req *RegistrationRequest) Bytes() []byte {
+	return req.Blinded.Bytes()
+}
+
+// SetBytes sets req to the decoded encoding b, and returns req. If b is not a
+// valid encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (req *RegistrationRequest) SetBytes(b []byte) (*RegistrationRequest, error) {
+	if len(b) != RegistrationRequestSize {
+		return nil, errors.New("opaque: invalid registration request length")
+	}
+	e, err := decodeElements(b)
+	if err != nil {
+		return nil, err
+	}
+	req.Blinded = e[0]
+	return req, nil
+}
+
+// Bytes returns the RegistrationResponseSize-byte encoding of resp.
+func (resp *RegistrationResponse) Bytes() []byte {
+	return append(resp.Evaluated.Bytes(), resp.ServerPublicKey.Bytes()...)
+}
+
+// SetBytes sets resp to the decoded encoding b, and returns resp. If b is not
+// a valid encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (resp *RegistrationResponse) SetBytes(b []byte) (*RegistrationResponse, error) {
+	if len(b) != RegistrationResponseSize {
+		return nil, errors.New("opaque: invalid registration response length")
+	}
+	e, err := decodeElements(b)
+	if err != nil {
+		return nil, err
+	}
+	resp.Evaluated, resp.ServerPublicKey = e[0], e[1]
+	return resp, nil
+}
+
+// Bytes returns the RegistrationRecordSize-byte encoding of rec.
+func (rec *RegistrationRecord) Bytes() []byte {
+	out := make([]byte, 0, RegistrationRecordSize)
+	out = append(out, rec.ClientPublicKey.Bytes()...)
+	out = append(out, rec.MaskingKey...)
+	return append(out, rec.Envelope...)
+}
+
+// SetBytes sets rec to the decoded encoding b, and returns rec. If b is not a
+// valid encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (rec *RegistrationRecord) SetBytes(b []byte) (*RegistrationRecord, error) {
+	if len(b) != RegistrationRecordSize {
+		return nil, errors.New("opaque: invalid registration record length")
+	}
+	e, err := decodeElements(b[:PublicKeySize])
+	if err != nil {
+		return nil, err
+	}
+	b = b[PublicKeySize:]
+	rec.ClientPublicKey = e[0]
+	rec.MaskingKey = append([]byte(nil), b[:KeySize]...)
+	rec.Envelope = append([]byte(nil), b[KeySize:]...)
+	return rec, nil
+}
+
+// Bytes returns the KE1Size-byte encoding of ke1.
+func (ke1 *KE1) Bytes() []byte {
+	out := make([]byte, 0, KE1Size)
+	out = append(out, ke1.Blinded.Bytes()...)
+	out = append(out, ke1.ClientNonce[:]...)
+	return append(out, ke1.ClientKeyshare.Bytes()...)
+}
+
+// SetBytes sets ke1 to the decoded encoding b, and returns ke1. If b is not a
+// valid encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (ke1 *KE1) SetBytes(b []byte) (*KE1, error) {
+	if len(b) != KE1Size {
+		return nil, errors.New("opaque: invalid KE1 length")
+	}
+	blinded, err := decodeElements(b[:32])
+	if err != nil {
+		return nil, err
+	}
+	keyshare, err := decodeElements(b[32+NonceSize:])
+	if err != nil {
+		return nil, err
+	}
+	ke1.Blinded, ke1.ClientKeyshare = blinded[0], keyshare[0]
+	copy(ke1.ClientNonce[:], b[32:32+NonceSize])
+	return ke1, nil
+}
+
+// credentialResponse returns the encoded CredentialResponse part of ke2.
+func (ke2 *KE2) credentialResponse() []byte {
+	out := make([]byte, 0, 32+NonceSize+PublicKeySize+EnvelopeSize)
+	out = append(out, ke2.Evaluated.Bytes()...)
+	out = append(out, ke2.MaskingNonce[:]...)
+	return append(out, ke2.MaskedResponse...)
+}
+
+// Bytes returns the KE2Size-byte encoding of ke2.
+func (ke2 *KE2) Bytes() []byte {
+	out := make([]byte, 0, KE2Size)
+	out = append(out, ke2.credentialResponse()...)
+	out = append(out, ke2.ServerNonce[:]...)
+	out = append(out, ke2.ServerKeyshare.Bytes()...)
+	return append(out, ke2.ServerMAC...)
+}
+
+// SetBytes sets ke2 to the decoded encoding b, and returns ke2. If b is not a
+// valid encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (ke2 *KE2) SetBytes(b []byte) (*KE2, error) {
+	if len(b) != KE2Size {
+		return nil, errors.New("opaque: invalid KE2 length")
+	}
+	evaluated, err := decodeElements(b[:32])
+	if err != nil {
+		return nil, err
+	}
+	b = b[32:]
+	var maskingNonce, serverNonce [NonceSize]byte
+	copy(maskingNonce[:], b[:NonceSize])
+	b = b[NonceSize:]
+	maskedResponse := append([]byte(nil), b[:PublicKeySize+EnvelopeSize]...)
+	b = b[PublicKeySize+EnvelopeSize:]
+	copy(serverNonce[:], b[:NonceSize])
+	b = b[NonceSize:]
+	keyshare, err := decodeElements(b[:PublicKeySize])
+	if err != nil {
+		return nil, err
+	}
+	ke2.Evaluated, ke2.MaskingNonce, ke2.MaskedResponse = evaluated[0], maskingNonce, maskedResponse
+	ke2.ServerNonce, ke2.ServerKeyshare = serverNonce, keyshare[0]
+	ke2.ServerMAC = append([]byte(nil), b[PublicKeySize:]...)
+	return ke2, nil
+}
+
+// Bytes returns the KE3Size-byte encoding of ke3.
+func (ke3 *KE3) Bytes() []byte {
+	return append([]byte(nil), ke3.ClientMAC...)
+}
+
+// SetBytes sets ke3 to the decoded encoding b, and returns ke3. If b is not a
+// valid encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (ke3 *KE3) SetBytes(b []byte) (*KE3, error) {
+	if len(b) != KE3Size {
+		return nil, errors.New("opaque: invalid KE3 length")
+	}
+	ke3.ClientMAC = append([]byte(nil), b...)
+	return ke3, nil
+}
@@ -0,0 +1,298 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package opaque implements the OPAQUE augmented password-authenticated key
+// exchange from RFC 9807, with the ristretto255-SHA512 OPRF and the 3DH key
+// exchange over ristretto255, using HKDF-SHA512 and HMAC-SHA512.
+//
+// During registration, a Client and a Server run an OPRF on the password to
+// produce a RegistrationRecord, which the Server stores under a credential
+// identifier. The record holds an envelope from which only the holder of the
+// password can recover the Client long-term key, and the Server never learns
+// the password. During login, the Client recovers its key with a new OPRF
+// evaluation, and the two parties run 3DH to agree on a session key and to
+// authenticate each other.
+//
+// Passwords should be hardened with a memory-hard key stretching function,
+// such as Argon2id, set as Config.KSF.
+package opaque
+
+import (
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/hkdf"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/oprf"
+)
+
+const (
+	// NonceSize is the size in bytes of the protocol nonces (Nn).
+	NonceSize = 32
+	// SeedSize is the size in bytes of the key derivation seeds (Nseed).
+	SeedSize = 32
+	// PublicKeySize is the size in bytes of an encoded public key (Npk).
+	PublicKeySize = 32
+	// PrivateKeySize is the size in bytes of an encoded private key (Nsk).
+	PrivateKeySize = 32
+	// OPRFSeedSize is the size in bytes of the Server OPRF seed (Nh).
+	OPRFSeedSize = sha512.Size
+	// MACSize is the size in bytes of a MAC (Nm).
+	MACSize = sha512.Size
+	// KeySize is the size in bytes of the session and export keys (Nx, Nh).
+	KeySize = sha512.Size
+	// EnvelopeSize is the size in bytes of an envelope.
+	EnvelopeSize = NonceSize + MACSize
+)
+
+var oprfSuite = oprf.NewSuite(oprf.ModeOPRF)
+
+const (
+	preambleLabel = "OPAQUEv1-"
+	dhKeyInfo     = "OPAQUE-DeriveDiffieHellmanKeyPair"
+)
+
+// Config is the configuration shared by the Client and the Server.
+type Config struct {
+	// Context is an application-specific string bound into the key exchange
+	// transcript.
+	Context []byte
+
+	// KSF is the key stretching function applied to the OPRF output. If nil,
+	// the identity function is used, which is only appropriate for testing.
+	KSF func(oprfOutput []byte) []byte
+}
+
+func (c *Config) stretch(oprfOutput []byte) []byte {
+	if c.KSF == nil {
+		return oprfOutput
+	}
+	return c.KSF(oprfOutput)
+}
+
+var (
+	errEnvelope   = errors.New("opaque: envelope recovery failed")
+	errServerAuth = errors.New("opaque: server authentication failed")
+	errClientAuth = errors.New("opaque: client authentication failed")
+)
+
+// PrivateKey is a long-term Diffie-Hellman key, used by the Server.
+type PrivateKey struct {
+	k   *ristretto255.Scalar
+	pub *ristretto255.Element
+}
+
+// GenerateKey returns a new PrivateKey, reading randomness from rand. If rand
+// is nil, crypto/rand.Reader is used.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	seed, err := randomBytes(rand, SeedSize)
+	if err != nil {
+		return nil, err
+	}
+	return deriveKeyPair(seed)
+}
+
+// NewPrivateKey decodes a PrivateKeySize-byte private key encoding.
+func NewPrivateKey(b []byte) (*PrivateKey, error) {
+	k, err := ristretto255.NewScalar().SetCanonicalBytes(b)
+	if err != nil || scalar.IsZero(k) {
+		return nil, errors.New("opaque: invalid private key")
+	}
+	return &PrivateKey{k: k, pub: ristretto255.NewIdentityElement().ScalarBaseMult(k)}, nil
+}
+
+// Bytes returns the PrivateKeySize-byte encoding of sk.
+func (sk *PrivateKey) Bytes() []byte {
+	return sk.k.Bytes()
+}
+
+// PublicKey returns the PublicKeySize-byte encoding of the public key of sk.
+func (sk *PrivateKey) PublicKey() []byte {
+	return sk.pub.Bytes()
+}
+
+// deriveKeyPair implements DeriveDiffieHellmanKeyPair.
+func deriveKeyPair(seed []byte) (*PrivateKey, error) {
+	k, err := oprfSuite.DeriveKey(seed, []byte(dhKeyInfo))
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{k: k.Scalar(), pub: k.Public().E}, nil
+}
+
+// diffieHellman returns the encoding of k * B.
+func diffieHellman(k *ristretto255.Scalar, B *ristretto255.Element) []byte {
+	return ristretto255.NewIdentityElement().ScalarMult(k, B).Bytes()
+}
+
+func randomBytes(rand io.Reader, n int) ([]byte, error) {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func extract(salt, ikm []byte) []byte {
+	return hkdf.Extract(sha512.New, salt, ikm)
+}
+
+// expand returns n bytes of HKDF-Expand output, with the concatenation of
+// info as the info string.
+func expand(prk []byte, n int, info ...[]byte) []byte {
+	var in []byte
+	for _, b := range info {
+		in = append(in, b...)
+	}
+	return hkdf.Expand(sha512.New, prk, in, n)
+}
+
+func mac(key []byte, msg ...[]byte) []byte {
+	h := hmac.New(sha512.New, key)
+	for _, m := range msg {
+		h.Write(m)
+	}
+	return h.Sum(nil)
+}
+
+// expandLabel implements Expand-Label from RFC 9807, Section 6.4.2.
+func expandLabel(secret []byte, label string, context []byte, n int) []byte {
+	label = "OPAQUE-" + label
+	info := binary.BigEndian.AppendUint16(nil, uint16(n))
+	info = append(info, byte(len(label)))
+	info = append(info, label...)
+	info = append(info, byte(len(context)))
+	info = append(info, context...)
+	return hkdf.Expand(sha512.New, secret, info, n)
+}
+
+// appendPrefixed appends I2OSP(len(b), 2) || b to out.
+func appendPrefixed(out, b []byte) []byte {
+	out = binary.BigEndian.AppendUint16(out, uint16(len(b)))
+	return append(out, b...)
+}
+
+// oprfKey derives the per-credential OPRF key from the Server seed.
+func oprfKey(oprfSeed, credentialID []byte) (*oprf.PrivateKey, error) {
+	seed := expand(oprfSeed, SeedSize, credentialID, []byte("OprfKey"))
+	return oprfSuite.DeriveKey(seed, []byte("OPAQUE-DeriveKeyPair"))
+}
+
+// randomizedPassword finalizes the OPRF evaluation of password, and hardens
+// the output with the configured KSF.
+func (c *Config) randomizedPassword(password []byte, blind *ristretto255.Scalar, blinded, evaluated *ristretto255.Element) ([]byte, error) {
+	outputs, err := oprfSuite.Finalize(nil, [][]byte{password}, []*ristretto255.Scalar{blind},
+		[]*ristretto255.Element{blinded}, []*ristretto255.Element{evaluated}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	ikm := append(append([]byte(nil), outputs[0]...), c.stretch(outputs[0])...)
+	return extract(nil, ikm), nil
+}
+
+// cleartextCredentials returns the encoded CleartextCredentials, with the
+// public keys standing in for missing identities.
+func cleartextCredentials(serverPublicKey, clientPublicKey, serverIdentity, clientIdentity []byte) (encoded, sid, cid []byte) {
+	if len(serverIdentity) == 0 {
+		serverIdentity = serverPublicKey
+	}
+	if len(clientIdentity) == 0 {
+		clientIdentity = clientPublicKey
+	}
+	encoded = append([]byte(nil), serverPublicKey...)
+	encoded = appendPrefixed(encoded, serverIdentity)
+	encoded = appendPrefixed(encoded, clientIdentity)
+	return encoded, serverIdentity, clientIdentity
+}
+
+// store implements Store from RFC 9807, Section 4.1.2, returning the
+// envelope, the client public key, the masking key and the export key.
+func store(randomizedPassword, serverPublicKey, serverIdentity, clientIdentity, nonce []byte) (envelope, clientPublicKey, maskingKey, exportKey []byte, err error) {
+	maskingKey = expand(randomizedPassword, KeySize, []byte("MaskingKey"))
+	authKey := expand(randomizedPassword, KeySize, nonce, []byte("AuthKey"))
+	exportKey = expand(randomizedPassword, KeySize, nonce, []byte("ExportKey"))
+	seed := expand(randomizedPassword, SeedSize, nonce, []byte("PrivateKey"))
+	clientKey, err := deriveKeyPair(seed)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	clientPublicKey = clientKey.PublicKey()
+	creds, _, _ := cleartextCredentials(serverPublicKey, clientPublicKey, serverIdentity, clientIdentity)
+	envelope = append(append([]byte(nil), nonce...), mac(authKey, nonce, creds)...)
+	return envelope, clientPublicKey, maskingKey, exportKey, nil
+}
+
+// recoverEnvelope implements Recover from RFC 9807, Section 4.1.3, returning
+// the client private key, the cleartext credentials with the effective
+// identities, and the export key.
+func recoverEnvelope(randomizedPassword, serverPublicKey, envelope, serverIdentity, clientIdentity []byte) (clientKey *PrivateKey, sid, cid, exportKey []byte, err error) {
+	nonce, authTag := envelope[:NonceSize], envelope[NonceSize:]
+	authKey := expand(randomizedPassword, KeySize, nonce, []byte("AuthKey"))
+	exportKey = expand(randomizedPassword, KeySize, nonce, []byte("ExportKey"))
+	seed := expand(randomizedPassword, SeedSize, nonce, []byte("PrivateKey"))
+	clientKey, err = deriveKeyPair(seed)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	creds, sid, cid := cleartextCredentials(serverPublicKey, clientKey.PublicKey(), serverIdentity, clientIdentity)
+	if !hmac.Equal(mac(authKey, nonce, creds), authTag) {
+		return nil, nil, nil, nil, errEnvelope
+	}
+	return clientKey, sid, cid, exportKey, nil
+}
+
+// credentialResponsePad returns the pad that masks the server public key and
+// the envelope in a CredentialResponse.
+func credentialResponsePad(maskingKey, maskingNonce []byte) []byte {
+	return expand(maskingKey, PublicKeySize+EnvelopeSize, maskingNonce, []byte("CredentialResponsePad"))
+}
+
+func xor(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// preamble returns the 3DH transcript preamble from RFC 9807, Section 6.4.2.
+func preamble(context, clientIdentity []byte, ke1 *KE1, serverIdentity []byte, ke2 *KE2) []byte {
+	out := []byte(preambleLabel)
+	out = appendPrefixed(out, context)
+	out = appendPrefixed(out, clientIdentity)
+	out = append(out, ke1.Bytes()...)
+	out = appendPrefixed(out, serverIdentity)
+	out = append(out, ke2.credentialResponse()...)
+	out = append(out, ke2.ServerNonce[:]...)
+	return append(out, ke2.ServerKeyshare.Bytes()...)
+}
+
+// deriveKeys implements DeriveKeys from RFC 9807, Section 6.4.3, returning
+// the server MAC key, the client MAC key and the session key.
+func deriveKeys(ikm, preamble []byte) (km2, km3, sessionKey []byte) {
+	prk := extract(nil, ikm)
+	h := sha512.Sum512(preamble)
+	handshakeSecret := expandLabel(prk, "HandshakeSecret", h[:], KeySize)
+	sessionKey = expandLabel(prk, "SessionKey", h[:], KeySize)
+	km2 = expandLabel(handshakeSecret, "ServerMAC", nil, MACSize)
+	km3 = expandLabel(handshakeSecret, "ClientMAC", nil, MACSize)
+	return km2, km3, sessionKey
+}
+
+// macs returns the server MAC and the client MAC for the transcript.
+func macs(km2, km3, preamble []byte) (serverMAC, clientMAC []byte) {
+	h := sha512.Sum512(preamble)
+	serverMAC = mac(km2, h[:])
+	h = sha512.Sum512(append(append([]byte(nil), preamble...), serverMAC...))
+	clientMAC = mac(km3, h[:])
+	return serverMAC, clientMAC
+}
@@ -0,0 +1,369 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opaque
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func decodeScalar(t *testing.T, s string) *ristretto255.Scalar {
+	t.Helper()
+	x, err := ristretto255.NewScalar().SetCanonicalBytes(decodeHex(t, s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func decodeKey(t *testing.T, s string) *PrivateKey {
+	t.Helper()
+	k, err := NewPrivateKey(decodeHex(t, s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func checkHex(t *testing.T, name string, got []byte, want string) {
+	t.Helper()
+	if hex.EncodeToString(got) != want {
+		t.Errorf("got %s %x, want %s", name, got, want)
+	}
+}
+
+type rfcVector struct {
+	oprfSeed, credentialIdentifier, password                      string
+	serverPrivateKey, serverPublicKey                             string
+	serverIdentity, clientIdentity                                string
+	envelopeNonce, maskingNonce, serverNonce, clientNonce         string
+	clientKeyshareSeed, serverKeyshareSeed                        string
+	blindRegistration, blindLogin                                 string
+	clientPublicKey, maskingKey, envelope                         string
+	registrationRequest, registrationResponse, registrationUpload string
+	ke1, ke2, ke3, exportKey, sessionKey                          string
+}
+
+// Test vectors for ristretto255-SHA512 from RFC 9807, Appendix C.1.1 and
+// C.1.2, which differ only in the client and server identities.
+var rfcVectors = []rfcVector{
+	{
+		oprfSeed:             "f433d0227b0b9dd54f7c4422b600e764e47fb503f1f9a0f0a47c6606b054a7fdc65347f1a08f277e22358bbabe26f823fca82c7848e9a75661f4ec5d5c1989ef",
+		credentialIdentifier: "31323334",
+		password:             "436f7272656374486f72736542617474657279537461706c65",
+		serverPrivateKey:     "47451a85372f8b3537e249d7b54188091fb18edde78094b43e2ba42b5eb89f0d",
+		serverPublicKey:      "b2fe7af9f48cc502d016729d2fe25cdd433f2c4bc904660b2a382c9b79df1a78",
+		envelopeNonce:        "ac13171b2f17bc2c74997f0fce1e1f35bec6b91fe2e12dbd323d23ba7a38dfec",
+		maskingNonce:         "38fe59af0df2c79f57b8780278f5ae47355fe1f817119041951c80f612fdfc6d",
+		serverNonce:          "71cd9960ecef2fe0d0f7494986fa3d8b2bb01963537e60efb13981e138e3d4a1",
+		clientNonce:          "da7e07376d6d6f034cfa9bb537d11b8c6b4238c334333d1f0aebb380cae6a6cc",
+		clientKeyshareSeed:   "82850a697b42a505f5b68fcdafce8c31f0af2b581f063cf1091933541936304b",
+		serverKeyshareSeed:   "05a4f54206eef1ba2f615bc0aa285cb22f26d1153b5b40a1e85ff80da12f982f",
+		blindRegistration:    "76cfbfe758db884bebb33582331ba9f159720ca8784a2a070a265d9c2d6abe01",
+		blindLogin:           "6ecc102d2e7a7cf49617aad7bbe188556792d4acd60a1a8a8d2b65d4b0790308",
+		clientPublicKey:      "76a845464c68a5d2f7e442436bb1424953b17d3e2e289ccbaccafb57ac5c3675",
+		maskingKey:           "1ac5844383c7708077dea41cbefe2fa15724f449e535dd7dd562e66f5ecfb95864eadddec9db5874959905117dad40a4524111849799281fefe3c51fa82785c5",
+		envelope:             "ac13171b2f17bc2c74997f0fce1e1f35bec6b91fe2e12dbd323d23ba7a38dfec634b0f5b96109c198a8027da51854c35bee90d1e1c781806d07d49b76de6a28b8d9e9b6c93b9f8b64d16dddd9c5bfb5fea48ee8fd2f75012a8b308605cdd8ba5",
+		registrationRequest:  "5059ff249eb1551b7ce4991f3336205bde44a105a032e747d21bf382e75f7a71",
+		registrationResponse: "7408a268083e03abc7097fc05b587834539065e86fb0c7b6342fcf5e01e5b019b2fe7af9f48cc502d016729d2fe25cdd433f2c4bc904660b2a382c9b79df1a78",
+		registrationUpload:   "76a845464c68a5d2f7e442436bb1424953b17d3e2e289ccbaccafb57ac5c36751ac5844383c7708077dea41cbefe2fa15724f449e535dd7dd562e66f5ecfb95864eadddec9db5874959905117dad40a4524111849799281fefe3c51fa82785c5ac13171b2f17bc2c74997f0fce1e1f35bec6b91fe2e12dbd323d23ba7a38dfec634b0f5b96109c198a8027da51854c35bee90d1e1c781806d07d49b76de6a28b8d9e9b6c93b9f8b64d16dddd9c5bfb5fea48ee8fd2f75012a8b308605cdd8ba5",
+		ke1:                  "c4dedb0ba6ed5d965d6f250fbe554cd45cba5dfcce3ce836e4aee778aa3cd44dda7e07376d6d6f034cfa9bb537d11b8c6b4238c334333d1f0aebb380cae6a6cc6e29bee50701498605b2c085d7b241ca15ba5c32027dd21ba420b94ce60da326",
+		ke2:                  "7e308140890bcde30cbcea28b01ea1ecfbd077cff62c4def8efa075aabcbb47138fe59af0df2c79f57b8780278f5ae47355fe1f817119041951c80f612fdfc6dd6ec60bcdb26dc455ddf3e718f1020490c192d70dfc7e403981179d8073d1146a4f9aa1ced4e4cd984c657eb3b54ced3848326f70331953d91b02535af44d9fedc80188ca46743c52786e0382f95ad85c08f6afcd1ccfbff95e2bdeb015b166c6b20b92f832cc6df01e0b86a7efd92c1c804ff865781fa93f2f20b446c8371b671cd9960ecef2fe0d0f7494986fa3d8b2bb01963537e60efb13981e138e3d4a1c4f62198a9d6fa9170c42c3c71f1971b29eb1d5d0bd733e40816c91f7912cc4a660c48dae03e57aaa38f3d0cffcfc21852ebc8b405d15bd6744945ba1a93438a162b6111699d98a16bb55b7bdddfe0fc5608b23da246e7bd73b47369169c5c90",
+		ke3:                  "4455df4f810ac31a6748835888564b536e6da5d9944dfea9e34defb9575fe5e2661ef61d2ae3929bcf57e53d464113d364365eb7d1a57b629707ca48da18e442",
+		exportKey:            "1ef15b4fa99e8a852412450ab78713aad30d21fa6966c9b8c9fb3262a970dc62950d4dd4ed62598229b1b72794fc0335199d9f7fcc6eaedde92cc04870e63f16",
+		sessionKey:           "42afde6f5aca0cfa5c163763fbad55e73a41db6b41bc87b8e7b62214a8eedc6731fa3cb857d657ab9b3764b89a84e91ebcb4785166fbb02cedfcbdfda215b96f",
+	},
+	{
+		oprfSeed:             "f433d0227b0b9dd54f7c4422b600e764e47fb503f1f9a0f0a47c6606b054a7fdc65347f1a08f277e22358bbabe26f823fca82c7848e9a75661f4ec5d5c1989ef",
+		credentialIdentifier: "31323334",
+		password:             "436f7272656374486f72736542617474657279537461706c65",
+		serverPrivateKey:     "47451a85372f8b3537e249d7b54188091fb18edde78094b43e2ba42b5eb89f0d",
+		serverPublicKey:      "b2fe7af9f48cc502d016729d2fe25cdd433f2c4bc904660b2a382c9b79df1a78",
+		serverIdentity:       "626f62",
+		clientIdentity:       "616c696365",
+		envelopeNonce:        "ac13171b2f17bc2c74997f0fce1e1f35bec6b91fe2e12dbd323d23ba7a38dfec",
+		maskingNonce:         "38fe59af0df2c79f57b8780278f5ae47355fe1f817119041951c80f612fdfc6d",
+		serverNonce:          "71cd9960ecef2fe0d0f7494986fa3d8b2bb01963537e60efb13981e138e3d4a1",
+		clientNonce:          "da7e07376d6d6f034cfa9bb537d11b8c6b4238c334333d1f0aebb380cae6a6cc",
+		clientKeyshareSeed:   "82850a697b42a505f5b68fcdafce8c31f0af2b581f063cf1091933541936304b",
+		serverKeyshareSeed:   "05a4f54206eef1ba2f615bc0aa285cb22f26d1153b5b40a1e85ff80da12f982f",
+		blindRegistration:    "76cfbfe758db884bebb33582331ba9f159720ca8784a2a070a265d9c2d6abe01",
+		blindLogin:           "6ecc102d2e7a7cf49617aad7bbe188556792d4acd60a1a8a8d2b65d4b0790308",
+		clientPublicKey:      "76a845464c68a5d2f7e442436bb1424953b17d3e2e289ccbaccafb57ac5c3675",
+		maskingKey:           "1ac5844383c7708077dea41cbefe2fa15724f449e535dd7dd562e66f5ecfb95864eadddec9db5874959905117dad40a4524111849799281fefe3c51fa82785c5",
+		envelope:             "ac13171b2f17bc2c74997f0fce1e1f35bec6b91fe2e12dbd323d23ba7a38dfec1ac902dc5589e9a5f0de56ad685ea8486210ef41449cd4d8712828913c5d2b680b2b3af4a26c765cff329bfb66d38ecf1d6cfa9e7a73c222c6efe0d9520f7d7c",
+		registrationRequest:  "5059ff249eb1551b7ce4991f3336205bde44a105a032e747d21bf382e75f7a71",
+		registrationResponse: "7408a268083e03abc7097fc05b587834539065e86fb0c7b6342fcf5e01e5b019b2fe7af9f48cc502d016729d2fe25cdd433f2c4bc904660b2a382c9b79df1a78",
+		registrationUpload:   "76a845464c68a5d2f7e442436bb1424953b17d3e2e289ccbaccafb57ac5c36751ac5844383c7708077dea41cbefe2fa15724f449e535dd7dd562e66f5ecfb95864eadddec9db5874959905117dad40a4524111849799281fefe3c51fa82785c5ac13171b2f17bc2c74997f0fce1e1f35bec6b91fe2e12dbd323d23ba7a38dfec1ac902dc5589e9a5f0de56ad685ea8486210ef41449cd4d8712828913c5d2b680b2b3af4a26c765cff329bfb66d38ecf1d6cfa9e7a73c222c6efe0d9520f7d7c",
+		ke1:                  "c4dedb0ba6ed5d965d6f250fbe554cd45cba5dfcce3ce836e4aee778aa3cd44dda7e07376d6d6f034cfa9bb537d11b8c6b4238c334333d1f0aebb380cae6a6cc6e29bee50701498605b2c085d7b241ca15ba5c32027dd21ba420b94ce60da326",
+		ke2:                  "7e308140890bcde30cbcea28b01ea1ecfbd077cff62c4def8efa075aabcbb47138fe59af0df2c79f57b8780278f5ae47355fe1f817119041951c80f612fdfc6dd6ec60bcdb26dc455ddf3e718f1020490c192d70dfc7e403981179d8073d1146a4f9aa1ced4e4cd984c657eb3b54ced3848326f70331953d91b02535af44d9fea502150b67fe36795dd8914f164e49f81c7688a38928372134b7dccd50e09f8fed9518b7b2f94835b3c4fe4c8475e7513f20eb97ff0568a39caee3fd6251876f71cd9960ecef2fe0d0f7494986fa3d8b2bb01963537e60efb13981e138e3d4a1c4f62198a9d6fa9170c42c3c71f1971b29eb1d5d0bd733e40816c91f7912cc4a292371e7809a9031743e943fb3b56f51de903552fc91fba4e7419029951c3970b2e2f0a9dea218d22e9e4e0000855bb6421aa3610d6fc0f4033a6517030d4341",
+		ke3:                  "7a026de1d6126905736c3f6d92463a08d209833eb793e46d0f7f15b3e0f62c7643763c02bbc6b8d3d15b63250cae98171e9260f1ffa789750f534ac11a0176d5",
+		exportKey:            "1ef15b4fa99e8a852412450ab78713aad30d21fa6966c9b8c9fb3262a970dc62950d4dd4ed62598229b1b72794fc0335199d9f7fcc6eaedde92cc04870e63f16",
+		sessionKey:           "ae7951123ab5befc27e62e63f52cf472d6236cb386c968cc47b7e34f866aa4bc7638356a73cfce92becf39d6a7d32a1861f12130e824241fe6cab34fbd471a57",
+	},
+}
+
+var rfcContext = []byte("OPAQUE-POC")
+
+func TestRFCVectors(t *testing.T) {
+	cfg := &Config{Context: rfcContext}
+	for _, v := range rfcVectors {
+		sk := decodeKey(t, v.serverPrivateKey)
+		checkHex(t, "server public key", sk.PublicKey(), v.serverPublicKey)
+		serverIdentity := decodeHex(t, v.serverIdentity)
+		clientIdentity := decodeHex(t, v.clientIdentity)
+		server, err := NewServer(cfg, sk, decodeHex(t, v.oprfSeed), serverIdentity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := NewClient(cfg)
+		password := decodeHex(t, v.password)
+		credentialID := decodeHex(t, v.credentialIdentifier)
+
+		reg, req, err := client.register(password, decodeScalar(t, v.blindRegistration))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkHex(t, "registration request", req.Bytes(), v.registrationRequest)
+		resp, err := server.Register(req, credentialID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkHex(t, "registration response", resp.Bytes(), v.registrationResponse)
+		record, exportKey, err := reg.finalize(resp, serverIdentity, clientIdentity, decodeHex(t, v.envelopeNonce))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkHex(t, "client public key", record.ClientPublicKey.Bytes(), v.clientPublicKey)
+		checkHex(t, "masking key", record.MaskingKey, v.maskingKey)
+		checkHex(t, "envelope", record.Envelope, v.envelope)
+		checkHex(t, "registration upload", record.Bytes(), v.registrationUpload)
+		checkHex(t, "registration export key", exportKey, v.exportKey)
+
+		clientKeyshare, err := deriveKeyPair(decodeHex(t, v.clientKeyshareSeed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		serverKeyshare, err := deriveKeyPair(decodeHex(t, v.serverKeyshareSeed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		login, ke1, err := client.login(password, decodeScalar(t, v.blindLogin),
+			decodeHex(t, v.clientNonce), clientKeyshare)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkHex(t, "KE1", ke1.Bytes(), v.ke1)
+		serverLogin, ke2, err := server.login(record, credentialID, clientIdentity, ke1,
+			decodeHex(t, v.maskingNonce), decodeHex(t, v.serverNonce), serverKeyshare)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkHex(t, "KE2", ke2.Bytes(), v.ke2)
+		ke3, clientKey, exportKey, err := login.Finish(ke2, serverIdentity, clientIdentity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkHex(t, "KE3", ke3.Bytes(), v.ke3)
+		checkHex(t, "login export key", exportKey, v.exportKey)
+		checkHex(t, "client session key", clientKey, v.sessionKey)
+		serverKey, err := serverLogin.Finish(ke3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkHex(t, "server session key", serverKey, v.sessionKey)
+	}
+}
+
+type testParties struct {
+	cfg    *Config
+	server *Server
+	client *Client
+	record *RegistrationRecord
+}
+
+func newTestParties(t *testing.T, cfg *Config, password, serverIdentity, clientIdentity []byte) *testParties {
+	t.Helper()
+	sk, err := GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := make([]byte, OPRFSeedSize)
+	rand.Read(seed)
+	server, err := NewServer(cfg, sk, seed, serverIdentity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(cfg)
+
+	reg, req, err := client.Register(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err = new(RegistrationRequest).SetBytes(req.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Register(req, []byte("user"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = new(RegistrationResponse).SetBytes(resp.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, _, err := reg.Finalize(resp, serverIdentity, clientIdentity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err = new(RegistrationRecord).SetBytes(record.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testParties{cfg: cfg, server: server, client: client, record: record}
+}
+
+// login runs a login through the wire encodings, and returns the client and
+// server results.
+func (p *testParties) login(t *testing.T, password, serverIdentity, clientIdentity []byte, record *RegistrationRecord) (clientKey, serverKey []byte, clientErr, serverErr error) {
+	t.Helper()
+	cl, ke1, err := p.client.Login(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ke1, err = new(KE1).SetBytes(ke1.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sl, ke2, err := p.server.Login(record, []byte("user"), clientIdentity, ke1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ke2, err = new(KE2).SetBytes(ke2.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ke3, clientKey, _, clientErr := cl.Finish(ke2, serverIdentity, clientIdentity)
+	if clientErr != nil {
+		return nil, nil, clientErr, nil
+	}
+	ke3, err = new(KE3).SetBytes(ke3.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverKey, serverErr = sl.Finish(ke3)
+	return clientKey, serverKey, nil, serverErr
+}
+
+func TestLogin(t *testing.T) {
+	ksf := func(b []byte) []byte {
+		h := sha256.Sum256(b)
+		return h[:]
+	}
+	for _, cfg := range []*Config{{}, {Context: []byte("app"), KSF: ksf}} {
+		password := []byte("hunter2")
+		p := newTestParties(t, cfg, password, []byte("server"), []byte("alice"))
+
+		ck, sk, cerr, serr := p.login(t, password, []byte("server"), []byte("alice"), p.record)
+		if cerr != nil || serr != nil {
+			t.Fatalf("login failed: %v, %v", cerr, serr)
+		}
+		if !bytes.Equal(ck, sk) || len(ck) != KeySize {
+			t.Error("session keys do not match")
+		}
+
+		if _, _, cerr, _ := p.login(t, []byte("hunter3"), []byte("server"), []byte("alice"), p.record); cerr == nil {
+			t.Error("login with the wrong password succeeded")
+		}
+		if _, _, cerr, _ := p.login(t, password, []byte("other"), []byte("alice"), p.record); cerr == nil {
+			t.Error("login with the wrong server identity succeeded")
+		}
+		if _, _, cerr, _ := p.login(t, password, []byte("server"), []byte("alice"), nil); cerr == nil {
+			t.Error("login with a fake record succeeded")
+		}
+	}
+}
+
+func TestExportKey(t *testing.T) {
+	password := []byte("correct horse")
+	cfg := &Config{}
+	sk, err := GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(cfg, sk, make([]byte, OPRFSeedSize), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(cfg)
+	reg, req, err := client.Register(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Register(req, []byte("user"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, regExportKey, err := reg.Finalize(resp, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cl, ke1, err := client.Login(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ke2, err := server.Login(record, []byte("user"), nil, ke1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, loginExportKey, err := cl.Finish(ke2, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(regExportKey, loginExportKey) {
+		t.Error("export keys do not match")
+	}
+	if _, _, _, err := cl.Finish(ke2, nil, nil); err == nil {
+		t.Error("login state was reused")
+	}
+}
+
+func TestClientAuthentication(t *testing.T) {
+	password := []byte("hunter2")
+	p := newTestParties(t, &Config{}, password, nil, nil)
+	_, ke1, err := p.client.Login(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sl, _, err := p.server.Login(p.record, []byte("user"), nil, ke1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sl.Finish(&KE3{ClientMAC: make([]byte, MACSize)}); err == nil {
+		t.Error("forged KE3 was accepted")
+	}
+}
+
+func TestIdentityKeyshare(t *testing.T) {
+	b := make([]byte, KE1Size)
+	if _, err := new(KE1).SetBytes(b); err == nil {
+		t.Error("KE1 with identity elements was accepted")
+	}
+}
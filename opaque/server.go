@@ -0,0 +1,146 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opaque
+
+import (
+	"crypto/hmac"
+	"errors"
+
+	"github.com/gtank/ristretto255"
+)
+
+// Server runs the Server side of registration and login for all the
+// credentials of a deployment.
+type Server struct {
+	cfg      *Config
+	key      *PrivateKey
+	oprfSeed []byte
+	identity []byte
+}
+
+// NewServer returns a Server with the long-term key sk and the secret
+// OPRFSeedSize-byte oprfSeed, from which the per-credential OPRF keys are
+// derived. identity is optional, and defaults to the Server public key.
+func NewServer(cfg *Config, sk *PrivateKey, oprfSeed, identity []byte) (*Server, error) {
+	if len(oprfSeed) != OPRFSeedSize {
+		return nil, errors.New("opaque: invalid OPRF seed length")
+	}
+	return &Server{
+		cfg:      cfg,
+		key:      sk,
+		oprfSeed: append([]byte(nil), oprfSeed...),
+		identity: append([]byte(nil), identity...),
+	}, nil
+}
+
+// PublicKey returns the encoded Server public key.
+func (s *Server) PublicKey() []byte {
+	return s.key.PublicKey()
+}
+
+// evaluate evaluates the OPRF for credentialID on blinded.
+func (s *Server) evaluate(credentialID []byte, blinded *ristretto255.Element) (*ristretto255.Element, error) {
+	k, err := oprfKey(s.oprfSeed, credentialID)
+	if err != nil {
+		return nil, err
+	}
+	evaluated, _, err := oprfSuite.BlindEvaluate(k, []*ristretto255.Element{blinded}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return evaluated[0], nil
+}
+
+// Register responds to a registration request for the credential identified
+// by credentialID, which must be unique to the Client.
+func (s *Server) Register(req *RegistrationRequest, credentialID []byte) (*RegistrationResponse, error) {
+	evaluated, err := s.evaluate(credentialID, req.Blinded)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrationResponse{Evaluated: evaluated, ServerPublicKey: s.key.pub}, nil
+}
+
+// ServerLogin holds the secret state of a pending login.
+type ServerLogin struct {
+	expectedClientMAC []byte
+	sessionKey        []byte
+}
+
+// Login responds to a KE1 message for the credential identified by
+// credentialID, stored as record. clientIdentity is optional, and must match
+// the one used at registration.
+//
+// If there is no record for credentialID, record must be nil: Login then
+// responds with a fake record, so that the response is indistinguishable
+// from that for a registered Client, and the login will fail at Finish.
+func (s *Server) Login(record *RegistrationRecord, credentialID, clientIdentity []byte, ke1 *KE1) (*ServerLogin, *KE2, error) {
+	if record == nil {
+		fakeKey, err := GenerateKey(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		maskingKey, err := randomBytes(nil, KeySize)
+		if err != nil {
+			return nil, nil, err
+		}
+		record = &RegistrationRecord{
+			ClientPublicKey: fakeKey.pub,
+			MaskingKey:      maskingKey,
+			Envelope:        make([]byte, EnvelopeSize),
+		}
+	}
+	maskingNonce, err := randomBytes(nil, NonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := randomBytes(nil, NonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	seed, err := randomBytes(nil, SeedSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyshare, err := deriveKeyPair(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.login(record, credentialID, clientIdentity, ke1, maskingNonce, nonce, keyshare)
+}
+
+func (s *Server) login(record *RegistrationRecord, credentialID, clientIdentity []byte, ke1 *KE1, maskingNonce, nonce []byte, keyshare *PrivateKey) (*ServerLogin, *KE2, error) {
+	if len(record.MaskingKey) != KeySize || len(record.Envelope) != EnvelopeSize {
+		return nil, nil, errors.New("opaque: invalid registration record")
+	}
+	evaluated, err := s.evaluate(credentialID, ke1.Blinded)
+	if err != nil {
+		return nil, nil, err
+	}
+	ke2 := &KE2{Evaluated: evaluated, ServerKeyshare: keyshare.pub}
+	copy(ke2.MaskingNonce[:], maskingNonce)
+	copy(ke2.ServerNonce[:], nonce)
+	pad := credentialResponsePad(record.MaskingKey, maskingNonce)
+	ke2.MaskedResponse = xor(pad, append(s.key.PublicKey(), record.Envelope...))
+
+	_, sid, cid := cleartextCredentials(s.key.PublicKey(), record.ClientPublicKey.Bytes(), s.identity, clientIdentity)
+	var ikm []byte
+	ikm = append(ikm, diffieHellman(keyshare.k, ke1.ClientKeyshare)...)
+	ikm = append(ikm, diffieHellman(s.key.k, ke1.ClientKeyshare)...)
+	ikm = append(ikm, diffieHellman(keyshare.k, record.ClientPublicKey)...)
+	pre := preamble(s.cfg.Context, cid, ke1, sid, ke2)
+	km2, km3, sessionKey := deriveKeys(ikm, pre)
+	serverMAC, clientMAC := macs(km2, km3, pre)
+	ke2.ServerMAC = serverMAC
+	return &ServerLogin{expectedClientMAC: clientMAC, sessionKey: sessionKey}, ke2, nil
+}
+
+// Finish checks the Client KE3 message, and returns the session key.
+func (st *ServerLogin) Finish(ke3 *KE3) (sessionKey []byte, err error) {
+	if !hmac.Equal(ke3.ClientMAC, st.expectedClientMAC) {
+		return nil, errClientAuth
+	}
+	return st.sessionKey, nil
+}
@@ -0,0 +1,382 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package oprf implements the ristretto255-SHA512 ciphersuite of the
+// Oblivious Pseudorandom Function protocols specified in RFC 9497, in the
+// OPRF, VOPRF and POPRF modes.
+//
+// A client blinds its inputs, a server holding a PrivateKey evaluates the
+// blinded elements, and the client unblinds the evaluations to obtain the PRF
+// outputs, without the server learning the inputs or the outputs. In the
+// verifiable modes the server also returns a single DLEQ proof covering the
+// whole batch, which shows that it used the key matching its PublicKey. The
+// partially-oblivious mode additionally binds a public info string into the
+// PRF.
+package oprf
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/dleq"
+	"github.com/gtank/ristretto255/internal/element"
+	"github.com/gtank/ristretto255/internal/h2c"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// Mode is one of the protocol variants of RFC 9497.
+type Mode byte
+
+const (
+	// ModeOPRF is the base mode, without proofs.
+	ModeOPRF Mode = 0x00
+	// ModeVOPRF is the verifiable mode, where the server proves that it
+	// evaluated the PRF with the key matching its PublicKey.
+	ModeVOPRF Mode = 0x01
+	// ModePOPRF is the partially-oblivious verifiable mode, where a public
+	// info string is included in the PRF evaluation.
+	ModePOPRF Mode = 0x02
+)
+
+const (
+	// OutputSize is the size in bytes of a PRF output.
+	OutputSize = 64
+	// SeedSize is the size in bytes of the seed passed to DeriveKey.
+	SeedSize = 32
+)
+
+var (
+	errInvalidInput = errors.New("oprf: invalid input")
+	errInverse      = errors.New("oprf: tweaked key is not invertible")
+	errVerify       = errors.New("oprf: proof verification failed")
+)
+
+// PrivateKey is a server OPRF key.
+type PrivateKey struct {
+	k  *ristretto255.Scalar
+	pk *PublicKey
+}
+
+// PublicKey is the public key of a server, used by clients to verify proofs
+// in the VOPRF and POPRF modes.
+type PublicKey struct {
+	E *ristretto255.Element
+}
+
+// GenerateKey returns a new PrivateKey, reading randomness from rand. If rand
+// is nil, crypto/rand.Reader is used.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	k, err := scalar.RandomNonZero(rand)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKey(k)
+}
+
+// NewPrivateKey returns the PrivateKey for the secret Scalar k, which must
+// not be zero.
+func NewPrivateKey(k *ristretto255.Scalar) (*PrivateKey, error) {
+	if scalar.IsZero(k) {
+		return nil, errors.New("oprf: invalid private key")
+	}
+	E := ristretto255.NewIdentityElement().ScalarBaseMult(k)
+	return &PrivateKey{k: ristretto255.NewScalar().Set(k), pk: &PublicKey{E: E}}, nil
+}
+
+// Public returns the public key corresponding to sk.
+func (sk *PrivateKey) Public() *PublicKey {
+	return sk.pk
+}
+
+// Scalar returns the secret Scalar of sk.
+func (sk *PrivateKey) Scalar() *ristretto255.Scalar {
+	return ristretto255.NewScalar().Set(sk.k)
+}
+
+// NewPublicKey decodes a 32-byte public key encoding. The identity is
+// rejected.
+func NewPublicKey(b []byte) (*PublicKey, error) {
+	E, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b)
+	if err != nil {
+		return nil, errors.New("oprf: invalid public key encoding")
+	}
+	if element.IsIdentity(E) {
+		return nil, errors.New("oprf: invalid public key")
+	}
+	return &PublicKey{E: E}, nil
+}
+
+// Bytes returns the 32-byte encoding of pk.
+func (pk *PublicKey) Bytes() []byte {
+	return pk.E.Bytes()
+}
+
+// Suite is an instance of the ristretto255-SHA512 ciphersuite in one Mode.
+type Suite struct {
+	mode          Mode
+	contextString []byte
+	proofs        *dleq.Suite
+}
+
+// NewSuite returns the Suite for mode. It panics if mode is not one of
+// ModeOPRF, ModeVOPRF or ModePOPRF.
+func NewSuite(mode Mode) *Suite {
+	if mode > ModePOPRF {
+		panic("oprf: invalid mode")
+	}
+	contextString := append([]byte("OPRFV1-"), byte(mode))
+	contextString = append(contextString, "-ristretto255-SHA512"...)
+	return &Suite{
+		mode:          mode,
+		contextString: contextString,
+		proofs:        dleq.NewSuite(contextString),
+	}
+}
+
+// Mode returns the mode of s.
+func (s *Suite) Mode() Mode {
+	return s.mode
+}
+
+func (s *Suite) hashToGroup(msg []byte) *ristretto255.Element {
+	return h2c.HashToElement(msg, append([]byte("HashToGroup-"), s.contextString...))
+}
+
+func (s *Suite) hashToScalar(msg []byte) *ristretto255.Scalar {
+	return h2c.HashToScalar(msg, append([]byte("HashToScalar-"), s.contextString...))
+}
+
+// DeriveKey deterministically derives a PrivateKey from a SeedSize-byte
+// seed and a public info string, as specified in RFC 9497, Section 3.2.1.
+func (s *Suite) DeriveKey(seed, info []byte) (*PrivateKey, error) {
+	if len(seed) != SeedSize {
+		return nil, errors.New("oprf: invalid seed length")
+	}
+	if len(info) > 0xffff {
+		return nil, errInvalidInput
+	}
+	deriveInput := append([]byte(nil), seed...)
+	deriveInput = appendPrefixed(deriveInput, info)
+	dst := append([]byte("DeriveKeyPair"), s.contextString...)
+	for counter := 0; counter < 256; counter++ {
+		k := h2c.HashToScalar(append(deriveInput, byte(counter)), dst)
+		if !scalar.IsZero(k) {
+			return NewPrivateKey(k)
+		}
+	}
+	return nil, errors.New("oprf: failed to derive key")
+}
+
+// Blind maps input to the group and blinds it with a random Scalar. The
+// blind must be kept by the client and passed to Finalize, while the blinded
+// Element is sent to the server.
+func (s *Suite) Blind(input []byte) (blind *ristretto255.Scalar, blinded *ristretto255.Element, err error) {
+	blind, err = scalar.RandomNonZero(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	blinded, err = s.BlindWithScalar(input, blind)
+	if err != nil {
+		return nil, nil, err
+	}
+	return blind, blinded, nil
+}
+
+// BlindWithScalar is like Blind, but uses the caller-provided blind, which
+// must be uniformly random, secret and not zero. It is mostly useful to
+// reproduce test vectors.
+func (s *Suite) BlindWithScalar(input []byte, blind *ristretto255.Scalar) (*ristretto255.Element, error) {
+	if scalar.IsZero(blind) {
+		return nil, errInvalidInput
+	}
+	if len(input) > 0xffff {
+		return nil, errInvalidInput
+	}
+	P := s.hashToGroup(input)
+	if element.IsIdentity(P) {
+		return nil, errInvalidInput
+	}
+	return P.ScalarMult(blind, P), nil
+}
+
+// checkInfo rejects info strings outside of the POPRF mode, where they would
+// otherwise be silently ignored.
+func (s *Suite) checkInfo(info []byte) error {
+	if s.mode != ModePOPRF && len(info) != 0 {
+		return errors.New("oprf: info is only supported in POPRF mode")
+	}
+	if len(info) > 0xffff {
+		return errInvalidInput
+	}
+	return nil
+}
+
+// tweak returns t = k + HashToScalar("Info" || I2OSP(len(info), 2) || info),
+// the POPRF evaluation key.
+func (s *Suite) tweak(k *ristretto255.Scalar, info []byte) (*ristretto255.Scalar, error) {
+	t := s.hashToScalar(appendPrefixed([]byte("Info"), info))
+	t.Add(t, k)
+	if scalar.IsZero(t) {
+		return nil, errInverse
+	}
+	return t, nil
+}
+
+// BlindEvaluate evaluates a batch of blinded Elements received from a
+// client with sk. In the VOPRF and POPRF modes, it also returns a single
+// proof covering the whole batch. In the OPRF mode, the returned proof is
+// nil. info must be empty unless s is in POPRF mode.
+func (s *Suite) BlindEvaluate(sk *PrivateKey, blinded []*ristretto255.Element, info []byte) ([]*ristretto255.Element, *dleq.Proof, error) {
+	if err := s.checkInfo(info); err != nil {
+		return nil, nil, err
+	}
+	if len(blinded) == 0 {
+		return nil, nil, errors.New("oprf: empty batch")
+	}
+	for _, B := range blinded {
+		if element.IsIdentity(B) {
+			return nil, nil, errInvalidInput
+		}
+	}
+
+	k, t := sk.k, (*ristretto255.Scalar)(nil)
+	if s.mode == ModePOPRF {
+		var err error
+		if t, err = s.tweak(sk.k, info); err != nil {
+			return nil, nil, err
+		}
+		k = ristretto255.NewScalar().Invert(t)
+	}
+	evaluated := make([]*ristretto255.Element, len(blinded))
+	for i, B := range blinded {
+		evaluated[i] = ristretto255.NewIdentityElement().ScalarMult(k, B)
+	}
+
+	var proof *dleq.Proof
+	var err error
+	switch s.mode {
+	case ModeVOPRF:
+		G := ristretto255.NewGeneratorElement()
+		proof, err = s.proofs.Prove(sk.k, G, sk.pk.E, blinded, evaluated)
+	case ModePOPRF:
+		// The proof is for the tweaked key t, for which evaluated[i] are the
+		// bases and blinded[i] are the results.
+		G := ristretto255.NewGeneratorElement()
+		tweakedKey := ristretto255.NewIdentityElement().ScalarBaseMult(t)
+		proof, err = s.proofs.Prove(t, G, tweakedKey, evaluated, blinded)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return evaluated, proof, nil
+}
+
+// Finalize verifies the server proof, if any, unblinds the evaluated
+// Elements and returns the PRF outputs for inputs. inputs, blinds and blinded
+// are the values used with Blind, in the order in which they were sent to the
+// server. In the OPRF mode, pk and proof are ignored and may be nil. info must
+// match the one used by the server.
+func (s *Suite) Finalize(pk *PublicKey, inputs [][]byte, blinds []*ristretto255.Scalar, blinded, evaluated []*ristretto255.Element, proof *dleq.Proof, info []byte) ([][]byte, error) {
+	if err := s.checkInfo(info); err != nil {
+		return nil, err
+	}
+	n := len(inputs)
+	if n == 0 || len(blinds) != n || len(blinded) != n || len(evaluated) != n {
+		return nil, errors.New("oprf: mismatched batch lengths")
+	}
+	// The evaluated elements come from the server, and must be valid
+	// non-identity elements as required by DeserializeElement.
+	for _, e := range evaluated {
+		if e == nil || element.IsIdentity(e) {
+			return nil, errInvalidInput
+		}
+	}
+
+	switch s.mode {
+	case ModeVOPRF:
+		if pk == nil || proof == nil {
+			return nil, errVerify
+		}
+		G := ristretto255.NewGeneratorElement()
+		if err := s.proofs.Verify(G, pk.E, blinded, evaluated, proof); err != nil {
+			return nil, errVerify
+		}
+	case ModePOPRF:
+		if pk == nil || proof == nil {
+			return nil, errVerify
+		}
+		m := s.hashToScalar(appendPrefixed([]byte("Info"), info))
+		tweakedKey := ristretto255.NewIdentityElement().ScalarBaseMult(m)
+		tweakedKey.Add(tweakedKey, pk.E)
+		if element.IsIdentity(tweakedKey) {
+			return nil, errInvalidInput
+		}
+		G := ristretto255.NewGeneratorElement()
+		if err := s.proofs.Verify(G, tweakedKey, evaluated, blinded, proof); err != nil {
+			return nil, errVerify
+		}
+	}
+
+	outputs := make([][]byte, n)
+	inv := ristretto255.NewScalar()
+	for i := range inputs {
+		if len(inputs[i]) > 0xffff {
+			return nil, errInvalidInput
+		}
+		if scalar.IsZero(blinds[i]) {
+			return nil, errInvalidInput
+		}
+		inv.Invert(blinds[i])
+		N := ristretto255.NewIdentityElement().ScalarMult(inv, evaluated[i])
+		outputs[i] = s.output(inputs[i], info, N)
+	}
+	return outputs, nil
+}
+
+// Evaluate computes the PRF output for input directly with the server key,
+// without blinding. It is used by servers to check outputs presented by
+// clients.
+func (s *Suite) Evaluate(sk *PrivateKey, input, info []byte) ([]byte, error) {
+	if err := s.checkInfo(info); err != nil {
+		return nil, err
+	}
+	if len(input) > 0xffff {
+		return nil, errInvalidInput
+	}
+	P := s.hashToGroup(input)
+	if element.IsIdentity(P) {
+		return nil, errInvalidInput
+	}
+	k := sk.k
+	if s.mode == ModePOPRF {
+		t, err := s.tweak(sk.k, info)
+		if err != nil {
+			return nil, err
+		}
+		k = t.Invert(t)
+	}
+	return s.output(input, info, P.ScalarMult(k, P)), nil
+}
+
+// output implements the final hash of Finalize and Evaluate.
+func (s *Suite) output(input, info []byte, N *ristretto255.Element) []byte {
+	var hashInput []byte
+	hashInput = appendPrefixed(hashInput, input)
+	if s.mode == ModePOPRF {
+		hashInput = appendPrefixed(hashInput, info)
+	}
+	hashInput = appendPrefixed(hashInput, N.Bytes())
+	hashInput = append(hashInput, "Finalize"...)
+	h := sha512.Sum512(hashInput)
+	return h[:]
+}
+
+// appendPrefixed appends I2OSP(len(b), 2) || b to out.
+func appendPrefixed(out, b []byte) []byte {
+	out = binary.BigEndian.AppendUint16(out, uint16(len(b)))
+	return append(out, b...)
+}
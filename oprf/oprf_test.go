@@ -0,0 +1,340 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oprf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/dleq"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func decodeElement(t *testing.T, s string) *ristretto255.Element {
+	t.Helper()
+	e, err := ristretto255.NewIdentityElement().SetCanonicalBytes(decodeHex(t, s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func decodeScalar(t *testing.T, s string) *ristretto255.Scalar {
+	t.Helper()
+	x, err := ristretto255.NewScalar().SetCanonicalBytes(decodeHex(t, s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+type rfcVector struct {
+	input, blind, blinded, evaluated, output []string
+	info, proof, r                           string
+}
+
+// Test vectors from RFC 9497, Appendix A.1 (ristretto255-SHA512).
+var rfcVectors = []struct {
+	mode       Mode
+	skSm, pkSm string
+	vectors    []rfcVector
+}{
+	{
+		mode: ModeOPRF,
+		skSm: "5ebcea5ee37023ccb9fc2d2019f9d7737be85591ae8652ffa9ef0f4d37063b0e",
+		vectors: []rfcVector{
+			{
+				input:     []string{"00"},
+				blind:     []string{"64d37aed22a27f5191de1c1d69fadb899d8862b58eb4220029e036ec4c1f6706"},
+				blinded:   []string{"609a0ae68c15a3cf6903766461307e5c8bb2f95e7e6550e1ffa2dc99e412803c"},
+				evaluated: []string{"7ec6578ae5120958eb2db1745758ff379e77cb64fe77b0b2d8cc917ea0869c7e"},
+				output:    []string{"527759c3d9366f277d8c6020418d96bb393ba2afb20ff90df23fb7708264e2f3ab9135e3bd69955851de4b1f9fe8a0973396719b7912ba9ee8aa7d0b5e24bcf6"},
+			},
+			{
+				input:     []string{"5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a"},
+				blind:     []string{"64d37aed22a27f5191de1c1d69fadb899d8862b58eb4220029e036ec4c1f6706"},
+				blinded:   []string{"da27ef466870f5f15296299850aa088629945a17d1f5b7f5ff043f76b3c06418"},
+				evaluated: []string{"b4cbf5a4f1eeda5a63ce7b77c7d23f461db3fcab0dd28e4e17cecb5c90d02c25"},
+				output:    []string{"f4a74c9c592497375e796aa837e907b1a045d34306a749db9f34221f7e750cb4f2a6413a6bf6fa5e19ba6348eb673934a722a7ede2e7621306d18951e7cf2c73"},
+			},
+		},
+	},
+	{
+		mode: ModeVOPRF,
+		skSm: "e6f73f344b79b379f1a0dd37e07ff62e38d9f71345ce62ae3a9bc60b04ccd909",
+		pkSm: "c803e2cc6b05fc15064549b5920659ca4a77b2cca6f04f6b357009335476ad4e",
+		vectors: []rfcVector{
+			{
+				input:     []string{"00"},
+				blind:     []string{"64d37aed22a27f5191de1c1d69fadb899d8862b58eb4220029e036ec4c1f6706"},
+				blinded:   []string{"863f330cc1a1259ed5a5998a23acfd37fb4351a793a5b3c090b642ddc439b945"},
+				evaluated: []string{"aa8fa048764d5623868679402ff6108d2521884fa138cd7f9c7669a9a014267e"},
+				proof:     "ddef93772692e535d1a53903db24367355cc2cc78de93b3be5a8ffcc6985dd066d4346421d17bf5117a2a1ff0fcb2a759f58a539dfbe857a40bce4cf49ec600d",
+				r:         "222a5e897cf59db8145db8d16e597e8facb80ae7d4e26d9881aa6f61d645fc0e",
+				output:    []string{"b58cfbe118e0cb94d79b5fd6a6dafb98764dff49c14e1770b566e42402da1a7da4d8527693914139caee5bd03903af43a491351d23b430948dd50cde10d32b3c"},
+			},
+			{
+				input:     []string{"5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a"},
+				blind:     []string{"64d37aed22a27f5191de1c1d69fadb899d8862b58eb4220029e036ec4c1f6706"},
+				blinded:   []string{"cc0b2a350101881d8a4cba4c80241d74fb7dcbfde4a61fde2f91443c2bf9ef0c"},
+				evaluated: []string{"60a59a57208d48aca71e9e850d22674b611f752bed48b36f7a91b372bd7ad468"},
+				proof:     "401a0da6264f8cf45bb2f5264bc31e109155600babb3cd4e5af7d181a2c9dc0a67154fabf031fd936051dec80b0b6ae29c9503493dde7393b722eafdf5a50b02",
+				r:         "222a5e897cf59db8145db8d16e597e8facb80ae7d4e26d9881aa6f61d645fc0e",
+				output:    []string{"8a9a2f3c7f085b65933594309041fc1898d42d0858e59f90814ae90571a6df60356f4610bf816f27afdd84f47719e480906d27ecd994985890e5f539e7ea74b6"},
+			},
+			{
+				input:     []string{"00", "5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a"},
+				blind:     []string{"64d37aed22a27f5191de1c1d69fadb899d8862b58eb4220029e036ec4c1f6706", "222a5e897cf59db8145db8d16e597e8facb80ae7d4e26d9881aa6f61d645fc0e"},
+				blinded:   []string{"863f330cc1a1259ed5a5998a23acfd37fb4351a793a5b3c090b642ddc439b945", "90a0145ea9da29254c3a56be4fe185465ebb3bf2a1801f7124bbbadac751e654"},
+				evaluated: []string{"aa8fa048764d5623868679402ff6108d2521884fa138cd7f9c7669a9a014267e", "cc5ac221950a49ceaa73c8db41b82c20372a4c8d63e5dded2db920b7eee36a2a"},
+				proof:     "cc203910175d786927eeb44ea847328047892ddf8590e723c37205cb74600b0a5ab5337c8eb4ceae0494c2cf89529dcf94572ed267473d567aeed6ab873dee08",
+				r:         "419c4f4f5052c53c45f3da494d2b67b220d02118e0857cdbcf037f9ea84bbe0c",
+				output:    []string{"b58cfbe118e0cb94d79b5fd6a6dafb98764dff49c14e1770b566e42402da1a7da4d8527693914139caee5bd03903af43a491351d23b430948dd50cde10d32b3c", "8a9a2f3c7f085b65933594309041fc1898d42d0858e59f90814ae90571a6df60356f4610bf816f27afdd84f47719e480906d27ecd994985890e5f539e7ea74b6"},
+			},
+		},
+	},
+	{
+		mode: ModePOPRF,
+		skSm: "145c79c108538421ac164ecbe131942136d5570b16d8bf41a24d4337da981e07",
+		pkSm: "c647bef38497bc6ec077c22af65b696efa43bff3b4a1975a3e8e0a1c5a79d631",
+		vectors: []rfcVector{
+			{
+				input:     []string{"00"},
+				info:      "7465737420696e666f",
+				blind:     []string{"64d37aed22a27f5191de1c1d69fadb899d8862b58eb4220029e036ec4c1f6706"},
+				blinded:   []string{"c8713aa89241d6989ac142f22dba30596db635c772cbf25021fdd8f3d461f715"},
+				evaluated: []string{"1a4b860d808ff19624731e67b5eff20ceb2df3c3c03b906f5693e2078450d874"},
+				proof:     "41ad1a291aa02c80b0915fbfbb0c0afa15a57e2970067a602ddb9e8fd6b7100de32e1ecff943a36f0b10e3dae6bd266cdeb8adf825d86ef27dbc6c0e30c52206",
+				r:         "222a5e897cf59db8145db8d16e597e8facb80ae7d4e26d9881aa6f61d645fc0e",
+				output:    []string{"ca688351e88afb1d841fde4401c79efebb2eb75e7998fa9737bd5a82a152406d38bd29f680504e54fd4587eddcf2f37a2617ac2fbd2993f7bdf45442ace7d221"},
+			},
+			{
+				input:     []string{"5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a"},
+				info:      "7465737420696e666f",
+				blind:     []string{"64d37aed22a27f5191de1c1d69fadb899d8862b58eb4220029e036ec4c1f6706"},
+				blinded:   []string{"f0f0b209dd4d5f1844dac679acc7761b91a2e704879656cb7c201e82a99ab07d"},
+				evaluated: []string{"8c3c9d064c334c6991e99f286ea2301d1bde170b54003fb9c44c6d7bd6fc1540"},
+				proof:     "4c39992d55ffba38232cdac88fe583af8a85441fefd7d1d4a8d0394cd1de77018bf135c174f20281b3341ab1f453fe72b0293a7398703384bed822bfdeec8908",
+				r:         "222a5e897cf59db8145db8d16e597e8facb80ae7d4e26d9881aa6f61d645fc0e",
+				output:    []string{"7c6557b276a137922a0bcfc2aa2b35dd78322bd500235eb6d6b6f91bc5b56a52de2d65612d503236b321f5d0bebcbc52b64b92e426f29c9b8b69f52de98ae507"},
+			},
+			{
+				input:     []string{"00", "5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a5a"},
+				info:      "7465737420696e666f",
+				blind:     []string{"64d37aed22a27f5191de1c1d69fadb899d8862b58eb4220029e036ec4c1f6706", "222a5e897cf59db8145db8d16e597e8facb80ae7d4e26d9881aa6f61d645fc0e"},
+				blinded:   []string{"c8713aa89241d6989ac142f22dba30596db635c772cbf25021fdd8f3d461f715", "423a01c072e06eb1cce96d23acce06e1ea64a609d7ec9e9023f3049f2d64e50c"},
+				evaluated: []string{"1a4b860d808ff19624731e67b5eff20ceb2df3c3c03b906f5693e2078450d874", "aa1f16e903841036e38075da8a46655c94fc92341887eb5819f46312adfc0504"},
+				proof:     "43fdb53be399cbd3561186ae480320caa2b9f36cca0e5b160c4a677b8bbf4301b28f12c36aa8e11e5a7ef551da0781e863a6dc8c0b2bf5a149c9e00621f02006",
+				r:         "419c4f4f5052c53c45f3da494d2b67b220d02118e0857cdbcf037f9ea84bbe0c",
+				output:    []string{"ca688351e88afb1d841fde4401c79efebb2eb75e7998fa9737bd5a82a152406d38bd29f680504e54fd4587eddcf2f37a2617ac2fbd2993f7bdf45442ace7d221", "7c6557b276a137922a0bcfc2aa2b35dd78322bd500235eb6d6b6f91bc5b56a52de2d65612d503236b321f5d0bebcbc52b64b92e426f29c9b8b69f52de98ae507"},
+			},
+		},
+	},
+}
+
+func TestRFC9497Vectors(t *testing.T) {
+	seed := bytes.Repeat([]byte{0xa3}, SeedSize)
+	for _, tc := range rfcVectors {
+		s := NewSuite(tc.mode)
+		sk, err := s.DeriveKey(seed, []byte("test key"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(sk.Scalar().Bytes()); got != tc.skSm {
+			t.Errorf("mode %d: got skSm %s, want %s", tc.mode, got, tc.skSm)
+		}
+		if tc.pkSm != "" {
+			if got := hex.EncodeToString(sk.Public().Bytes()); got != tc.pkSm {
+				t.Errorf("mode %d: got pkSm %s, want %s", tc.mode, got, tc.pkSm)
+			}
+		}
+
+		for _, v := range tc.vectors {
+			info := decodeHex(t, v.info)
+			var inputs [][]byte
+			var blinds []*ristretto255.Scalar
+			var blinded []*ristretto255.Element
+			for i := range v.input {
+				inputs = append(inputs, decodeHex(t, v.input[i]))
+				blinds = append(blinds, decodeScalar(t, v.blind[i]))
+				B, err := s.BlindWithScalar(inputs[i], blinds[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := hex.EncodeToString(B.Bytes()); got != v.blinded[i] {
+					t.Errorf("mode %d: got blinded element %s, want %s", tc.mode, got, v.blinded[i])
+				}
+				blinded = append(blinded, B)
+			}
+
+			evaluated, _, err := s.BlindEvaluate(sk, blinded, info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range evaluated {
+				if got := hex.EncodeToString(evaluated[i].Bytes()); got != v.evaluated[i] {
+					t.Errorf("mode %d: got evaluated element %s, want %s", tc.mode, got, v.evaluated[i])
+				}
+			}
+
+			var proof *dleq.Proof
+			if v.proof != "" {
+				G := ristretto255.NewGeneratorElement()
+				r := decodeScalar(t, v.r)
+				var got *dleq.Proof
+				if tc.mode == ModePOPRF {
+					tw, err := s.tweak(sk.k, info)
+					if err != nil {
+						t.Fatal(err)
+					}
+					tweakedKey := ristretto255.NewIdentityElement().ScalarBaseMult(tw)
+					got, err = s.proofs.ProveWithNonce(tw, G, tweakedKey, evaluated, blinded, r)
+					if err != nil {
+						t.Fatal(err)
+					}
+				} else {
+					got, err = s.proofs.ProveWithNonce(sk.k, G, sk.pk.E, blinded, evaluated, r)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+				if hex.EncodeToString(got.Bytes()) != v.proof {
+					t.Errorf("mode %d: got proof %x, want %s", tc.mode, got.Bytes(), v.proof)
+				}
+				proof, err = new(dleq.Proof).SetBytes(decodeHex(t, v.proof))
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			outputs, err := s.Finalize(sk.Public(), inputs, blinds, blinded, evaluated, proof, info)
+			if err != nil {
+				t.Fatalf("mode %d: Finalize: %v", tc.mode, err)
+			}
+			for i := range outputs {
+				if got := hex.EncodeToString(outputs[i]); got != v.output[i] {
+					t.Errorf("mode %d: got output %s, want %s", tc.mode, got, v.output[i])
+				}
+				direct, err := s.Evaluate(sk, inputs[i], info)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(direct, outputs[i]) {
+					t.Errorf("mode %d: Evaluate does not match Finalize", tc.mode)
+				}
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, mode := range []Mode{ModeOPRF, ModeVOPRF, ModePOPRF} {
+		s := NewSuite(mode)
+		sk, err := GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var info []byte
+		if mode == ModePOPRF {
+			info = []byte("epoch 1")
+		}
+
+		inputs := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+		var blinds []*ristretto255.Scalar
+		var blinded []*ristretto255.Element
+		for _, in := range inputs {
+			r, B, err := s.Blind(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			blinds = append(blinds, r)
+			blinded = append(blinded, B)
+		}
+		evaluated, proof, err := s.BlindEvaluate(sk, blinded, info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (proof == nil) != (mode == ModeOPRF) {
+			t.Fatalf("mode %d: unexpected proof presence", mode)
+		}
+		outputs, err := s.Finalize(sk.Public(), inputs, blinds, blinded, evaluated, proof, info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range inputs {
+			want, err := s.Evaluate(sk, inputs[i], info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(outputs[i], want) || len(want) != OutputSize {
+				t.Errorf("mode %d: output %d does not match Evaluate", mode, i)
+			}
+		}
+		identity := []*ristretto255.Element{evaluated[0], ristretto255.NewIdentityElement(), evaluated[2]}
+		if _, err := s.Finalize(sk.Public(), inputs, blinds, blinded, identity, proof, info); err == nil {
+			t.Errorf("mode %d: identity evaluated element was accepted", mode)
+		}
+		if mode == ModeOPRF {
+			continue
+		}
+
+		// A different key must be caught by the proof.
+		other, err := GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Finalize(other.Public(), inputs, blinds, blinded, evaluated, proof, info); err == nil {
+			t.Errorf("mode %d: proof verified under the wrong public key", mode)
+		}
+		// So must a swapped evaluation.
+		swapped := []*ristretto255.Element{evaluated[1], evaluated[0], evaluated[2]}
+		if _, err := s.Finalize(sk.Public(), inputs, blinds, blinded, swapped, proof, info); err == nil {
+			t.Errorf("mode %d: proof verified for swapped evaluations", mode)
+		}
+		if mode == ModePOPRF {
+			if _, err := s.Finalize(sk.Public(), inputs, blinds, blinded, evaluated, proof, []byte("epoch 2")); err == nil {
+				t.Errorf("mode %d: proof verified for a different info", mode)
+			}
+		}
+	}
+}
+
+func TestInfoOutsidePOPRF(t *testing.T) {
+	s := NewSuite(ModeVOPRF)
+	sk, err := GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Evaluate(sk, []byte("x"), []byte("info")); err == nil {
+		t.Error("info was accepted in VOPRF mode")
+	}
+}
+
+func TestPublicKeyEncoding(t *testing.T) {
+	sk, err := GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := NewPublicKey(sk.Public().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk.E.Equal(sk.Public().E) != 1 {
+		t.Error("public key did not round-trip")
+	}
+	if _, err := NewPublicKey(ristretto255.NewIdentityElement().Bytes()); err == nil {
+		t.Error("identity public key was accepted")
+	}
+}
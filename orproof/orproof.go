@@ -0,0 +1,214 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package orproof implements 1-out-of-n disjunctive Schnorr proofs over
+// ristretto255, following Cramer, Damgård and Schoenmakers (CDS94), made
+// non-interactive with Fiat-Shamir.
+//
+// Each Branch of the disjunction is a statement of knowledge of a single
+// Scalar x such that Y[j] = x * G[j] for all j. A Proof shows that the prover
+// knows the witness of at least one branch without revealing which.
+package orproof
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+var (
+	errVerification = errors.New("orproof: proof verification failed")
+	errFormat       = errors.New("orproof: malformed proof encoding")
+)
+
+// Branch is a conjunction of discrete logarithm statements sharing a single
+// witness x: Y[i] = x * G[i] for all i.
+type Branch struct {
+	Y, G []*ristretto255.Element
+}
+
+// DLog returns the Branch for knowledge of the discrete logarithm of Y with
+// respect to the canonical generator.
+func DLog(Y *ristretto255.Element) Branch {
+	return Branch{Y: []*ristretto255.Element{Y}, G: []*ristretto255.Element{ristretto255.NewGeneratorElement()}}
+}
+
+// Proof is a disjunctive proof with one challenge and one response per
+// branch. The challenges sum to the Fiat-Shamir challenge.
+type Proof struct {
+	Challenges []*ristretto255.Scalar
+	Responses  []*ristretto255.Scalar
+}
+
+func absorbBranches(t *transcript.Transcript, branches []Branch) error {
+	if len(branches) == 0 {
+		return errors.New("orproof: empty disjunction")
+	}
+	t.AppendMessage("dom-sep", []byte("orproof v1"))
+	t.AppendUint64("n", uint64(len(branches)))
+	for _, b := range branches {
+		if len(b.Y) != len(b.G) || len(b.Y) == 0 {
+			return errors.New("orproof: malformed branch")
+		}
+		t.AppendUint64("m", uint64(len(b.Y)))
+		for j := range b.Y {
+			t.AppendElement("G", b.G[j])
+			t.AppendElement("Y", b.Y[j])
+		}
+	}
+	return nil
+}
+
+// simulatedCommitments returns T[j] = s * G[j] + c * Y[j].
+func simulatedCommitments(b Branch, c, s *ristretto255.Scalar) []*ristretto255.Element {
+	out := make([]*ristretto255.Element, len(b.Y))
+	for j := range b.Y {
+		out[j] = ristretto255.NewIdentityElement().VarTimeMultiScalarMult(
+			[]*ristretto255.Scalar{s, c}, []*ristretto255.Element{b.G[j], b.Y[j]})
+	}
+	return out
+}
+
+// Prove returns a proof that the prover knows the witness of one of the
+// branches. x must be the witness of branches[index].
+//
+// Prove does not check the witness: if it is wrong, the proof will fail to
+// verify.
+func Prove(t *transcript.Transcript, branches []Branch, index int, x *ristretto255.Scalar) (*Proof, error) {
+	if index < 0 || index >= len(branches) {
+		return nil, errors.New("orproof: branch index out of range")
+	}
+	if err := absorbBranches(t, branches); err != nil {
+		return nil, err
+	}
+
+	n := len(branches)
+	proof := &Proof{
+		Challenges: make([]*ristretto255.Scalar, n),
+		Responses:  make([]*ristretto255.Scalar, n),
+	}
+	w, err := scalar.Random(nil)
+	if err != nil {
+		return nil, err
+	}
+	sum := ristretto255.NewScalar()
+	for i, b := range branches {
+		var T []*ristretto255.Element
+		if i == index {
+			T = make([]*ristretto255.Element, len(b.G))
+			for j := range b.G {
+				T[j] = ristretto255.NewIdentityElement().ScalarMult(w, b.G[j])
+			}
+		} else {
+			// Simulate the branch with a random challenge and response.
+			if proof.Challenges[i], err = scalar.Random(nil); err != nil {
+				return nil, err
+			}
+			if proof.Responses[i], err = scalar.Random(nil); err != nil {
+				return nil, err
+			}
+			sum.Add(sum, proof.Challenges[i])
+			T = simulatedCommitments(b, proof.Challenges[i], proof.Responses[i])
+		}
+		for _, Tj := range T {
+			t.AppendElement("T", Tj)
+		}
+	}
+
+	c := t.ChallengeScalar("c")
+	ck := ristretto255.NewScalar().Subtract(c, sum)
+	sk := ristretto255.NewScalar().Multiply(ck, x)
+	sk.Subtract(w, sk)
+	proof.Challenges[index], proof.Responses[index] = ck, sk
+	return proof, nil
+}
+
+// Verify checks that proof shows knowledge of the witness of one of the
+// branches.
+func Verify(t *transcript.Transcript, branches []Branch, proof *Proof) error {
+	if proof == nil || slices.Contains(proof.Challenges, nil) || slices.Contains(proof.Responses, nil) ||
+		len(proof.Challenges) != len(branches) || len(proof.Responses) != len(branches) {
+		return errVerification
+	}
+	if err := absorbBranches(t, branches); err != nil {
+		return err
+	}
+	sum := ristretto255.NewScalar()
+	for i, b := range branches {
+		for _, Tj := range simulatedCommitments(b, proof.Challenges[i], proof.Responses[i]) {
+			t.AppendElement("T", Tj)
+		}
+		sum.Add(sum, proof.Challenges[i])
+	}
+	if t.ChallengeScalar("c").Equal(sum) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+// bitBranches returns the branches for an ElGamal ciphertext (C1, C2) under
+// the public key pk encrypting 0 or 1: C1 = r * G and C2 - m * G = r * pk.
+func bitBranches(pk, C1, C2 *ristretto255.Element) []Branch {
+	G := ristretto255.NewGeneratorElement()
+	C2MinusG := ristretto255.NewIdentityElement().Subtract(C2, G)
+	return []Branch{
+		{Y: []*ristretto255.Element{C1, C2}, G: []*ristretto255.Element{G, pk}},
+		{Y: []*ristretto255.Element{C1, C2MinusG}, G: []*ristretto255.Element{G, pk}},
+	}
+}
+
+// ProveBit returns a proof that the exponential ElGamal ciphertext
+// (C1, C2) = (r * G, m * G + r * pk) encrypts m = 0 or m = 1. bit and r
+// are the plaintext and the encryption randomness.
+func ProveBit(t *transcript.Transcript, pk, C1, C2 *ristretto255.Element, bit int, r *ristretto255.Scalar) (*Proof, error) {
+	if bit != 0 && bit != 1 {
+		return nil, errors.New("orproof: plaintext is not a bit")
+	}
+	t.AppendMessage("dom-sep", []byte("elgamal bit"))
+	return Prove(t, bitBranches(pk, C1, C2), bit, r)
+}
+
+// VerifyBit checks that proof shows that (C1, C2) encrypts 0 or 1 under pk.
+func VerifyBit(t *transcript.Transcript, pk, C1, C2 *ristretto255.Element, proof *Proof) error {
+	t.AppendMessage("dom-sep", []byte("elgamal bit"))
+	return Verify(t, bitBranches(pk, C1, C2), proof)
+}
+
+// Bytes returns the encoding of the proof: the challenge and response of
+// each branch, for a total of 64 bytes per branch.
+func (p *Proof) Bytes() []byte {
+	out := make([]byte, 0, 64*len(p.Challenges))
+	for i := range p.Challenges {
+		out = append(out, p.Challenges[i].Bytes()...)
+		out = append(out, p.Responses[i].Bytes()...)
+	}
+	return out
+}
+
+// SetBytes sets p to the decoded encoding b, and returns p. If b is not a
+// canonical encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (p *Proof) SetBytes(b []byte) (*Proof, error) {
+	if len(b) == 0 || len(b)%64 != 0 {
+		return nil, errFormat
+	}
+	q := &Proof{}
+	for ; len(b) > 0; b = b[64:] {
+		c, err := ristretto255.NewScalar().SetCanonicalBytes(b[:32])
+		if err != nil {
+			return nil, errFormat
+		}
+		s, err := ristretto255.NewScalar().SetCanonicalBytes(b[32:64])
+		if err != nil {
+			return nil, errFormat
+		}
+		q.Challenges = append(q.Challenges, c)
+		q.Responses = append(q.Responses, s)
+	}
+	*p = *q
+	return p, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orproof
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+func TestDisjunctiveDLog(t *testing.T) {
+	const n = 5
+	keys := make([]*ristretto255.Scalar, n)
+	branches := make([]Branch, n)
+	for i := range keys {
+		keys[i] = scalar.MustRandom()
+		branches[i] = DLog(ristretto255.NewIdentityElement().ScalarBaseMult(keys[i]))
+	}
+
+	for index := 0; index < n; index++ {
+		proof, err := Prove(transcript.New("test"), branches, index, keys[index])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(transcript.New("test"), branches, proof); err != nil {
+			t.Errorf("index %d: valid proof failed to verify: %v", index, err)
+		}
+		decoded, err := new(Proof).SetBytes(proof.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(transcript.New("test"), branches, decoded); err != nil {
+			t.Errorf("index %d: decoded proof failed to verify: %v", index, err)
+		}
+		if err := Verify(transcript.New("other"), branches, proof); err == nil {
+			t.Errorf("index %d: proof verified with a different transcript", index)
+		}
+		if err := Verify(transcript.New("test"), branches[:n-1], proof); err == nil {
+			t.Errorf("index %d: proof verified for a different set", index)
+		}
+		if err := Verify(transcript.New("test"), branches, nil); err == nil {
+			t.Errorf("index %d: nil proof verified", index)
+		}
+		incomplete := &Proof{Challenges: proof.Challenges, Responses: make([]*ristretto255.Scalar, n)}
+		if err := Verify(transcript.New("test"), branches, incomplete); err == nil {
+			t.Errorf("index %d: proof without responses verified", index)
+		}
+	}
+
+	// Knowing none of the witnesses is not enough.
+	proof, err := Prove(transcript.New("test"), branches, 2, scalar.MustRandom())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(transcript.New("test"), branches, proof); err == nil {
+		t.Error("proof with a wrong witness verified")
+	}
+}
+
+func encryptBit(pk *ristretto255.Element, m int) (C1, C2 *ristretto255.Element, r *ristretto255.Scalar) {
+	r = scalar.MustRandom()
+	C1 = ristretto255.NewIdentityElement().ScalarBaseMult(r)
+	C2 = ristretto255.NewIdentityElement().ScalarMult(r, pk)
+	M := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromInt64(int64(m)))
+	C2.Add(C2, M)
+	return C1, C2, r
+}
+
+func TestElGamalBit(t *testing.T) {
+	pk := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.MustRandom())
+
+	for _, bit := range []int{0, 1} {
+		C1, C2, r := encryptBit(pk, bit)
+		proof, err := ProveBit(transcript.New("vote"), pk, C1, C2, bit, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyBit(transcript.New("vote"), pk, C1, C2, proof); err != nil {
+			t.Errorf("bit %d: valid proof failed to verify: %v", bit, err)
+		}
+		if err := VerifyBit(transcript.New("vote"), pk, C2, C1, proof); err == nil {
+			t.Errorf("bit %d: proof verified for a different ciphertext", bit)
+		}
+	}
+
+	for _, m := range []int{2, -1} {
+		C1, C2, r := encryptBit(pk, m)
+		for _, bit := range []int{0, 1} {
+			proof, err := ProveBit(transcript.New("vote"), pk, C1, C2, bit, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyBit(transcript.New("vote"), pk, C1, C2, proof); err == nil {
+				t.Errorf("proof verified for an encryption of %d", m)
+			}
+		}
+	}
+
+	if _, err := ProveBit(transcript.New("vote"), pk, pk, pk, 2, scalar.One()); err == nil {
+		t.Error("accepted a non-bit plaintext")
+	}
+}
+
+func TestSetBytesErrors(t *testing.T) {
+	for _, l := range []int{0, 32, 96} {
+		if _, err := new(Proof).SetBytes(make([]byte, l)); err == nil {
+			t.Errorf("accepted a proof of %d bytes", l)
+		}
+	}
+}
@@ -0,0 +1,427 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package partiallyblind implements the partially blind Schnorr signatures
+// of Abe and Okamoto, "Provably Secure Partially Blind Signatures" (CRYPTO
+// 2000), over ristretto255.
+//
+// A signature binds a public info string, agreed on by the signer and the
+// user, and a message hidden from the signer. The info is mapped to the
+// Element Z = hash_to_ristretto255(info), and a signature (rho, omega,
+// sigma, delta) on msg is valid if
+//
+//	omega + delta = H(rho * G + omega * X, sigma * G + delta * Z, Z, msg)
+//
+// where H is a transcript hash that also binds the public key X.
+//
+// Like other Schnorr-based blind signatures, the scheme is vulnerable to the
+// ROS attack of Benhamouda et al. (EUROCRYPT 2021) if the signer has many
+// sessions open at the same time for the same info. With l concurrent
+// sessions, the generalized birthday attack of Wagner costs about
+// 2^(252 / (1 + floor(log2(l + 1)))) hash evaluations to forge an extra
+// signature: 2^126 for l = 1 or 2, 2^84 for l = 3, 2^63 for l = 7, and 2^50
+// for l = 15, while l > 252 allows a polynomial time forgery. The Signer
+// therefore allows at most MaxOpenSessions concurrent sessions per info.
+//
+// A session that is never responded to or aborted expires after the
+// Signer's session timeout. Expired sessions no longer count against the
+// bound, and can't be responded to, since responding to them would let an
+// attacker exceed it.
+package partiallyblind
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/h2c"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+const (
+	// CommitmentSize is the size in bytes of an encoded Commitment.
+	CommitmentSize = 64
+	// ResponseSize is the size in bytes of an encoded Response.
+	ResponseSize = 128
+	// SignatureSize is the size in bytes of an encoded Signature.
+	SignatureSize = 128
+
+	// MaxOpenSessions is the largest maxOpenSessions accepted by NewSigner.
+	// See the package documentation for the security loss of each value.
+	MaxOpenSessions = 3
+)
+
+const infoDST = "ristretto255-AbeOkamoto-v1-info"
+
+var (
+	errFormat       = errors.New("partiallyblind: malformed encoding")
+	errVerification = errors.New("partiallyblind: signature verification failed")
+	errInvalidKey   = errors.New("partiallyblind: invalid public key")
+)
+
+func infoElement(info []byte) *ristretto255.Element {
+	return h2c.HashToElement(info, []byte(infoDST))
+}
+
+func challenge(X, alpha, beta, Z *ristretto255.Element, msg []byte) *ristretto255.Scalar {
+	t := transcript.New("ristretto255-AbeOkamoto-v1")
+	t.AppendElement("X", X)
+	t.AppendElement("alpha", alpha)
+	t.AppendElement("beta", beta)
+	t.AppendElement("Z", Z)
+	t.AppendMessage("msg", msg)
+	return t.ChallengeScalar("epsilon")
+}
+
+// Signer holds the signing key and tracks open sessions. It is safe for
+// concurrent use.
+type Signer struct {
+	x *ristretto255.Scalar
+	X *ristretto255.Element
+
+	maxOpen int
+	timeout time.Duration
+	now     func() time.Time
+
+	mu   sync.Mutex
+	open map[string][]*SignerSession
+	// sweepAt is the number of infos with open sessions above which
+	// NewSession reclaims expired sessions for all infos.
+	sweepAt int
+}
+
+// NewSigner returns a Signer for the secret key x, which must not be zero,
+// that allows at most maxOpenSessions sessions per info to be open at once,
+// and expires sessions sessionTimeout after they are opened.
+//
+// maxOpenSessions must be between 1 and MaxOpenSessions. sessionTimeout must
+// be positive, and should be short, since an expired session is rejected by
+// Respond, while an attacker can hold open sessions for an info, and lock
+// out other users, until they expire.
+func NewSigner(x *ristretto255.Scalar, maxOpenSessions int, sessionTimeout time.Duration) (*Signer, error) {
+	if scalar.IsZero(x) {
+		return nil, errors.New("partiallyblind: zero secret key")
+	}
+	if maxOpenSessions < 1 || maxOpenSessions > MaxOpenSessions {
+		return nil, errors.New("partiallyblind: maxOpenSessions out of range")
+	}
+	if sessionTimeout <= 0 {
+		return nil, errors.New("partiallyblind: sessionTimeout must be positive")
+	}
+	return &Signer{
+		x:       ristretto255.NewScalar().Set(x),
+		X:       ristretto255.NewIdentityElement().ScalarBaseMult(x),
+		maxOpen: maxOpenSessions,
+		timeout: sessionTimeout,
+		now:     time.Now,
+		open:    make(map[string][]*SignerSession),
+		sweepAt: 1,
+	}, nil
+}
+
+// reclaim closes the expired sessions for info, and returns the ones still
+// open. It must be called with sg.mu held.
+func (sg *Signer) reclaim(info string, now time.Time) []*SignerSession {
+	open := sg.open[info][:0]
+	for _, ss := range sg.open[info] {
+		if now.After(ss.deadline) {
+			ss.done = true
+			ss.u, ss.s, ss.d = nil, nil, nil
+			continue
+		}
+		open = append(open, ss)
+	}
+	if len(open) == 0 {
+		delete(sg.open, info)
+		return nil
+	}
+	sg.open[info] = open
+	return open
+}
+
+// sweep reclaims expired sessions for all infos once the number of infos
+// with open sessions has doubled since the last sweep, so that sessions for
+// infos that are never used again are eventually released. It must be
+// called with sg.mu held.
+func (sg *Signer) sweep(now time.Time) {
+	if len(sg.open) < sg.sweepAt {
+		return
+	}
+	for info := range sg.open {
+		sg.reclaim(info, now)
+	}
+	sg.sweepAt = 2*len(sg.open) + 1
+}
+
+// Commitment is the signer's first message, a = u * G and
+// b = s * G + d * Z.
+type Commitment struct {
+	A, B *ristretto255.Element
+}
+
+// Response is the signer's second message, with r = u - c * x and
+// c = e - d.
+type Response struct {
+	R, C, S, D *ristretto255.Scalar
+}
+
+// SignerSession is the signer's state for a single session.
+type SignerSession struct {
+	signer  *Signer
+	info    string
+	z       *ristretto255.Element
+	u, s, d *ristretto255.Scalar
+
+	// deadline and done are protected by signer.mu.
+	deadline time.Time
+	done     bool
+}
+
+// NewSession opens a signing session for info, and returns its Commitment
+// for the user. The session must be completed with Respond or released with
+// Abort before the Signer's session timeout. NewSession returns an error if
+// maxOpenSessions unexpired sessions are already open for info.
+func (sg *Signer) NewSession(info []byte) (*SignerSession, *Commitment, error) {
+	rnd := make([]*ristretto255.Scalar, 3)
+	for i := range rnd {
+		var err error
+		if rnd[i], err = scalar.Random(nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ss := &SignerSession{signer: sg, info: string(info), z: infoElement(info), u: rnd[0], s: rnd[1], d: rnd[2]}
+
+	sg.mu.Lock()
+	now := sg.now()
+	sg.sweep(now)
+	open := sg.reclaim(ss.info, now)
+	if len(open) >= sg.maxOpen {
+		sg.mu.Unlock()
+		return nil, nil, errors.New("partiallyblind: too many open sessions for info")
+	}
+	ss.deadline = now.Add(sg.timeout)
+	sg.open[ss.info] = append(open, ss)
+	sg.mu.Unlock()
+
+	B := ristretto255.NewIdentityElement().ScalarMult(ss.d, ss.z)
+	B.Add(B, ristretto255.NewIdentityElement().ScalarBaseMult(ss.s))
+	return ss, &Commitment{A: ristretto255.NewIdentityElement().ScalarBaseMult(ss.u), B: B}, nil
+}
+
+// close marks the session as done and releases its slot. It returns an
+// error if the session was already closed or has expired.
+func (ss *SignerSession) close() error {
+	sg := ss.signer
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	if ss.done {
+		return errors.New("partiallyblind: session already closed")
+	}
+	ss.done = true
+	open := sg.open[ss.info]
+	for i := range open {
+		if open[i] == ss {
+			open = append(open[:i], open[i+1:]...)
+			break
+		}
+	}
+	if len(open) == 0 {
+		delete(sg.open, ss.info)
+	} else {
+		sg.open[ss.info] = open
+	}
+	if sg.now().After(ss.deadline) {
+		ss.u, ss.s, ss.d = nil, nil, nil
+		return errors.New("partiallyblind: session expired")
+	}
+	return nil
+}
+
+// Respond answers the user's blinded challenge e, and closes the session.
+// A session can be responded to only once, and not after it has expired.
+func (ss *SignerSession) Respond(e *ristretto255.Scalar) (*Response, error) {
+	if err := ss.close(); err != nil {
+		return nil, err
+	}
+	u, s, d := ss.u, ss.s, ss.d
+	ss.u, ss.s, ss.d = nil, nil, nil
+
+	c := ristretto255.NewScalar().Subtract(e, d)
+	r := ristretto255.NewScalar().Multiply(c, ss.signer.x)
+	r.Subtract(u, r)
+	return &Response{R: r, C: c, S: s, D: d}, nil
+}
+
+// Abort closes the session without responding.
+func (ss *SignerSession) Abort() {
+	if ss.close() == nil {
+		ss.u, ss.s, ss.d = nil, nil, nil
+	}
+}
+
+// UserSession is the user's state for a single session.
+type UserSession struct {
+	x, z           *ristretto255.Element
+	cm             *Commitment
+	t1, t2, t3, t4 *ristretto255.Scalar
+	e              *ristretto255.Scalar
+}
+
+// Blind returns the user's state and blinded challenge e for a signature on
+// msg with info by the public key X, given the signer's Commitment.
+func Blind(X *ristretto255.Element, info, msg []byte, cm *Commitment) (*UserSession, *ristretto255.Scalar, error) {
+	if X.Equal(ristretto255.NewIdentityElement()) == 1 {
+		return nil, nil, errInvalidKey
+	}
+	rnd := make([]*ristretto255.Scalar, 4)
+	for i := range rnd {
+		var err error
+		if rnd[i], err = scalar.Random(nil); err != nil {
+			return nil, nil, err
+		}
+	}
+	us := &UserSession{
+		x: X, z: infoElement(info), cm: cm,
+		t1: rnd[0], t2: rnd[1], t3: rnd[2], t4: rnd[3],
+	}
+
+	// alpha = a + t1 * G + t2 * X and beta = b + t3 * G + t4 * Z.
+	alpha := ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(us.t2, X, us.t1)
+	alpha.Add(alpha, cm.A)
+	beta := ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(us.t4, us.z, us.t3)
+	beta.Add(beta, cm.B)
+
+	// e = epsilon - t2 - t4.
+	e := challenge(X, alpha, beta, us.z, msg)
+	e.Subtract(e, us.t2)
+	e.Subtract(e, us.t4)
+	us.e = e
+	return us, ristretto255.NewScalar().Set(e), nil
+}
+
+// Unblind checks the signer's Response and returns the unblinded signature
+// (r + t1, c + t2, s + t3, d + t4).
+func (us *UserSession) Unblind(resp *Response) (*Signature, error) {
+	// Check that c + d = e, a = r * G + c * X, and b = s * G + d * Z.
+	cd := ristretto255.NewScalar().Add(resp.C, resp.D)
+	A := ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(resp.C, us.x, resp.R)
+	B := ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(resp.D, us.z, resp.S)
+	if cd.Equal(us.e) != 1 || A.Equal(us.cm.A) != 1 || B.Equal(us.cm.B) != 1 {
+		return nil, errors.New("partiallyblind: invalid signer response")
+	}
+	return &Signature{
+		Rho:   ristretto255.NewScalar().Add(resp.R, us.t1),
+		Omega: ristretto255.NewScalar().Add(resp.C, us.t2),
+		Sigma: ristretto255.NewScalar().Add(resp.S, us.t3),
+		Delta: ristretto255.NewScalar().Add(resp.D, us.t4),
+	}, nil
+}
+
+// Signature is a partially blind signature.
+type Signature struct {
+	Rho, Omega, Sigma, Delta *ristretto255.Scalar
+}
+
+// Verify returns nil if sig is a valid signature on msg with info by the
+// public key X, and an error otherwise.
+func Verify(X *ristretto255.Element, info, msg []byte, sig *Signature) error {
+	if X.Equal(ristretto255.NewIdentityElement()) == 1 {
+		return errInvalidKey
+	}
+	if sig == nil || sig.Rho == nil || sig.Omega == nil || sig.Sigma == nil || sig.Delta == nil {
+		return errVerification
+	}
+	Z := infoElement(info)
+	alpha := ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(sig.Omega, X, sig.Rho)
+	beta := ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(sig.Delta, Z, sig.Sigma)
+	sum := ristretto255.NewScalar().Add(sig.Omega, sig.Delta)
+	if challenge(X, alpha, beta, Z, msg).Equal(sum) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+func encodeScalars(s ...*ristretto255.Scalar) []byte {
+	out := make([]byte, 0, 32*len(s))
+	for _, x := range s {
+		out = append(out, x.Bytes()...)
+	}
+	return out
+}
+
+func decodeScalars(b []byte, n int) ([]*ristretto255.Scalar, error) {
+	if len(b) != 32*n {
+		return nil, errFormat
+	}
+	out := make([]*ristretto255.Scalar, n)
+	for i := range out {
+		var err error
+		if out[i], err = ristretto255.NewScalar().SetCanonicalBytes(b[32*i : 32*(i+1)]); err != nil {
+			return nil, errFormat
+		}
+	}
+	return out, nil
+}
+
+// Bytes returns the 64-byte encoding of the commitment, a || b.
+func (cm *Commitment) Bytes() []byte {
+	return append(cm.A.Bytes(), cm.B.Bytes()...)
+}
+
+// SetBytes sets cm to the decoded 64-byte encoding b, and returns cm. If b is
+// not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (cm *Commitment) SetBytes(b []byte) (*Commitment, error) {
+	if len(b) != CommitmentSize {
+		return nil, errFormat
+	}
+	A, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[:32])
+	if err != nil {
+		return nil, errFormat
+	}
+	B, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[32:])
+	if err != nil {
+		return nil, errFormat
+	}
+	cm.A, cm.B = A, B
+	return cm, nil
+}
+
+// Bytes returns the 128-byte encoding of the response, r || c || s || d.
+func (resp *Response) Bytes() []byte {
+	return encodeScalars(resp.R, resp.C, resp.S, resp.D)
+}
+
+// SetBytes sets resp to the decoded 128-byte encoding b, and returns resp. If
+// b is not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (resp *Response) SetBytes(b []byte) (*Response, error) {
+	s, err := decodeScalars(b, 4)
+	if err != nil {
+		return nil, err
+	}
+	resp.R, resp.C, resp.S, resp.D = s[0], s[1], s[2], s[3]
+	return resp, nil
+}
+
+// Bytes returns the 128-byte encoding of the signature,
+// rho || omega || sigma || delta.
+func (sig *Signature) Bytes() []byte {
+	return encodeScalars(sig.Rho, sig.Omega, sig.Sigma, sig.Delta)
+}
+
+// SetBytes sets sig to the decoded 128-byte encoding b, and returns sig. If
+// b is not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (sig *Signature) SetBytes(b []byte) (*Signature, error) {
+	s, err := decodeScalars(b, 4)
+	if err != nil {
+		return nil, err
+	}
+	sig.Rho, sig.Omega, sig.Sigma, sig.Delta = s[0], s[1], s[2], s[3]
+	return sig, nil
+}
@@ -0,0 +1,292 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package partiallyblind
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+func runSession(t *testing.T, signer *Signer, info, msg []byte) *Signature {
+	session, cm, err := signer.NewSession(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, e, err := Blind(signer.X, info, msg, cm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := session.Respond(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := user.Unblind(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestPartiallyBlindSignature(t *testing.T) {
+	signer, err := NewSigner(scalar.MustRandom(), MaxOpenSessions, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := []byte("epoch=2026-10;tier=gold")
+	msg := []byte("token nonce 0x1234")
+
+	sig := runSession(t, signer, info, msg)
+	if err := Verify(signer.X, info, msg, sig); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(signer.X, []byte("epoch=2026-10;tier=platinum"), msg, sig); err == nil {
+		t.Error("signature verified with different info")
+	}
+	if err := Verify(signer.X, info, []byte("other"), sig); err == nil {
+		t.Error("signature verified for another message")
+	}
+	other, _ := NewSigner(scalar.MustRandom(), 1, time.Minute)
+	if err := Verify(other.X, info, msg, sig); err == nil {
+		t.Error("signature verified for another key")
+	}
+	if err := Verify(signer.X, info, msg, nil); err == nil {
+		t.Error("nil signature verified")
+	}
+	if err := Verify(signer.X, info, msg, &Signature{Rho: sig.Rho, Omega: sig.Omega}); err == nil {
+		t.Error("incomplete signature verified")
+	}
+
+	decoded, err := new(Signature).SetBytes(sig.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(signer.X, info, msg, decoded); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMismatchedInfo(t *testing.T) {
+	// A user who blinds for a different info than the signer commits to
+	// detects it, and gets no valid signature.
+	signer, _ := NewSigner(scalar.MustRandom(), MaxOpenSessions, time.Minute)
+	session, cm, _ := signer.NewSession([]byte("tier=basic"))
+	user, e, err := Blind(signer.X, []byte("tier=gold"), []byte("msg"), cm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := session.Respond(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := user.Unblind(resp); err == nil {
+		t.Error("Unblind accepted a response for different info")
+	}
+}
+
+func TestUnblindRejectsBadResponse(t *testing.T) {
+	signer, _ := NewSigner(scalar.MustRandom(), MaxOpenSessions, time.Minute)
+	session, cm, _ := signer.NewSession([]byte("info"))
+	user, e, _ := Blind(signer.X, []byte("info"), []byte("msg"), cm)
+	resp, _ := session.Respond(e)
+
+	bad := *resp
+	bad.R = ristretto255.NewScalar().Add(resp.R, scalar.One())
+	if _, err := user.Unblind(&bad); err == nil {
+		t.Error("Unblind accepted a modified response")
+	}
+	if _, err := session.Respond(e); err == nil {
+		t.Error("session was responded to twice")
+	}
+}
+
+func TestOpenSessionLimit(t *testing.T) {
+	signer, _ := NewSigner(scalar.MustRandom(), 2, time.Minute)
+	info := []byte("info")
+	a, _, err := signer.NewSession(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := signer.NewSession(info); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := signer.NewSession(info); err == nil {
+		t.Fatal("opened more sessions than allowed")
+	}
+	if _, _, err := signer.NewSession([]byte("other info")); err != nil {
+		t.Fatal("limit is not per info")
+	}
+	a.Abort()
+	a.Abort()
+	if _, _, err := signer.NewSession(info); err != nil {
+		t.Fatal("Abort did not release a session")
+	}
+	if _, _, err := signer.NewSession(info); err == nil {
+		t.Fatal("double Abort released two sessions")
+	}
+	if _, err := a.Respond(scalar.One()); err == nil {
+		t.Error("aborted session was responded to")
+	}
+}
+
+func TestNewSignerBounds(t *testing.T) {
+	x := scalar.MustRandom()
+	for _, n := range []int{-1, 0, MaxOpenSessions + 1} {
+		if _, err := NewSigner(x, n, time.Minute); err == nil {
+			t.Errorf("maxOpenSessions %d accepted", n)
+		}
+	}
+	for _, d := range []time.Duration{-time.Second, 0} {
+		if _, err := NewSigner(x, 1, d); err == nil {
+			t.Errorf("sessionTimeout %v accepted", d)
+		}
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	signer, _ := NewSigner(scalar.MustRandom(), 1, time.Minute)
+	now := time.Now()
+	signer.now = func() time.Time { return now }
+	info := []byte("info")
+
+	stale, _, err := signer.NewSession(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := signer.NewSession(info); err == nil {
+		t.Fatal("opened more sessions than allowed")
+	}
+
+	now = now.Add(2 * time.Minute)
+	fresh, cm, err := signer.NewSession(info)
+	if err != nil {
+		t.Fatal("expired session was not reclaimed")
+	}
+	if _, err := stale.Respond(scalar.One()); err == nil {
+		t.Error("expired session was responded to")
+	}
+	if _, _, err := signer.NewSession(info); err == nil {
+		t.Fatal("responding to an expired session released a slot")
+	}
+
+	msg := []byte("message")
+	user, e, _ := Blind(signer.X, info, msg, cm)
+	resp, err := fresh.Respond(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := user.Unblind(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(signer.X, info, msg, sig); err != nil {
+		t.Error(err)
+	}
+
+	// A session that expires between NewSession and Respond is rejected.
+	late, _, _ := signer.NewSession(info)
+	now = now.Add(2 * time.Minute)
+	if _, err := late.Respond(scalar.One()); err == nil {
+		t.Error("session was responded to after expiring")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	signer, _ := NewSigner(scalar.MustRandom(), 1, time.Minute)
+	now := time.Now()
+	signer.now = func() time.Time { return now }
+	for i := 0; i < 100; i++ {
+		if _, _, err := signer.NewSession([]byte(fmt.Sprint(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(2 * time.Minute)
+	for i := 0; i < 200; i++ {
+		if _, _, err := signer.NewSession([]byte(fmt.Sprint("new", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if _, ok := signer.open[fmt.Sprint(i)]; ok {
+			t.Fatalf("expired sessions for info %d were never reclaimed", i)
+		}
+	}
+}
+
+func TestConcurrentSessions(t *testing.T) {
+	const infos = 5
+	const n = infos * MaxOpenSessions
+	signer, _ := NewSigner(scalar.MustRandom(), MaxOpenSessions, time.Minute)
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info := []byte(fmt.Sprint(i % infos))
+			msg := []byte(fmt.Sprint(i))
+			session, cm, err := signer.NewSession(info)
+			if err != nil {
+				errs <- err
+				return
+			}
+			user, e, _ := Blind(signer.X, info, msg, cm)
+			resp, err := session.Respond(e)
+			if err != nil {
+				errs <- err
+				return
+			}
+			sig, err := user.Unblind(resp)
+			if err != nil {
+				errs <- err
+				return
+			}
+			errs <- Verify(signer.X, info, msg, sig)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if len(signer.open) != 0 {
+		t.Errorf("%d infos still have open sessions", len(signer.open))
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	signer, _ := NewSigner(scalar.MustRandom(), 1, time.Minute)
+	session, cm, _ := signer.NewSession([]byte("info"))
+	cm2, err := new(Commitment).SetBytes(cm.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, e, _ := Blind(signer.X, []byte("info"), []byte("msg"), cm2)
+	resp, _ := session.Respond(e)
+	resp2, err := new(Response).SetBytes(resp.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := user.Unblind(resp2); err != nil {
+		t.Fatal(err)
+	}
+
+	b := resp.Bytes()
+	for i := 96; i < 128; i++ {
+		b[i] = 0xff
+	}
+	if _, err := new(Response).SetBytes(b); err == nil {
+		t.Error("accepted a non-canonical scalar")
+	}
+	if _, err := new(Signature).SetBytes(b[:127]); err == nil {
+		t.Error("accepted a short signature")
+	}
+}
@@ -0,0 +1,481 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pmbtoken implements anonymous tokens with a private metadata bit
+// (PMBTokens) over ristretto255, from Kreuter, Lepoint, Orrù and Raykova,
+// "Anonymous Tokens with Private Metadata Bit", CRYPTO 2020.
+//
+// An Issuer embeds a bit in every batch of tokens it issues. The bit is
+// hidden from the Client, which can't tell tokens carrying different bits
+// apart even though it verifies a Proof that each token is valid, and it is
+// revealed only to the Issuer when the token is redeemed.
+//
+// The Issuer key is made of three Pedersen-style key pairs over the
+// generators G and H: Pub[b] = x[b] * G + y[b] * H for the two bit values, and
+// PubS = xs * G + ys * H for validity. A token for the nonce t is
+//
+//	(t, S, W = x[b] * T + y[b] * S, Ws = xs * T + ys * S)
+//
+// where T = H_t(t), and S is derived from an Issuer-chosen nonce.
+package pmbtoken
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/h2c"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/privacypass"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+const (
+	// NonceSize is the size in bytes of a token nonce.
+	NonceSize = privacypass.NonceSize
+	// TokenSize is the size in bytes of an encoded Token.
+	TokenSize = NonceSize + 3*32
+	// PublicKeySize is the size in bytes of an encoded PublicKey.
+	PublicKeySize = 3 * 32
+	// PrivateKeySize is the size in bytes of an encoded PrivateKey.
+	PrivateKeySize = 6 * 32
+	// MaxBatchSize is the maximum number of tokens in a single request.
+	MaxBatchSize = 0xffff
+)
+
+const (
+	generatorDST = "ristretto255-PMBTokens-v1-generator"
+	hashTDST     = "ristretto255-PMBTokens-v1-hash-t"
+	hashSDST     = "ristretto255-PMBTokens-v1-hash-s"
+)
+
+// generatorH is the second generator of the key commitments, with unknown
+// discrete logarithm with respect to G.
+var generatorH = h2c.HashToElement([]byte("H"), []byte(generatorDST))
+
+var errInvalid = errors.New("pmbtoken: invalid token")
+
+func hashT(nonce []byte) *ristretto255.Element {
+	return h2c.HashToElement(nonce, []byte(hashTDST))
+}
+
+func hashS(T *ristretto255.Element, nonce []byte) *ristretto255.Element {
+	return h2c.HashToElement(append(T.Bytes(), nonce...), []byte(hashSDST))
+}
+
+func newTranscript() *transcript.Transcript {
+	return transcript.New("ristretto255-PMBTokens-v1")
+}
+
+// PrivateKey is an Issuer key.
+type PrivateKey struct {
+	x, y   [2]*ristretto255.Scalar
+	xs, ys *ristretto255.Scalar
+	pk     *PublicKey
+}
+
+// PublicKey is the public key of an Issuer, used by Clients to verify
+// issuance proofs.
+type PublicKey struct {
+	Pub  [2]*ristretto255.Element
+	PubS *ristretto255.Element
+}
+
+// GenerateKey returns a new PrivateKey, reading randomness from rand. If rand
+// is nil, crypto/rand.Reader is used.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	s := make([]*ristretto255.Scalar, 6)
+	for i := range s {
+		var err error
+		if s[i], err = scalar.Random(rand); err != nil {
+			return nil, err
+		}
+	}
+	return newPrivateKey(s), nil
+}
+
+func newPrivateKey(s []*ristretto255.Scalar) *PrivateKey {
+	sk := &PrivateKey{
+		x:  [2]*ristretto255.Scalar{s[0], s[2]},
+		y:  [2]*ristretto255.Scalar{s[1], s[3]},
+		xs: s[4], ys: s[5],
+		pk: &PublicKey{},
+	}
+	pub := func(x, y *ristretto255.Scalar) *ristretto255.Element {
+		return ristretto255.NewIdentityElement().MultiScalarMult(
+			[]*ristretto255.Scalar{x, y}, []*ristretto255.Element{ristretto255.NewGeneratorElement(), generatorH})
+	}
+	sk.pk.Pub[0] = pub(sk.x[0], sk.y[0])
+	sk.pk.Pub[1] = pub(sk.x[1], sk.y[1])
+	sk.pk.PubS = pub(sk.xs, sk.ys)
+	return sk
+}
+
+// Public returns the public key corresponding to sk.
+func (sk *PrivateKey) Public() *PublicKey {
+	return sk.pk
+}
+
+// Bytes returns the PrivateKeySize-byte encoding of sk.
+func (sk *PrivateKey) Bytes() []byte {
+	out := make([]byte, 0, PrivateKeySize)
+	for _, s := range []*ristretto255.Scalar{sk.x[0], sk.y[0], sk.x[1], sk.y[1], sk.xs, sk.ys} {
+		out = append(out, s.Bytes()...)
+	}
+	return out
+}
+
+// NewPrivateKey decodes a PrivateKeySize-byte private key encoding.
+func NewPrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != PrivateKeySize {
+		return nil, errors.New("pmbtoken: invalid private key length")
+	}
+	s := make([]*ristretto255.Scalar, 6)
+	for i := range s {
+		var err error
+		s[i], err = ristretto255.NewScalar().SetCanonicalBytes(b[32*i : 32*i+32])
+		if err != nil {
+			return nil, errors.New("pmbtoken: invalid private key encoding")
+		}
+	}
+	return newPrivateKey(s), nil
+}
+
+// Bytes returns the PublicKeySize-byte encoding of pk, Pub[0] || Pub[1] ||
+// PubS.
+func (pk *PublicKey) Bytes() []byte {
+	out := make([]byte, 0, PublicKeySize)
+	out = append(out, pk.Pub[0].Bytes()...)
+	out = append(out, pk.Pub[1].Bytes()...)
+	return append(out, pk.PubS.Bytes()...)
+}
+
+// NewPublicKey decodes a PublicKeySize-byte public key encoding.
+func NewPublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeySize {
+		return nil, errors.New("pmbtoken: invalid public key length")
+	}
+	e, err := decodeElements(b, 3)
+	if err != nil {
+		return nil, errors.New("pmbtoken: invalid public key encoding")
+	}
+	return &PublicKey{Pub: [2]*ristretto255.Element{e[0], e[1]}, PubS: e[2]}, nil
+}
+
+// Request is a batch of blinded token nonces.
+type Request struct {
+	Blinded []*ristretto255.Element
+}
+
+// Response is the Issuer answer to a Request.
+type Response struct {
+	// Nonces are the Issuer nonces from which the S' values are derived.
+	Nonces [][NonceSize]byte
+	W, Ws  []*ristretto255.Element
+	Proof  *Proof
+}
+
+// Token is a finalized token, presented by a Client at redemption.
+type Token struct {
+	Nonce    [NonceSize]byte
+	S, W, Ws *ristretto255.Element
+}
+
+// Client requests tokens from a single Issuer.
+type Client struct {
+	pk *PublicKey
+}
+
+// NewClient returns a Client for the Issuer with public key pk.
+func NewClient(pk *PublicKey) *Client {
+	return &Client{pk: pk}
+}
+
+// ClientState holds the secret state of a pending Request.
+type ClientState struct {
+	c       *Client
+	nonces  [][NonceSize]byte
+	blinds  []*ristretto255.Scalar
+	blinded []*ristretto255.Element
+}
+
+// NewRequest returns a Request for n tokens, and the state needed to finalize
+// the Issuer response.
+func (c *Client) NewRequest(n int) (*ClientState, *Request, error) {
+	if n <= 0 || n > MaxBatchSize {
+		return nil, nil, errors.New("pmbtoken: invalid batch size")
+	}
+	st := &ClientState{c: c}
+	for range n {
+		var t [NonceSize]byte
+		if _, err := io.ReadFull(rand.Reader, t[:]); err != nil {
+			return nil, nil, err
+		}
+		r, err := scalar.RandomNonZero(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		st.nonces = append(st.nonces, t)
+		st.blinds = append(st.blinds, r)
+		T := hashT(t[:])
+		st.blinded = append(st.blinded, T.ScalarMult(r, T))
+	}
+	return st, &Request{Blinded: append([]*ristretto255.Element(nil), st.blinded...)}, nil
+}
+
+// Finalize verifies the Issuer proof in resp and returns the issued Tokens,
+// in the same order as the request.
+func (st *ClientState) Finalize(resp *Response) ([]*Token, error) {
+	n := len(st.blinded)
+	if resp == nil || len(resp.Nonces) != n || len(resp.W) != n || len(resp.Ws) != n {
+		return nil, errors.New("pmbtoken: mismatched response length")
+	}
+	if slices.Contains(resp.W, nil) || slices.Contains(resp.Ws, nil) {
+		return nil, errors.New("pmbtoken: incomplete response")
+	}
+	S := make([]*ristretto255.Element, n)
+	for i := range S {
+		S[i] = hashS(st.blinded[i], resp.Nonces[i][:])
+	}
+	stmt := batchStatement(newTranscript(), st.c.pk, st.blinded, S, resp.W, resp.Ws)
+	if err := stmt.verify(newTranscript(), resp.Proof); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*Token, n)
+	rInv := ristretto255.NewScalar()
+	for i := range tokens {
+		rInv.Invert(st.blinds[i])
+		tokens[i] = &Token{
+			Nonce: st.nonces[i],
+			S:     ristretto255.NewIdentityElement().ScalarMult(rInv, S[i]),
+			W:     ristretto255.NewIdentityElement().ScalarMult(rInv, resp.W[i]),
+			Ws:    ristretto255.NewIdentityElement().ScalarMult(rInv, resp.Ws[i]),
+		}
+	}
+	return tokens, nil
+}
+
+// Issuer issues and redeems tokens under a single key.
+type Issuer struct {
+	sk    *PrivateKey
+	spent privacypass.SpentStore
+}
+
+// NewIssuer returns an Issuer for sk. Redeemed nonces are recorded in spent,
+// which should be shared by all the Issuers using sk. If spent is nil, a new
+// privacypass.MemoryStore is used.
+func NewIssuer(sk *PrivateKey, spent privacypass.SpentStore) *Issuer {
+	if spent == nil {
+		spent = privacypass.NewMemoryStore()
+	}
+	return &Issuer{sk: sk, spent: spent}
+}
+
+// PublicKey returns the public key of the Issuer, to be distributed to
+// Clients.
+func (i *Issuer) PublicKey() *PublicKey {
+	return i.sk.Public()
+}
+
+// Issue evaluates the blinded nonces of req, embedding bit, which must be 0
+// or 1, in all the resulting tokens.
+func (i *Issuer) Issue(req *Request, bit int) (*Response, error) {
+	if bit != 0 && bit != 1 {
+		return nil, errors.New("pmbtoken: metadata is not a bit")
+	}
+	n := len(req.Blinded)
+	if n == 0 || n > MaxBatchSize {
+		return nil, errors.New("pmbtoken: invalid batch size")
+	}
+	identity := ristretto255.NewIdentityElement()
+	for _, T := range req.Blinded {
+		if T.Equal(identity) == 1 {
+			return nil, errors.New("pmbtoken: invalid blinded element")
+		}
+	}
+
+	// Select the bit key without branching on the secret bit.
+	mask := scalar.FromUint64(uint64(bit))
+	xb := ristretto255.NewScalar().Subtract(i.sk.x[1], i.sk.x[0])
+	xb.Multiply(xb, mask).Add(xb, i.sk.x[0])
+	yb := ristretto255.NewScalar().Subtract(i.sk.y[1], i.sk.y[0])
+	yb.Multiply(yb, mask).Add(yb, i.sk.y[0])
+
+	resp := &Response{
+		Nonces: make([][NonceSize]byte, n),
+		W:      make([]*ristretto255.Element, n),
+		Ws:     make([]*ristretto255.Element, n),
+	}
+	S := make([]*ristretto255.Element, n)
+	for j, T := range req.Blinded {
+		if _, err := io.ReadFull(rand.Reader, resp.Nonces[j][:]); err != nil {
+			return nil, err
+		}
+		S[j] = hashS(T, resp.Nonces[j][:])
+		resp.W[j] = ristretto255.NewIdentityElement().MultiScalarMult(
+			[]*ristretto255.Scalar{xb, yb}, []*ristretto255.Element{T, S[j]})
+		resp.Ws[j] = ristretto255.NewIdentityElement().MultiScalarMult(
+			[]*ristretto255.Scalar{i.sk.xs, i.sk.ys}, []*ristretto255.Element{T, S[j]})
+	}
+
+	stmt := batchStatement(newTranscript(), i.sk.pk, req.Blinded, S, resp.W, resp.Ws)
+	proof, err := stmt.prove(newTranscript(), i.sk, bit)
+	if err != nil {
+		return nil, err
+	}
+	resp.Proof = proof
+	return resp, nil
+}
+
+// Read checks that t is a valid token issued under i, and returns its
+// private metadata bit. It does not check or record the nonce; use Redeem
+// for that.
+func (i *Issuer) Read(t *Token) (bit int, err error) {
+	if t == nil || t.S == nil || t.W == nil || t.Ws == nil {
+		return 0, errInvalid
+	}
+	T := hashT(t.Nonce[:])
+	mul := func(x, y *ristretto255.Scalar) *ristretto255.Element {
+		return ristretto255.NewIdentityElement().MultiScalarMult(
+			[]*ristretto255.Scalar{x, y}, []*ristretto255.Element{T, t.S})
+	}
+	valid := t.Ws.Equal(mul(i.sk.xs, i.sk.ys))
+	is0 := t.W.Equal(mul(i.sk.x[0], i.sk.y[0]))
+	is1 := t.W.Equal(mul(i.sk.x[1], i.sk.y[1]))
+	if valid&(is0|is1) != 1 {
+		return 0, errInvalid
+	}
+	return is1, nil
+}
+
+// Redeem reads t like Read and then records its nonce as spent. It returns
+// an error if the token is invalid or was already redeemed.
+func (i *Issuer) Redeem(t *Token) (bit int, err error) {
+	bit, err = i.Read(t)
+	if err != nil {
+		return 0, err
+	}
+	if !i.spent.MarkSpent(t.Nonce) {
+		return 0, errors.New("pmbtoken: token already redeemed")
+	}
+	return bit, nil
+}
+
+// Bytes returns the TokenSize-byte encoding of t, nonce || S || W || Ws.
+func (t *Token) Bytes() []byte {
+	out := make([]byte, 0, TokenSize)
+	out = append(out, t.Nonce[:]...)
+	out = append(out, t.S.Bytes()...)
+	out = append(out, t.W.Bytes()...)
+	return append(out, t.Ws.Bytes()...)
+}
+
+// SetBytes sets t to the decoded TokenSize-byte encoding b, and returns t. If
+// b is not a valid encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (t *Token) SetBytes(b []byte) (*Token, error) {
+	if len(b) != TokenSize {
+		return nil, errors.New("pmbtoken: invalid token length")
+	}
+	e, err := decodeElements(b[NonceSize:], 3)
+	if err != nil {
+		return nil, errors.New("pmbtoken: invalid token encoding")
+	}
+	copy(t.Nonce[:], b[:NonceSize])
+	t.S, t.W, t.Ws = e[0], e[1], e[2]
+	return t, nil
+}
+
+// Bytes returns the encoding of req, a 16-bit big-endian count followed by
+// the blinded elements.
+func (req *Request) Bytes() []byte {
+	out := binary.BigEndian.AppendUint16(nil, uint16(len(req.Blinded)))
+	for _, e := range req.Blinded {
+		out = append(out, e.Bytes()...)
+	}
+	return out
+}
+
+// SetBytes sets req to the decoded encoding b, and returns req. If b is not
+// a valid encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (req *Request) SetBytes(b []byte) (*Request, error) {
+	if len(b) < 2 {
+		return nil, errors.New("pmbtoken: invalid request length")
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if n == 0 || len(b) != 2+32*n {
+		return nil, errors.New("pmbtoken: invalid request length")
+	}
+	e, err := decodeElements(b[2:], n)
+	if err != nil {
+		return nil, errors.New("pmbtoken: invalid request encoding")
+	}
+	req.Blinded = e
+	return req, nil
+}
+
+// Bytes returns the encoding of resp, a 16-bit big-endian count followed by
+// nonce || W || Ws for each token, and the proof.
+func (resp *Response) Bytes() []byte {
+	n := len(resp.Nonces)
+	out := make([]byte, 0, 2+96*n+ProofSize)
+	out = binary.BigEndian.AppendUint16(out, uint16(n))
+	for i := range resp.Nonces {
+		out = append(out, resp.Nonces[i][:]...)
+		out = append(out, resp.W[i].Bytes()...)
+		out = append(out, resp.Ws[i].Bytes()...)
+	}
+	return append(out, resp.Proof.Bytes()...)
+}
+
+// SetBytes sets resp to the decoded encoding b, and returns resp. If b is
+// not a valid encoding, SetBytes returns nil and an error, and the receiver
+// is unchanged.
+func (resp *Response) SetBytes(b []byte) (*Response, error) {
+	if len(b) < 2 {
+		return nil, errors.New("pmbtoken: invalid response length")
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if n == 0 || len(b) != 2+96*n+ProofSize {
+		return nil, errors.New("pmbtoken: invalid response length")
+	}
+	b = b[2:]
+	r := &Response{
+		Nonces: make([][NonceSize]byte, n),
+		W:      make([]*ristretto255.Element, n),
+		Ws:     make([]*ristretto255.Element, n),
+	}
+	for i := range n {
+		copy(r.Nonces[i][:], b[:NonceSize])
+		e, err := decodeElements(b[NonceSize:96], 2)
+		if err != nil {
+			return nil, errors.New("pmbtoken: invalid response encoding")
+		}
+		r.W[i], r.Ws[i] = e[0], e[1]
+		b = b[96:]
+	}
+	proof, err := new(Proof).SetBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	resp.Nonces, resp.W, resp.Ws, resp.Proof = r.Nonces, r.W, r.Ws, proof
+	return resp, nil
+}
+
+func decodeElements(b []byte, n int) ([]*ristretto255.Element, error) {
+	out := make([]*ristretto255.Element, n)
+	for i := range out {
+		var err error
+		out[i], err = ristretto255.NewIdentityElement().SetCanonicalBytes(b[32*i : 32*i+32])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
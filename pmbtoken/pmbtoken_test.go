@@ -0,0 +1,232 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pmbtoken
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+)
+
+// newIssuer returns an Issuer for a fresh key, along with the key, which
+// some tests use to evaluate tokens outside of Issue.
+func newIssuer(t *testing.T) (*Issuer, *PrivateKey) {
+	t.Helper()
+	sk, err := GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewIssuer(sk, nil), sk
+}
+
+// issue has issuer embed bit in n tokens, passing the request and response
+// through their encodings, and checks that Read recovers bit from each of
+// them without spending it.
+func issue(t *testing.T, issuer *Issuer, n, bit int) []*Token {
+	t.Helper()
+	st, req, err := NewClient(issuer.PublicKey()).NewRequest(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req, err = new(Request).SetBytes(req.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := issuer.Issue(req, bit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err = new(Response).SetBytes(resp.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := st.Finalize(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tok := range tokens {
+		if got, err := issuer.Read(tok); err != nil || got != bit {
+			t.Fatalf("issued token read as bit %d, err %v, want bit %d", got, err, bit)
+		}
+	}
+	return tokens
+}
+
+func TestIssueAndRedeem(t *testing.T) {
+	issuer, _ := newIssuer(t)
+	for _, bit := range []int{0, 1} {
+		for _, n := range []int{1, 4} {
+			for _, tok := range issue(t, issuer, n, bit) {
+				decoded, err := new(Token).SetBytes(tok.Bytes())
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := issuer.Redeem(decoded)
+				if err != nil {
+					t.Fatalf("valid token was rejected: %v", err)
+				}
+				if got != bit {
+					t.Errorf("got bit %d, want %d", got, bit)
+				}
+				if _, err := issuer.Redeem(decoded); err == nil {
+					t.Error("double spend was accepted")
+				}
+			}
+		}
+	}
+}
+
+func TestInvalidTokens(t *testing.T) {
+	issuer, _ := newIssuer(t)
+	tok := issue(t, issuer, 1, 1)[0]
+
+	forged := *tok
+	forged.Nonce[0] ^= 1
+	if _, err := issuer.Read(&forged); err == nil {
+		t.Error("token with a modified nonce was accepted")
+	}
+	forged = *tok
+	forged.Ws = ristretto255.NewIdentityElement().Add(tok.Ws, ristretto255.NewGeneratorElement())
+	if _, err := issuer.Read(&forged); err == nil {
+		t.Error("token with a modified validity tag was accepted")
+	}
+	forged = *tok
+	forged.W = ristretto255.NewIdentityElement().Add(tok.W, ristretto255.NewGeneratorElement())
+	if _, err := issuer.Read(&forged); err == nil {
+		t.Error("token with a modified bit tag was accepted")
+	}
+	other, _ := newIssuer(t)
+	if _, err := other.Read(tok); err == nil {
+		t.Error("token was accepted by another issuer")
+	}
+	if _, err := issuer.Read(nil); err == nil {
+		t.Error("nil token was accepted")
+	}
+	if _, err := issuer.Redeem(&Token{Nonce: tok.Nonce, S: tok.S}); err == nil {
+		t.Error("incomplete token was accepted")
+	}
+	// Failed redemptions must not burn the nonce.
+	if _, err := issuer.Redeem(tok); err != nil {
+		t.Errorf("valid token was rejected: %v", err)
+	}
+}
+
+// TestTaggingIssuer checks that an issuer can't embed more than one bit of
+// information by evaluating with a key other than its two bit keys.
+func TestTaggingIssuer(t *testing.T) {
+	issuer, _ := newIssuer(t)
+	st, req, err := NewClient(issuer.PublicKey()).NewRequest(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := issuer.Issue(req, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := st.Finalize(nil); err == nil {
+		t.Error("nil response was accepted")
+	}
+	incomplete := *resp
+	incomplete.Ws = []*ristretto255.Element{resp.Ws[0], nil, resp.Ws[2]}
+	if _, err := st.Finalize(&incomplete); err == nil {
+		t.Error("response with a missing validity tag was accepted")
+	}
+	incomplete = *resp
+	incomplete.Proof = &Proof{C: resp.Proof.C}
+	if _, err := st.Finalize(&incomplete); err == nil {
+		t.Error("response with an incomplete proof was accepted")
+	}
+
+	// Re-evaluate one token with a different key.
+	_, other := newIssuer(t)
+	S := hashS(req.Blinded[1], resp.Nonces[1][:])
+	resp.W[1] = ristretto255.NewIdentityElement().MultiScalarMult(
+		[]*ristretto255.Scalar{other.x[0], other.y[0]}, []*ristretto255.Element{req.Blinded[1], S})
+	if _, err := st.Finalize(resp); err == nil {
+		t.Error("response with a tagged token was accepted")
+	}
+}
+
+func TestMixedBits(t *testing.T) {
+	// A proof for one bit must not cover a batch in which one token carries
+	// the other bit.
+	issuer, _ := newIssuer(t)
+	st, req, err := NewClient(issuer.PublicKey()).NewRequest(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp0, err := issuer.Issue(req, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp1, err := issuer.Issue(req, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mixed := &Response{
+		Nonces: [][NonceSize]byte{resp0.Nonces[0], resp1.Nonces[1]},
+		W:      []*ristretto255.Element{resp0.W[0], resp1.W[1]},
+		Ws:     []*ristretto255.Element{resp0.Ws[0], resp1.Ws[1]},
+		Proof:  resp0.Proof,
+	}
+	if _, err := st.Finalize(mixed); err == nil {
+		t.Error("mixed-bit response was accepted")
+	}
+}
+
+func TestKeyEncoding(t *testing.T) {
+	issuer, sk := newIssuer(t)
+	sk2, err := NewPrivateKey(sk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sk2.Public().Bytes(), sk.Public().Bytes()) {
+		t.Error("private key did not round-trip")
+	}
+	pk, err := NewPublicKey(sk.Public().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tokens issued by a decoded key must be readable by the original.
+	tok := issue(t, NewIssuer(sk2, nil), 1, 1)[0]
+	if bit, err := issuer.Read(tok); err != nil || bit != 1 {
+		t.Errorf("got bit %d, err %v", bit, err)
+	}
+	if !bytes.Equal(pk.Bytes(), sk.Public().Bytes()) {
+		t.Error("public key did not round-trip")
+	}
+}
+
+func TestEncodingErrors(t *testing.T) {
+	issuer, _ := newIssuer(t)
+	_, req, err := NewClient(issuer.PublicKey()).NewRequest(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := req.Bytes()
+	for _, b := range [][]byte{enc[:len(enc)-1], append(bytes.Clone(enc), 0), {0, 0}} {
+		if _, err := new(Request).SetBytes(b); err == nil {
+			t.Errorf("invalid request encoding %x was accepted", b)
+		}
+	}
+	resp, err := issuer.Issue(req, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc = resp.Bytes()
+	if len(enc) != 2+2*96+ProofSize {
+		t.Errorf("got response size %d", len(enc))
+	}
+	if _, err := new(Response).SetBytes(enc[:len(enc)-1]); err == nil {
+		t.Error("truncated response was accepted")
+	}
+	req.Blinded[0] = ristretto255.NewIdentityElement()
+	if _, err := issuer.Issue(req, 0); err == nil {
+		t.Error("identity blinded element was accepted")
+	}
+	if _, err := issuer.Issue(req, 2); err == nil {
+		t.Error("non-bit metadata was accepted")
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pmbtoken
+
+import (
+	"errors"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+// ProofSize is the size in bytes of an encoded Proof.
+const ProofSize = 8 * 32
+
+var errVerification = errors.New("pmbtoken: proof verification failed")
+
+// Proof shows that a batch of issued tokens was computed with the validity
+// key and with one of the two bit keys of a PublicKey, without revealing
+// which. It is the conjunction of a proof of knowledge of (xs, ys) such that
+//
+//	Pub[s] = xs * G + ys * H   and   Ws = xs * T + ys * S
+//
+// and a CDS94 disjunction over b in {0, 1} of proofs of knowledge of
+// (xb, yb) such that
+//
+//	Pub[b] = xb * G + yb * H   and   W = xb * T + yb * S
+//
+// where (T, S, W, Ws) is a random linear combination of the batch. The two
+// branch challenges sum to the Fiat-Shamir challenge, which is also the
+// challenge of the validity proof.
+type Proof struct {
+	C      [2]*ristretto255.Scalar
+	U, V   [2]*ristretto255.Scalar
+	US, VS *ristretto255.Scalar
+}
+
+// complete reports whether all the fields of p are set, as they are for any
+// Proof returned by Issue or SetBytes.
+func (p *Proof) complete() bool {
+	if p == nil {
+		return false
+	}
+	for _, s := range []*ristretto255.Scalar{p.C[0], p.C[1], p.U[0], p.U[1], p.V[0], p.V[1], p.US, p.VS} {
+		if s == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// statement is the composite statement covered by a Proof.
+type statement struct {
+	pk          *PublicKey
+	T, S, W, Ws *ristretto255.Element
+}
+
+// batchStatement absorbs the public key and the batch into t, and returns
+// the composite statement.
+func batchStatement(t *transcript.Transcript, pk *PublicKey, T, S, W, Ws []*ristretto255.Element) *statement {
+	t.AppendMessage("dom-sep", []byte("pmbtoken issuance"))
+	t.AppendElement("pub0", pk.Pub[0])
+	t.AppendElement("pub1", pk.Pub[1])
+	t.AppendElement("pubs", pk.PubS)
+	t.AppendUint64("n", uint64(len(T)))
+	for i := range T {
+		t.AppendElement("T", T[i])
+		t.AppendElement("S", S[i])
+		t.AppendElement("W", W[i])
+		t.AppendElement("Ws", Ws[i])
+	}
+	e := make([]*ristretto255.Scalar, len(T))
+	for i := range e {
+		e[i] = t.ChallengeScalar("e")
+	}
+	combine := func(P []*ristretto255.Element) *ristretto255.Element {
+		return ristretto255.NewIdentityElement().VarTimeMultiScalarMult(e, P)
+	}
+	return &statement{pk: pk, T: combine(T), S: combine(S), W: combine(W), Ws: combine(Ws)}
+}
+
+// commit returns (k * G + l * H + c * P, k * T + l * S + c * W).
+func (st *statement) commit(k, l, c *ristretto255.Scalar, P, W *ristretto255.Element) (*ristretto255.Element, *ristretto255.Element) {
+	K0 := ristretto255.NewIdentityElement().MultiScalarMult(
+		[]*ristretto255.Scalar{k, l, c}, []*ristretto255.Element{ristretto255.NewGeneratorElement(), generatorH, P})
+	K1 := ristretto255.NewIdentityElement().MultiScalarMult(
+		[]*ristretto255.Scalar{k, l, c}, []*ristretto255.Element{st.T, st.S, W})
+	return K0, K1
+}
+
+// prove returns a Proof for st with the keys of sk and the secret bit.
+//
+// Both branches are computed in the same way, with the simulated challenge
+// masked to zero in the real branch and the witness masked to zero in the
+// simulated one, so that the bit does not affect the sequence of operations.
+func (st *statement) prove(t *transcript.Transcript, sk *PrivateKey, bit int) (*Proof, error) {
+	rs, err := randomScalars(7)
+	if err != nil {
+		return nil, err
+	}
+	ks, ls, cSim, kb := rs[0], rs[1], rs[2], rs[3:]
+
+	var isReal, cPre [2]*ristretto255.Scalar
+	for j := range 2 {
+		isReal[j] = scalar.FromUint64(uint64(1 ^ j ^ bit))
+		isSim := scalar.FromUint64(uint64(j ^ bit))
+		cPre[j] = ristretto255.NewScalar().Multiply(cSim, isSim)
+	}
+
+	zero := ristretto255.NewScalar()
+	Ks0, Ks1 := st.commit(ks, ls, zero, st.pk.PubS, st.Ws)
+	t.AppendElement("Ks0", Ks0)
+	t.AppendElement("Ks1", Ks1)
+	for j := range 2 {
+		K0, K1 := st.commit(kb[2*j], kb[2*j+1], cPre[j], st.pk.Pub[j], st.W)
+		t.AppendElement("K0", K0)
+		t.AppendElement("K1", K1)
+	}
+	c := t.ChallengeScalar("c")
+
+	proof := &Proof{}
+	cReal := ristretto255.NewScalar().Subtract(c, cSim)
+	for j := range 2 {
+		cj := ristretto255.NewScalar().Multiply(cReal, isReal[j])
+		cj.Add(cj, cPre[j])
+		proof.C[j] = cj
+		m := ristretto255.NewScalar().Multiply(cj, isReal[j])
+		proof.U[j] = ristretto255.NewScalar().Multiply(m, sk.x[j])
+		proof.U[j].Subtract(kb[2*j], proof.U[j])
+		proof.V[j] = ristretto255.NewScalar().Multiply(m, sk.y[j])
+		proof.V[j].Subtract(kb[2*j+1], proof.V[j])
+	}
+	proof.US = ristretto255.NewScalar().Multiply(c, sk.xs)
+	proof.US.Subtract(ks, proof.US)
+	proof.VS = ristretto255.NewScalar().Multiply(c, sk.ys)
+	proof.VS.Subtract(ls, proof.VS)
+	return proof, nil
+}
+
+// verify checks proof against st.
+func (st *statement) verify(t *transcript.Transcript, proof *Proof) error {
+	if !proof.complete() {
+		return errVerification
+	}
+	c := ristretto255.NewScalar().Add(proof.C[0], proof.C[1])
+	G := ristretto255.NewGeneratorElement()
+	msm := func(s []*ristretto255.Scalar, P []*ristretto255.Element) *ristretto255.Element {
+		return ristretto255.NewIdentityElement().VarTimeMultiScalarMult(s, P)
+	}
+	t.AppendElement("Ks0", msm([]*ristretto255.Scalar{proof.US, proof.VS, c},
+		[]*ristretto255.Element{G, generatorH, st.pk.PubS}))
+	t.AppendElement("Ks1", msm([]*ristretto255.Scalar{proof.US, proof.VS, c},
+		[]*ristretto255.Element{st.T, st.S, st.Ws}))
+	for j := range 2 {
+		t.AppendElement("K0", msm([]*ristretto255.Scalar{proof.U[j], proof.V[j], proof.C[j]},
+			[]*ristretto255.Element{G, generatorH, st.pk.Pub[j]}))
+		t.AppendElement("K1", msm([]*ristretto255.Scalar{proof.U[j], proof.V[j], proof.C[j]},
+			[]*ristretto255.Element{st.T, st.S, st.W}))
+	}
+	if t.ChallengeScalar("c").Equal(c) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+func (p *Proof) scalars() []*ristretto255.Scalar {
+	return []*ristretto255.Scalar{p.C[0], p.C[1], p.U[0], p.V[0], p.U[1], p.V[1], p.US, p.VS}
+}
+
+// Bytes returns the ProofSize-byte encoding of p.
+func (p *Proof) Bytes() []byte {
+	out := make([]byte, 0, ProofSize)
+	for _, s := range p.scalars() {
+		out = append(out, s.Bytes()...)
+	}
+	return out
+}
+
+// SetBytes sets p to the decoded ProofSize-byte encoding b, and returns p. If
+// b is not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (p *Proof) SetBytes(b []byte) (*Proof, error) {
+	if len(b) != ProofSize {
+		return nil, errors.New("pmbtoken: invalid proof length")
+	}
+	s := make([]*ristretto255.Scalar, 8)
+	for i := range s {
+		var err error
+		s[i], err = ristretto255.NewScalar().SetCanonicalBytes(b[32*i : 32*i+32])
+		if err != nil {
+			return nil, errors.New("pmbtoken: invalid proof encoding")
+		}
+	}
+	p.C = [2]*ristretto255.Scalar{s[0], s[1]}
+	p.U = [2]*ristretto255.Scalar{s[2], s[4]}
+	p.V = [2]*ristretto255.Scalar{s[3], s[5]}
+	p.US, p.VS = s[6], s[7]
+	return p, nil
+}
+
+func randomScalars(n int) ([]*ristretto255.Scalar, error) {
+	out := make([]*ristretto255.Scalar, n)
+	for i := range out {
+		var err error
+		if out[i], err = scalar.Random(nil); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
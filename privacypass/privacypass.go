@@ -0,0 +1,344 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package privacypass implements privately verifiable Privacy Pass tokens
+// backed by the ristretto255-SHA512 VOPRF of RFC 9497.
+//
+// The protocol follows the issuance and redemption flow of RFC 9578, Section
+// 5, with the batched issuance of draft-ietf-privacypass-batched-tokens: a
+// Client sends a TokenRequest with one blinded element per token, the Issuer
+// evaluates them all and returns a TokenResponse with a single DLEQ proof, and
+// the Client unblinds the evaluations into Tokens. Tokens are redeemed with
+// the Issuer, which recomputes the PRF output and records the token nonce in a
+// SpentStore to reject double spending.
+package privacypass
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/dleq"
+	"github.com/gtank/ristretto255/oprf"
+)
+
+const (
+	// TokenType is the Privacy Pass token type for VOPRF(ristretto255,
+	// SHA-512).
+	TokenType uint16 = 0x0005
+	// NonceSize is the size in bytes of a token nonce.
+	NonceSize = 32
+	// KeyIDSize is the size in bytes of a token key ID.
+	KeyIDSize = sha256.Size
+	// TokenSize is the size in bytes of an encoded Token.
+	TokenSize = 2 + NonceSize + sha256.Size + KeyIDSize + oprf.OutputSize
+	// MaxBatchSize is the maximum number of tokens in a single request.
+	MaxBatchSize = 0xffff / 32
+)
+
+var suite = oprf.NewSuite(oprf.ModeVOPRF)
+
+var (
+	errTokenType = errors.New("privacypass: unsupported token type")
+	errKeyID     = errors.New("privacypass: unknown token key")
+	errInvalid   = errors.New("privacypass: invalid token")
+)
+
+// KeyID returns the token key ID of pk, the SHA-256 hash of its encoding.
+func KeyID(pk *oprf.PublicKey) [KeyIDSize]byte {
+	return sha256.Sum256(pk.Bytes())
+}
+
+// Token is a finalized token, presented by a client at redemption.
+type Token struct {
+	Nonce           [NonceSize]byte
+	ChallengeDigest [sha256.Size]byte
+	KeyID           [KeyIDSize]byte
+	Authenticator   []byte
+}
+
+// input returns the PRF input of t, token_type || nonce || challenge_digest
+// || token_key_id.
+func (t *Token) input() []byte {
+	out := binary.BigEndian.AppendUint16(nil, TokenType)
+	out = append(out, t.Nonce[:]...)
+	out = append(out, t.ChallengeDigest[:]...)
+	return append(out, t.KeyID[:]...)
+}
+
+// TokenRequest is a batch of blinded token inputs sent by a Client to an
+// Issuer.
+type TokenRequest struct {
+	// TruncatedKeyID is the last byte of the token key ID of the Issuer.
+	TruncatedKeyID byte
+	Blinded        []*ristretto255.Element
+}
+
+// TokenResponse is the Issuer evaluation of a TokenRequest, with one proof
+// for the whole batch.
+type TokenResponse struct {
+	Evaluated []*ristretto255.Element
+	Proof     *dleq.Proof
+}
+
+// Client requests tokens from a single Issuer.
+type Client struct {
+	pk    *oprf.PublicKey
+	keyID [KeyIDSize]byte
+}
+
+// NewClient returns a Client for the Issuer with public key pk.
+func NewClient(pk *oprf.PublicKey) *Client {
+	return &Client{pk: pk, keyID: KeyID(pk)}
+}
+
+// ClientState holds the secret state of a pending TokenRequest.
+type ClientState struct {
+	c       *Client
+	tokens  []*Token
+	inputs  [][]byte
+	blinds  []*ristretto255.Scalar
+	blinded []*ristretto255.Element
+}
+
+// NewRequest returns a TokenRequest for n tokens bound to challenge, the
+// encoded TokenChallenge of the origin, and the state needed to finalize the
+// Issuer response.
+func (c *Client) NewRequest(challenge []byte, n int) (*ClientState, *TokenRequest, error) {
+	if n <= 0 || n > MaxBatchSize {
+		return nil, nil, errors.New("privacypass: invalid batch size")
+	}
+	digest := sha256.Sum256(challenge)
+	st := &ClientState{c: c}
+	for range n {
+		t := &Token{ChallengeDigest: digest, KeyID: c.keyID}
+		if _, err := io.ReadFull(rand.Reader, t.Nonce[:]); err != nil {
+			return nil, nil, err
+		}
+		input := t.input()
+		blind, blinded, err := suite.Blind(input)
+		if err != nil {
+			return nil, nil, err
+		}
+		st.tokens = append(st.tokens, t)
+		st.inputs = append(st.inputs, input)
+		st.blinds = append(st.blinds, blind)
+		st.blinded = append(st.blinded, blinded)
+	}
+	req := &TokenRequest{
+		TruncatedKeyID: c.keyID[KeyIDSize-1],
+		Blinded:        append([]*ristretto255.Element(nil), st.blinded...),
+	}
+	return st, req, nil
+}
+
+// Finalize verifies the Issuer proof in resp and returns the issued Tokens,
+// in the same order as the request.
+func (st *ClientState) Finalize(resp *TokenResponse) ([]*Token, error) {
+	if resp == nil || resp.Proof == nil {
+		return nil, errors.New("privacypass: incomplete token response")
+	}
+	if len(resp.Evaluated) != len(st.tokens) {
+		return nil, errors.New("privacypass: mismatched response length")
+	}
+	outputs, err := suite.Finalize(st.c.pk, st.inputs, st.blinds, st.blinded, resp.Evaluated, resp.Proof, nil)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]*Token, len(st.tokens))
+	for i, t := range st.tokens {
+		tokens[i] = &Token{
+			Nonce:           t.Nonce,
+			ChallengeDigest: t.ChallengeDigest,
+			KeyID:           t.KeyID,
+			Authenticator:   outputs[i],
+		}
+	}
+	return tokens, nil
+}
+
+// Issuer issues and redeems tokens under a single key.
+type Issuer struct {
+	sk    *oprf.PrivateKey
+	keyID [KeyIDSize]byte
+	spent SpentStore
+}
+
+// NewIssuer returns an Issuer for sk. Redeemed nonces are recorded in spent,
+// which should be shared by all the Issuers using sk. If spent is nil, a new
+// MemoryStore is used.
+func NewIssuer(sk *oprf.PrivateKey, spent SpentStore) *Issuer {
+	if spent == nil {
+		spent = NewMemoryStore()
+	}
+	return &Issuer{sk: sk, keyID: KeyID(sk.Public()), spent: spent}
+}
+
+// PublicKey returns the public key of the Issuer, to be distributed to
+// Clients.
+func (i *Issuer) PublicKey() *oprf.PublicKey {
+	return i.sk.Public()
+}
+
+// Issue evaluates the blinded elements of req.
+func (i *Issuer) Issue(req *TokenRequest) (*TokenResponse, error) {
+	if req.TruncatedKeyID != i.keyID[KeyIDSize-1] {
+		return nil, errKeyID
+	}
+	if len(req.Blinded) == 0 || len(req.Blinded) > MaxBatchSize {
+		return nil, errors.New("privacypass: invalid batch size")
+	}
+	evaluated, proof, err := suite.BlindEvaluate(i.sk, req.Blinded, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenResponse{Evaluated: evaluated, Proof: proof}, nil
+}
+
+// Verify checks that t was issued by i for challenge, the encoded
+// TokenChallenge of the origin. It does not check or record the nonce; use
+// Redeem for that.
+func (i *Issuer) Verify(t *Token, challenge []byte) error {
+	if t.KeyID != i.keyID {
+		return errKeyID
+	}
+	digest := sha256.Sum256(challenge)
+	if t.ChallengeDigest != digest {
+		return errInvalid
+	}
+	want, err := suite.Evaluate(i.sk, t.input(), nil)
+	if err != nil {
+		return errInvalid
+	}
+	if subtle.ConstantTimeCompare(want, t.Authenticator) != 1 {
+		return errInvalid
+	}
+	return nil
+}
+
+// Redeem verifies t like Verify and then records its nonce as spent. It
+// returns an error if the token is invalid or was already redeemed.
+func (i *Issuer) Redeem(t *Token, challenge []byte) error {
+	if err := i.Verify(t, challenge); err != nil {
+		return err
+	}
+	if !i.spent.MarkSpent(t.Nonce) {
+		return errors.New("privacypass: token already redeemed")
+	}
+	return nil
+}
+
+// Bytes returns the encoding of t, token_type || nonce || challenge_digest
+// || token_key_id || authenticator.
+func (t *Token) Bytes() []byte {
+	out := make([]byte, 0, TokenSize)
+	out = append(out, t.input()...)
+	return append(out, t.Authenticator...)
+}
+
+// SetBytes sets t to the decoded TokenSize-byte encoding b, and returns t. If
+// b is not a valid encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (t *Token) SetBytes(b []byte) (*Token, error) {
+	if len(b) != TokenSize {
+		return nil, errors.New("privacypass: invalid token length")
+	}
+	if binary.BigEndian.Uint16(b) != TokenType {
+		return nil, errTokenType
+	}
+	b = b[2:]
+	copy(t.Nonce[:], b[:NonceSize])
+	b = b[NonceSize:]
+	copy(t.ChallengeDigest[:], b[:sha256.Size])
+	b = b[sha256.Size:]
+	copy(t.KeyID[:], b[:KeyIDSize])
+	t.Authenticator = append([]byte(nil), b[KeyIDSize:]...)
+	return t, nil
+}
+
+// Bytes returns the encoding of req, token_type || truncated_token_key_id ||
+// blinded_elements, where blinded_elements is prefixed by its length in
+// bytes as a 16-bit big-endian integer.
+func (req *TokenRequest) Bytes() []byte {
+	out := binary.BigEndian.AppendUint16(nil, TokenType)
+	out = append(out, req.TruncatedKeyID)
+	return appendElements(out, req.Blinded)
+}
+
+// SetBytes sets req to the decoded encoding b, and returns req. If b is not
+// a valid encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (req *TokenRequest) SetBytes(b []byte) (*TokenRequest, error) {
+	if len(b) < 3 {
+		return nil, errors.New("privacypass: invalid token request length")
+	}
+	if binary.BigEndian.Uint16(b) != TokenType {
+		return nil, errTokenType
+	}
+	blinded, rest, err := readElements(b[3:])
+	if err != nil || len(rest) != 0 {
+		return nil, errors.New("privacypass: invalid token request encoding")
+	}
+	req.TruncatedKeyID, req.Blinded = b[2], blinded
+	return req, nil
+}
+
+// Bytes returns the encoding of resp, evaluated_elements || proof, where
+// evaluated_elements is prefixed by its length in bytes as a 16-bit
+// big-endian integer.
+//
+// resp must be a TokenResponse returned by Issue or SetBytes. Bytes panics if
+// its Proof or any of its Evaluated elements are unset.
+func (resp *TokenResponse) Bytes() []byte {
+	out := appendElements(nil, resp.Evaluated)
+	return append(out, resp.Proof.Bytes()...)
+}
+
+// SetBytes sets resp to the decoded encoding b, and returns resp. If b is
+// not a valid encoding, SetBytes returns nil and an error, and the receiver
+// is unchanged.
+func (resp *TokenResponse) SetBytes(b []byte) (*TokenResponse, error) {
+	evaluated, rest, err := readElements(b)
+	if err != nil {
+		return nil, errors.New("privacypass: invalid token response encoding")
+	}
+	proof, err := new(dleq.Proof).SetBytes(rest)
+	if err != nil {
+		return nil, errors.New("privacypass: invalid token response encoding")
+	}
+	resp.Evaluated, resp.Proof = evaluated, proof
+	return resp, nil
+}
+
+func appendElements(out []byte, elements []*ristretto255.Element) []byte {
+	out = binary.BigEndian.AppendUint16(out, uint16(32*len(elements)))
+	for _, e := range elements {
+		out = append(out, e.Bytes()...)
+	}
+	return out
+}
+
+func readElements(b []byte) ([]*ristretto255.Element, []byte, error) {
+	if len(b) < 2 {
+		return nil, nil, errInvalid
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	b = b[2:]
+	if n == 0 || n%32 != 0 || len(b) < n {
+		return nil, nil, errInvalid
+	}
+	elements := make([]*ristretto255.Element, n/32)
+	for i := range elements {
+		e, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[32*i : 32*i+32])
+		if err != nil {
+			return nil, nil, err
+		}
+		elements[i] = e
+	}
+	return elements, b[n:], nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package privacypass
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/oprf"
+)
+
+func newIssuer(t *testing.T) *Issuer {
+	t.Helper()
+	sk, err := oprf.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewIssuer(sk, nil)
+}
+
+// issue runs the issuance protocol through the wire encodings.
+func issue(t *testing.T, issuer *Issuer, challenge []byte, n int) []*Token {
+	t.Helper()
+	client := NewClient(issuer.PublicKey())
+	st, req, err := client.NewRequest(challenge, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err = new(TokenRequest).SetBytes(req.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := issuer.Issue(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = new(TokenResponse).SetBytes(resp.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := st.Finalize(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+func TestIssueAndRedeem(t *testing.T) {
+	issuer := newIssuer(t)
+	challenge := []byte("origin challenge")
+	tokens := issue(t, issuer, challenge, 5)
+	if len(tokens) != 5 {
+		t.Fatalf("got %d tokens, want 5", len(tokens))
+	}
+
+	for _, tok := range tokens {
+		enc := tok.Bytes()
+		if len(enc) != TokenSize {
+			t.Fatalf("got token size %d, want %d", len(enc), TokenSize)
+		}
+		decoded, err := new(Token).SetBytes(enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := issuer.Verify(decoded, []byte("another challenge")); err == nil {
+			t.Error("token verified for the wrong challenge")
+		}
+		if err := issuer.Redeem(decoded, challenge); err != nil {
+			t.Fatalf("valid token was rejected: %v", err)
+		}
+		if err := issuer.Redeem(decoded, challenge); err == nil {
+			t.Error("double spend was accepted")
+		}
+	}
+	if n := issuer.spent.(*MemoryStore).Len(); n != 5 {
+		t.Errorf("got %d spent nonces, want 5", n)
+	}
+}
+
+func TestForgedToken(t *testing.T) {
+	issuer := newIssuer(t)
+	challenge := []byte("origin challenge")
+	tok := issue(t, issuer, challenge, 1)[0]
+
+	forged := *tok
+	forged.Nonce[0] ^= 1
+	if err := issuer.Redeem(&forged, challenge); err == nil {
+		t.Error("token with a modified nonce was accepted")
+	}
+	forged = *tok
+	forged.Authenticator = bytes.Clone(tok.Authenticator)
+	forged.Authenticator[0] ^= 1
+	if err := issuer.Redeem(&forged, challenge); err == nil {
+		t.Error("token with a modified authenticator was accepted")
+	}
+	// Failed redemptions must not burn the nonce.
+	if err := issuer.Redeem(tok, challenge); err != nil {
+		t.Errorf("valid token was rejected: %v", err)
+	}
+
+	if err := newIssuer(t).Verify(tok, challenge); err == nil {
+		t.Error("token verified under another issuer key")
+	}
+}
+
+func TestWrongIssuerKey(t *testing.T) {
+	issuer, other := newIssuer(t), newIssuer(t)
+	client := NewClient(other.PublicKey())
+	st, req, err := client.NewRequest(nil, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Force the truncated key ID to match, so that the proof is what fails.
+	req.TruncatedKeyID = issuer.keyID[KeyIDSize-1]
+	resp, err := issuer.Issue(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Finalize(resp); err == nil {
+		t.Error("response under the wrong key was accepted")
+	}
+
+	resp.Evaluated[0], resp.Evaluated[1] = resp.Evaluated[1], resp.Evaluated[0]
+	if _, err := st.Finalize(resp); err == nil {
+		t.Error("response with swapped evaluations was accepted")
+	}
+	if _, err := st.Finalize(nil); err == nil {
+		t.Error("nil response was accepted")
+	}
+	if _, err := st.Finalize(&TokenResponse{Evaluated: resp.Evaluated}); err == nil {
+		t.Error("response without a proof was accepted")
+	}
+}
+
+func TestConcurrentRedeem(t *testing.T) {
+	issuer := newIssuer(t)
+	tok := issue(t, issuer, nil, 1)[0]
+
+	var wg sync.WaitGroup
+	var ok atomic.Int32
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if issuer.Redeem(tok, nil) == nil {
+				ok.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if ok.Load() != 1 {
+		t.Errorf("token was redeemed %d times", ok.Load())
+	}
+}
+
+func TestEncodingErrors(t *testing.T) {
+	issuer := newIssuer(t)
+	client := NewClient(issuer.PublicKey())
+	_, req, err := client.NewRequest(nil, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := req.Bytes()
+	if len(enc) != 3+2+32*3 {
+		t.Errorf("got request size %d", len(enc))
+	}
+	for _, b := range [][]byte{
+		enc[:len(enc)-1],
+		append(bytes.Clone(enc), 0),
+		append([]byte{0, 1}, enc[2:]...),
+	} {
+		if _, err := new(TokenRequest).SetBytes(b); err == nil {
+			t.Errorf("invalid request encoding %x was accepted", b)
+		}
+	}
+
+	req.Blinded[1] = ristretto255.NewIdentityElement()
+	if _, err := issuer.Issue(req); err == nil {
+		t.Error("identity blinded element was accepted")
+	}
+
+	tok := issue(t, issuer, nil, 1)[0].Bytes()
+	tok[1] = 1
+	if _, err := new(Token).SetBytes(tok); err == nil {
+		t.Error("token with the wrong type was accepted")
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package privacypass
+
+import "sync"
+
+// SpentStore records the nonces of redeemed tokens.
+//
+// Implementations must be safe for concurrent use, and MarkSpent must be
+// atomic, so that two concurrent redemptions of the same token can't both
+// succeed. A store only needs to remember nonces for as long as the
+// corresponding Issuer key is accepted.
+type SpentStore interface {
+	// MarkSpent records nonce and reports whether it was not already
+	// recorded.
+	MarkSpent(nonce [NonceSize]byte) bool
+}
+
+// MemoryStore is an in-memory SpentStore. It grows with every redeemed
+// token, so it is suited to keys that are rotated regularly.
+type MemoryStore struct {
+	mu     sync.Mutex
+	nonces map[[NonceSize]byte]struct{}
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{nonces: make(map[[NonceSize]byte]struct{})}
+}
+
+// MarkSpent implements SpentStore.
+func (s *MemoryStore) MarkSpent(nonce [NonceSize]byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.nonces[nonce]; ok {
+		return false
+	}
+	s.nonces[nonce] = struct{}{}
+	return true
+}
+
+// Len returns the number of recorded nonces.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.nonces)
+}
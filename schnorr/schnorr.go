@@ -0,0 +1,165 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package schnorr implements the Schnorr zero-knowledge proof of knowledge of
+// a discrete logarithm from RFC 8235, instantiated over ristretto255.
+//
+// A Proof shows knowledge of the secret a such that A = a * G, where G is the
+// canonical generator. It is suitable as a proof of possession when
+// registering public keys, to prevent rogue-key attacks.
+//
+// The challenge of the non-interactive variant is computed as
+//
+//	c = SHA-512(DST || G || V || A || len(UserID) || UserID || len(OtherInfo) || OtherInfo)
+//
+// reduced modulo l, where the lengths are 8-byte big-endian integers, and
+// UserID and OtherInfo bind the proof to its context as recommended by
+// RFC 8235, Section 3.3.
+package schnorr
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// ProofSize is the size in bytes of an encoded Proof.
+const ProofSize = 64
+
+const dst = "ristretto255-SHA512-RFC8235-Schnorr-NIZK"
+
+var (
+	errVerification = errors.New("schnorr: proof verification failed")
+	errInvalidKey   = errors.New("schnorr: invalid public key")
+)
+
+// Proof is a non-interactive Schnorr proof of knowledge.
+type Proof struct {
+	// V is the commitment to the prover's nonce.
+	V *ristretto255.Element
+	// R is the response r = v - a * c mod l.
+	R *ristretto255.Scalar
+}
+
+// Prove returns a proof of knowledge of a, the discrete logarithm of
+// A = a * G, bound to userID and otherInfo.
+func Prove(a *ristretto255.Scalar, userID, otherInfo []byte) (*Proof, error) {
+	prover, V, err := NewProver(a)
+	if err != nil {
+		return nil, err
+	}
+	A := ristretto255.NewIdentityElement().ScalarBaseMult(a)
+	c := Challenge(V, A, userID, otherInfo)
+	return &Proof{V: V, R: prover.Respond(c)}, nil
+}
+
+// Verify checks that proof is a valid proof of knowledge of the discrete
+// logarithm of A, bound to userID and otherInfo.
+func Verify(A *ristretto255.Element, userID, otherInfo []byte, proof *Proof) error {
+	if proof == nil || proof.V == nil || proof.R == nil {
+		return errVerification
+	}
+	c := Challenge(proof.V, A, userID, otherInfo)
+	return VerifyInteractive(A, proof.V, c, proof.R)
+}
+
+// Challenge returns the Fiat-Shamir challenge for the commitment V and the
+// public key A, as defined in RFC 8235, Section 3.3.
+func Challenge(V, A *ristretto255.Element, userID, otherInfo []byte) *ristretto255.Scalar {
+	h := sha512.New()
+	h.Write([]byte(dst))
+	h.Write(ristretto255.NewGeneratorElement().Bytes())
+	h.Write(V.Bytes())
+	h.Write(A.Bytes())
+	writeLengthPrefixed(h, userID)
+	writeLengthPrefixed(h, otherInfo)
+	c, _ := ristretto255.NewScalar().SetUniformBytes(h.Sum(nil))
+	return c
+}
+
+func writeLengthPrefixed(h interface{ Write([]byte) (int, error) }, b []byte) {
+	var l [8]byte
+	binary.BigEndian.PutUint64(l[:], uint64(len(b)))
+	h.Write(l[:])
+	h.Write(b)
+}
+
+// Prover is the prover of the interactive Schnorr identification protocol
+// of RFC 8235, Section 2.
+type Prover struct {
+	a, v *ristretto255.Scalar
+}
+
+// NewProver returns a Prover for the secret a and its commitment
+// V = v * G, which must be sent to the verifier.
+func NewProver(a *ristretto255.Scalar) (*Prover, *ristretto255.Element, error) {
+	v, err := scalar.Random(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	V := ristretto255.NewIdentityElement().ScalarBaseMult(v)
+	return &Prover{a: ristretto255.NewScalar().Set(a), v: v}, V, nil
+}
+
+// Respond returns the response r = v - a * c mod l to the verifier's
+// challenge c. A Prover must be used to respond to a single challenge:
+// responding to two different challenges reveals the secret.
+func (p *Prover) Respond(c *ristretto255.Scalar) *ristretto255.Scalar {
+	if p.v == nil {
+		panic("schnorr: Prover used more than once")
+	}
+	r := ristretto255.NewScalar().Multiply(p.a, c)
+	r.Subtract(p.v, r)
+	p.v = nil
+	return r
+}
+
+// NewChallenge returns a random challenge for the interactive protocol.
+func NewChallenge() (*ristretto255.Scalar, error) {
+	return scalar.Random(nil)
+}
+
+// VerifyInteractive checks the transcript (V, c, r) of the interactive
+// protocol for the public key A, that is, that A is not the identity and
+// V = r * G + c * A.
+func VerifyInteractive(A, V *ristretto255.Element, c, r *ristretto255.Scalar) error {
+	identity := ristretto255.NewIdentityElement()
+	if A.Equal(identity) == 1 {
+		return errInvalidKey
+	}
+	check := ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(c, A, r)
+	if check.Equal(V) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+// Bytes returns the 64-byte encoding of the proof, V || r.
+func (p *Proof) Bytes() []byte {
+	out := make([]byte, 0, ProofSize)
+	out = append(out, p.V.Bytes()...)
+	return append(out, p.R.Bytes()...)
+}
+
+// SetBytes sets p to the decoded 64-byte encoding b, and returns p. If b is
+// not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (p *Proof) SetBytes(b []byte) (*Proof, error) {
+	if len(b) != ProofSize {
+		return nil, errors.New("schnorr: invalid proof length")
+	}
+	V, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[:32])
+	if err != nil {
+		return nil, errors.New("schnorr: invalid proof encoding")
+	}
+	r, err := ristretto255.NewScalar().SetCanonicalBytes(b[32:])
+	if err != nil {
+		return nil, errors.New("schnorr: invalid proof encoding")
+	}
+	p.V, p.R = V, r
+	return p, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+func TestNonInteractive(t *testing.T) {
+	a := scalar.MustRandom()
+	A := ristretto255.NewIdentityElement().ScalarBaseMult(a)
+	userID, otherInfo := []byte("alice"), []byte("key registration 2026")
+
+	proof, err := Prove(a, userID, otherInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(A, userID, otherInfo, proof); err != nil {
+		t.Errorf("valid proof failed to verify: %v", err)
+	}
+
+	encoded := proof.Bytes()
+	if len(encoded) != ProofSize {
+		t.Fatalf("unexpected proof size %d", len(encoded))
+	}
+	decoded, err := new(Proof).SetBytes(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(A, userID, otherInfo, decoded); err != nil {
+		t.Errorf("decoded proof failed to verify: %v", err)
+	}
+
+	if err := Verify(A, []byte("mallory"), otherInfo, proof); err == nil {
+		t.Error("proof verified for a different UserID")
+	}
+	if err := Verify(A, userID, []byte("other context"), proof); err == nil {
+		t.Error("proof verified for a different OtherInfo")
+	}
+	// The length prefixes prevent shifting bytes between the two fields.
+	if err := Verify(A, []byte("alic"), []byte("ekey registration 2026"), proof); err == nil {
+		t.Error("proof verified with shifted context")
+	}
+	B := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.MustRandom())
+	if err := Verify(B, userID, otherInfo, proof); err == nil {
+		t.Error("proof verified for a different public key")
+	}
+	if err := Verify(A, userID, otherInfo, &Proof{}); err == nil {
+		t.Error("zero proof verified")
+	}
+	if err := Verify(A, userID, otherInfo, &Proof{V: proof.V}); err == nil {
+		t.Error("proof without a response verified")
+	}
+}
+
+func TestIdentityKeyRejected(t *testing.T) {
+	a := ristretto255.NewScalar()
+	proof, err := Prove(a, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(ristretto255.NewIdentityElement(), nil, nil, proof); err == nil {
+		t.Error("proof for the identity verified")
+	}
+}
+
+func TestInteractive(t *testing.T) {
+	a := scalar.MustRandom()
+	A := ristretto255.NewIdentityElement().ScalarBaseMult(a)
+
+	prover, V, err := NewProver(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewChallenge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := prover.Respond(c)
+	if err := VerifyInteractive(A, V, c, r); err != nil {
+		t.Errorf("valid transcript failed to verify: %v", err)
+	}
+	if err := VerifyInteractive(A, V, scalar.MustRandom(), r); err == nil {
+		t.Error("transcript verified with a different challenge")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Prover responded twice")
+		}
+	}()
+	prover.Respond(c)
+}
+
+func TestSetBytesErrors(t *testing.T) {
+	if _, err := new(Proof).SetBytes(make([]byte, 63)); err == nil {
+		t.Error("accepted a short proof")
+	}
+	b := make([]byte, 64)
+	for i := range b[32:] {
+		b[32+i] = 0xff
+	}
+	if _, err := new(Proof).SetBytes(b); err == nil {
+		t.Error("accepted a non-canonical scalar")
+	}
+}
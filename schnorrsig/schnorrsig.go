@@ -0,0 +1,127 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package schnorrsig implements plain key-prefixed Schnorr signatures over
+// ristretto255.
+//
+// A Signature (R, s) by the public key P = x * G is valid for msg if
+//
+//	s * G = R + c * P
+//
+// for the challenge c = Challenge(R, P, msg). This is the form of signature
+// produced by the multi-party signing protocol in the musig2 package, whose
+// aggregate signatures can be verified with Verify like any other.
+//
+// For proofs of knowledge of a discrete logarithm bound to a context, such
+// as proofs of possession, see the schnorr package instead.
+package schnorrsig
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+
+	"github.com/gtank/ristretto255"
+)
+
+// SignatureSize is the size in bytes of an encoded Signature.
+const SignatureSize = 64
+
+const (
+	signatureDST = "ristretto255-SHA512-Schnorr-Signature-v1"
+	nonceDST     = "ristretto255-SHA512-Schnorr-Signature-v1-nonce"
+)
+
+var (
+	errInvalidKey   = errors.New("schnorrsig: invalid public key")
+	errVerification = errors.New("schnorrsig: signature verification failed")
+)
+
+// Signature is a Schnorr signature (R, s).
+type Signature struct {
+	R *ristretto255.Element
+	S *ristretto255.Scalar
+}
+
+// Challenge returns the challenge
+//
+//	c = SHA-512(DST || R || P || msg)
+//
+// reduced modulo l, where DST is "ristretto255-SHA512-Schnorr-Signature-v1".
+func Challenge(R, P *ristretto255.Element, msg []byte) *ristretto255.Scalar {
+	h := sha512.New()
+	h.Write([]byte(signatureDST))
+	h.Write(R.Bytes())
+	h.Write(P.Bytes())
+	h.Write(msg)
+	c, _ := ristretto255.NewScalar().SetUniformBytes(h.Sum(nil))
+	return c
+}
+
+// Sign returns a signature on msg by the secret key x.
+//
+// The nonce is derived from x, msg and 32 random bytes, so that a failure of
+// the system random number generator doesn't reveal the key.
+func Sign(x *ristretto255.Scalar, msg []byte) (*Signature, error) {
+	var z [32]byte
+	if _, err := rand.Read(z[:]); err != nil {
+		return nil, err
+	}
+	h := sha512.New()
+	h.Write([]byte(nonceDST))
+	h.Write(x.Bytes())
+	h.Write(z[:])
+	h.Write(msg)
+	k, _ := ristretto255.NewScalar().SetUniformBytes(h.Sum(nil))
+
+	P := ristretto255.NewIdentityElement().ScalarBaseMult(x)
+	R := ristretto255.NewIdentityElement().ScalarBaseMult(k)
+	c := Challenge(R, P, msg)
+	s := ristretto255.NewScalar().Multiply(c, x)
+	return &Signature{R: R, S: s.Add(s, k)}, nil
+}
+
+// Verify returns nil if sig is a valid signature on msg by the public key P,
+// and an error otherwise. P must not be the identity.
+func Verify(P *ristretto255.Element, msg []byte, sig *Signature) error {
+	if P.Equal(ristretto255.NewIdentityElement()) == 1 {
+		return errInvalidKey
+	}
+	if sig == nil || sig.R == nil || sig.S == nil {
+		return errVerification
+	}
+	c := Challenge(sig.R, P, msg)
+	c.Negate(c)
+	check := ristretto255.NewIdentityElement().VarTimeDoubleScalarBaseMult(c, P, sig.S)
+	if check.Equal(sig.R) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+// Bytes returns the 64-byte encoding of the signature, R || s.
+func (sig *Signature) Bytes() []byte {
+	out := make([]byte, 0, SignatureSize)
+	out = append(out, sig.R.Bytes()...)
+	return append(out, sig.S.Bytes()...)
+}
+
+// SetBytes sets sig to the decoded 64-byte encoding b, and returns sig. If b
+// is not a canonical encoding, SetBytes returns nil and an error, and the
+// receiver is unchanged.
+func (sig *Signature) SetBytes(b []byte) (*Signature, error) {
+	if len(b) != SignatureSize {
+		return nil, errors.New("schnorrsig: invalid signature length")
+	}
+	R, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[:32])
+	if err != nil {
+		return nil, errors.New("schnorrsig: invalid signature encoding")
+	}
+	s, err := ristretto255.NewScalar().SetCanonicalBytes(b[32:])
+	if err != nil {
+		return nil, errors.New("schnorrsig: invalid signature encoding")
+	}
+	sig.R, sig.S = R, s
+	return sig, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schnorrsig
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+func TestSignature(t *testing.T) {
+	x := scalar.MustRandom()
+	P := ristretto255.NewIdentityElement().ScalarBaseMult(x)
+	msg := []byte("hello")
+
+	sig, err := Sign(x, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(P, msg, sig); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(P, []byte("hellO"), sig); err == nil {
+		t.Error("signature verified for another message")
+	}
+	other := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.MustRandom())
+	if err := Verify(other, msg, sig); err == nil {
+		t.Error("signature verified for another key")
+	}
+	if err := Verify(ristretto255.NewIdentityElement(), msg, sig); err == nil {
+		t.Error("signature verified for the identity key")
+	}
+	if err := Verify(P, msg, &Signature{}); err == nil {
+		t.Error("zero signature verified")
+	}
+
+	sig2, _ := Sign(x, msg)
+	if sig2.R.Equal(sig.R) == 1 {
+		t.Error("nonces are not randomized")
+	}
+
+	decoded, err := new(Signature).SetBytes(sig.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(P, msg, decoded); err != nil {
+		t.Error(err)
+	}
+	b := sig.Bytes()
+	for i := range b[32:] {
+		b[32+i] = 0xff
+	}
+	if _, err := new(Signature).SetBytes(b); err == nil {
+		t.Error("accepted a non-canonical scalar")
+	}
+}
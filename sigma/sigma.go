@@ -0,0 +1,356 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sigma implements non-interactive zero-knowledge proofs of
+// knowledge for linear relations over ristretto255, using Maurer's unified
+// sigma protocol compiled with Fiat-Shamir.
+//
+// A Relation is declared as a set of equations
+//
+//	P_j = x_a * G_b + x_c * G_d + ...
+//
+// where the x are secret Scalars and the P and G are public Elements. From a
+// Relation, the package derives the prover and verifier, in two flavors:
+// compact proofs, made of the challenge and the responses, and batchable
+// proofs, made of the commitments and the responses, which can be verified
+// together with a single VarTimeMultiScalarMult.
+package sigma
+
+import (
+	"crypto/rand"
+	"errors"
+	"slices"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+var (
+	errVerification = errors.New("sigma: proof verification failed")
+	errFormat       = errors.New("sigma: malformed proof encoding")
+)
+
+// ScalarVar identifies a secret Scalar in a Relation.
+type ScalarVar int
+
+// PointVar identifies a public Element in a Relation.
+type PointVar int
+
+// Term is the product of a secret Scalar and a public Element.
+type Term struct {
+	Scalar ScalarVar
+	Point  PointVar
+}
+
+type equation struct {
+	lhs PointVar
+	rhs []Term
+}
+
+// Relation is a statement about secret Scalars, made of linear equations
+// over public Elements. A Relation must be declared identically by the
+// prover and the verifier.
+type Relation struct {
+	label       string
+	scalarNames []string
+	pointNames  []string
+	equations   []equation
+}
+
+// NewRelation returns an empty Relation. The label is absorbed into the
+// transcript of every proof, and should uniquely identify the statement.
+func NewRelation(label string) *Relation {
+	return &Relation{label: label}
+}
+
+// SecretScalar declares a new secret Scalar.
+func (r *Relation) SecretScalar(name string) ScalarVar {
+	r.scalarNames = append(r.scalarNames, name)
+	return ScalarVar(len(r.scalarNames) - 1)
+}
+
+// Point declares a new public Element.
+func (r *Relation) Point(name string) PointVar {
+	r.pointNames = append(r.pointNames, name)
+	return PointVar(len(r.pointNames) - 1)
+}
+
+// Constrain adds the equation lhs = sum(rhs[i].Scalar * rhs[i].Point).
+func (r *Relation) Constrain(lhs PointVar, rhs ...Term) {
+	r.equations = append(r.equations, equation{lhs: lhs, rhs: append([]Term(nil), rhs...)})
+}
+
+// NumScalars returns the number of secret Scalars declared in r.
+func (r *Relation) NumScalars() int { return len(r.scalarNames) }
+
+// NumPoints returns the number of public Elements declared in r.
+func (r *Relation) NumPoints() int { return len(r.pointNames) }
+
+// NumEquations returns the number of equations in r.
+func (r *Relation) NumEquations() int { return len(r.equations) }
+
+// CompactProof is a proof made of the challenge and the responses. It is
+// the shortest encoding, but can't be batch verified.
+type CompactProof struct {
+	Challenge *ristretto255.Scalar
+	Responses []*ristretto255.Scalar
+}
+
+// BatchableProof is a proof made of the commitments and the responses.
+type BatchableProof struct {
+	Commitments []*ristretto255.Element
+	Responses   []*ristretto255.Scalar
+}
+
+func (r *Relation) checkPoints(points []*ristretto255.Element) error {
+	if len(points) != len(r.pointNames) {
+		return errors.New("sigma: wrong number of public Elements")
+	}
+	for _, p := range points {
+		if p == nil {
+			return errors.New("sigma: missing public Element")
+		}
+	}
+	return nil
+}
+
+func (r *Relation) absorbStatement(t *transcript.Transcript, points []*ristretto255.Element) {
+	t.AppendMessage("dom-sep", []byte("sigma v1"))
+	t.AppendMessage("relation", []byte(r.label))
+	for i, p := range points {
+		t.AppendMessage("point-name", []byte(r.pointNames[i]))
+		t.AppendElement("point", p)
+	}
+}
+
+// commitments returns, for each equation, sum(s * G) + c * P. The nonce
+// commitments are obtained for s the nonces and c zero, and the verifier's
+// recomputed commitments for s the responses and c the challenge.
+func (r *Relation) commitments(points []*ristretto255.Element, s []*ristretto255.Scalar, c *ristretto255.Scalar) []*ristretto255.Element {
+	out := make([]*ristretto255.Element, len(r.equations))
+	for j, eq := range r.equations {
+		scalars := make([]*ristretto255.Scalar, 0, len(eq.rhs)+1)
+		elements := make([]*ristretto255.Element, 0, len(eq.rhs)+1)
+		for _, tm := range eq.rhs {
+			scalars = append(scalars, s[tm.Scalar])
+			elements = append(elements, points[tm.Point])
+		}
+		if c != nil {
+			scalars = append(scalars, c)
+			elements = append(elements, points[eq.lhs])
+			out[j] = ristretto255.NewIdentityElement().VarTimeMultiScalarMult(scalars, elements)
+		} else {
+			out[j] = ristretto255.NewIdentityElement().MultiScalarMult(scalars, elements)
+		}
+	}
+	return out
+}
+
+// prove runs the prover up to the responses. The nonces are derived from
+// the transcript, the witness, and fresh randomness, so that a broken
+// random number generator does not leak the witness.
+func (r *Relation) prove(t *transcript.Transcript, points []*ristretto255.Element, witness []*ristretto255.Scalar) ([]*ristretto255.Element, *ristretto255.Scalar, []*ristretto255.Scalar, error) {
+	if err := r.checkPoints(points); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(witness) != len(r.scalarNames) {
+		return nil, nil, nil, errors.New("sigma: wrong number of secret Scalars")
+	}
+	r.absorbStatement(t, points)
+
+	rng := t.Clone()
+	for _, w := range witness {
+		rng.AppendScalar("witness", w)
+	}
+	var entropy [32]byte
+	if _, err := rand.Read(entropy[:]); err != nil {
+		return nil, nil, nil, err
+	}
+	rng.AppendMessage("rng", entropy[:])
+	nonces := make([]*ristretto255.Scalar, len(witness))
+	for i := range nonces {
+		nonces[i] = rng.ChallengeScalar("nonce")
+	}
+
+	commitments := r.commitments(points, nonces, nil)
+	for _, T := range commitments {
+		t.AppendElement("com", T)
+	}
+	c := t.ChallengeScalar("chal")
+
+	// s_i = k_i - c * x_i, so that sum(s * G) + c * P = sum(k * G).
+	responses := make([]*ristretto255.Scalar, len(witness))
+	for i := range responses {
+		responses[i] = ristretto255.NewScalar().Multiply(c, witness[i])
+		responses[i].Subtract(nonces[i], responses[i])
+	}
+	return commitments, c, responses, nil
+}
+
+// ProveCompact returns a CompactProof that witness satisfies r for the
+// public Elements points, indexed by ScalarVar and PointVar respectively.
+//
+// ProveCompact does not check that the witness satisfies the relation: if it
+// doesn't, the proof will fail to verify.
+func (r *Relation) ProveCompact(t *transcript.Transcript, points []*ristretto255.Element, witness []*ristretto255.Scalar) (*CompactProof, error) {
+	_, c, responses, err := r.prove(t, points, witness)
+	if err != nil {
+		return nil, err
+	}
+	return &CompactProof{Challenge: c, Responses: responses}, nil
+}
+
+// ProveBatchable is like ProveCompact, but returns a BatchableProof.
+func (r *Relation) ProveBatchable(t *transcript.Transcript, points []*ristretto255.Element, witness []*ristretto255.Scalar) (*BatchableProof, error) {
+	commitments, _, responses, err := r.prove(t, points, witness)
+	if err != nil {
+		return nil, err
+	}
+	return &BatchableProof{Commitments: commitments, Responses: responses}, nil
+}
+
+// VerifyCompact checks proof against the public Elements points.
+func (r *Relation) VerifyCompact(t *transcript.Transcript, points []*ristretto255.Element, proof *CompactProof) error {
+	if err := r.checkPoints(points); err != nil {
+		return err
+	}
+	if proof == nil || proof.Challenge == nil || slices.Contains(proof.Responses, nil) ||
+		len(proof.Responses) != len(r.scalarNames) {
+		return errVerification
+	}
+	r.absorbStatement(t, points)
+	for _, T := range r.commitments(points, proof.Responses, proof.Challenge) {
+		t.AppendElement("com", T)
+	}
+	c := t.ChallengeScalar("chal")
+	if c.Equal(proof.Challenge) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+// VerifyBatchable checks proof against the public Elements points.
+func (r *Relation) VerifyBatchable(t *transcript.Transcript, points []*ristretto255.Element, proof *BatchableProof) error {
+	return r.VerifyBatch([]*transcript.Transcript{t}, [][]*ristretto255.Element{points}, []*BatchableProof{proof})
+}
+
+// VerifyBatch checks a batch of proofs of r, each with its own transcript
+// and public Elements. All the equations of all the proofs are combined with
+// random weights and checked with a single VarTimeMultiScalarMult.
+//
+// If VerifyBatch fails, at least one of the proofs is invalid, but the error
+// does not say which.
+func (r *Relation) VerifyBatch(ts []*transcript.Transcript, points [][]*ristretto255.Element, proofs []*BatchableProof) error {
+	if len(ts) != len(proofs) || len(points) != len(proofs) {
+		return errors.New("sigma: mismatched batch lengths")
+	}
+
+	var scalars []*ristretto255.Scalar
+	var elements []*ristretto255.Element
+	for k, proof := range proofs {
+		if err := r.checkPoints(points[k]); err != nil {
+			return err
+		}
+		if proof == nil || slices.Contains(proof.Responses, nil) || slices.Contains(proof.Commitments, nil) ||
+			len(proof.Responses) != len(r.scalarNames) || len(proof.Commitments) != len(r.equations) {
+			return errVerification
+		}
+		t := ts[k]
+		r.absorbStatement(t, points[k])
+		for _, T := range proof.Commitments {
+			t.AppendElement("com", T)
+		}
+		c := t.ChallengeScalar("chal")
+
+		// For each equation, check that
+		//   sum(s * G) + c * P - T = 0
+		// weighted by a random z.
+		for j, eq := range r.equations {
+			z := scalar.MustRandom()
+			for _, tm := range eq.rhs {
+				scalars = append(scalars, ristretto255.NewScalar().Multiply(z, proof.Responses[tm.Scalar]))
+				elements = append(elements, points[k][tm.Point])
+			}
+			scalars = append(scalars, ristretto255.NewScalar().Multiply(z, c),
+				ristretto255.NewScalar().Negate(z))
+			elements = append(elements, points[k][eq.lhs], proof.Commitments[j])
+		}
+	}
+
+	check := ristretto255.NewIdentityElement().VarTimeMultiScalarMult(scalars, elements)
+	if check.Equal(ristretto255.NewIdentityElement()) != 1 {
+		return errVerification
+	}
+	return nil
+}
+
+// Bytes returns the encoding of the proof: the challenge followed by the
+// responses, for a total of 32 * (1 + NumScalars) bytes.
+func (p *CompactProof) Bytes() []byte {
+	out := make([]byte, 0, 32*(1+len(p.Responses)))
+	out = append(out, p.Challenge.Bytes()...)
+	for _, s := range p.Responses {
+		out = append(out, s.Bytes()...)
+	}
+	return out
+}
+
+// Bytes returns the encoding of the proof: the commitments followed by the
+// responses, for a total of 32 * (NumEquations + NumScalars) bytes.
+func (p *BatchableProof) Bytes() []byte {
+	out := make([]byte, 0, 32*(len(p.Commitments)+len(p.Responses)))
+	for _, T := range p.Commitments {
+		out = append(out, T.Bytes()...)
+	}
+	for _, s := range p.Responses {
+		out = append(out, s.Bytes()...)
+	}
+	return out
+}
+
+// ParseCompactProof decodes a CompactProof for r.
+func (r *Relation) ParseCompactProof(b []byte) (*CompactProof, error) {
+	if len(b) != 32*(1+len(r.scalarNames)) {
+		return nil, errFormat
+	}
+	scalars, err := decodeScalars(b)
+	if err != nil {
+		return nil, err
+	}
+	return &CompactProof{Challenge: scalars[0], Responses: scalars[1:]}, nil
+}
+
+// ParseBatchableProof decodes a BatchableProof for r.
+func (r *Relation) ParseBatchableProof(b []byte) (*BatchableProof, error) {
+	m := len(r.equations)
+	if len(b) != 32*(m+len(r.scalarNames)) {
+		return nil, errFormat
+	}
+	proof := &BatchableProof{}
+	for i := 0; i < m; i++ {
+		T, err := ristretto255.NewIdentityElement().SetCanonicalBytes(b[32*i : 32*(i+1)])
+		if err != nil {
+			return nil, errFormat
+		}
+		proof.Commitments = append(proof.Commitments, T)
+	}
+	var err error
+	if proof.Responses, err = decodeScalars(b[32*m:]); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+func decodeScalars(b []byte) ([]*ristretto255.Scalar, error) {
+	out := make([]*ristretto255.Scalar, len(b)/32)
+	for i := range out {
+		s, err := ristretto255.NewScalar().SetCanonicalBytes(b[32*i : 32*(i+1)])
+		if err != nil {
+			return nil, errFormat
+		}
+		out[i] = s
+	}
+	return out, nil
+}
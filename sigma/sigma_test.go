@@ -0,0 +1,157 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sigma
+
+import (
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+	"github.com/gtank/ristretto255/transcript"
+)
+
+func randomElement() *ristretto255.Element {
+	return ristretto255.NewIdentityElement().ScalarBaseMult(scalar.MustRandom())
+}
+
+// dleqRelation declares A = x * G and B = x * H.
+func dleqRelation() (*Relation, ScalarVar, [4]PointVar) {
+	r := NewRelation("DLEQ")
+	x := r.SecretScalar("x")
+	A, B, G, H := r.Point("A"), r.Point("B"), r.Point("G"), r.Point("H")
+	r.Constrain(A, Term{x, G})
+	r.Constrain(B, Term{x, H})
+	return r, x, [4]PointVar{A, B, G, H}
+}
+
+func dleqInstance(x *ristretto255.Scalar) []*ristretto255.Element {
+	G, H := ristretto255.NewGeneratorElement(), randomElement()
+	A := ristretto255.NewIdentityElement().ScalarMult(x, G)
+	B := ristretto255.NewIdentityElement().ScalarMult(x, H)
+	return []*ristretto255.Element{A, B, G, H}
+}
+
+func TestCompactProof(t *testing.T) {
+	r, _, _ := dleqRelation()
+	x := scalar.MustRandom()
+	points := dleqInstance(x)
+
+	proof, err := r.ProveCompact(transcript.New("test"), points, []*ristretto255.Scalar{x})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.VerifyCompact(transcript.New("test"), points, proof); err != nil {
+		t.Errorf("valid proof failed to verify: %v", err)
+	}
+
+	decoded, err := r.ParseCompactProof(proof.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.VerifyCompact(transcript.New("test"), points, decoded); err != nil {
+		t.Errorf("decoded proof failed to verify: %v", err)
+	}
+
+	if err := r.VerifyCompact(transcript.New("other"), points, proof); err == nil {
+		t.Error("proof verified with a different transcript")
+	}
+	if err := r.VerifyCompact(transcript.New("test"), points, &CompactProof{}); err == nil {
+		t.Error("zero proof verified")
+	}
+	incomplete := &CompactProof{Challenge: proof.Challenge, Responses: []*ristretto255.Scalar{nil}}
+	if err := r.VerifyCompact(transcript.New("test"), points, incomplete); err == nil {
+		t.Error("proof with a missing response verified")
+	}
+	points[1] = randomElement()
+	if err := r.VerifyCompact(transcript.New("test"), points, proof); err == nil {
+		t.Error("proof verified for a different statement")
+	}
+
+	// A proof with the wrong witness does not verify.
+	proof, err = r.ProveCompact(transcript.New("test"), points, []*ristretto255.Scalar{x})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.VerifyCompact(transcript.New("test"), points, proof); err == nil {
+		t.Error("proof for a false statement verified")
+	}
+}
+
+func TestBatchableProof(t *testing.T) {
+	// Knowledge of an opening of a Pedersen commitment C = v * G + b * H,
+	// and of the same v in V = v * G.
+	r := NewRelation("Pedersen")
+	v, b := r.SecretScalar("v"), r.SecretScalar("b")
+	C, V, G, H := r.Point("C"), r.Point("V"), r.Point("G"), r.Point("H")
+	r.Constrain(C, Term{v, G}, Term{b, H})
+	r.Constrain(V, Term{v, G})
+
+	const n = 8
+	var ts []*transcript.Transcript
+	var instances [][]*ristretto255.Element
+	var proofs []*BatchableProof
+	for i := 0; i < n; i++ {
+		vv, bb := scalar.MustRandom(), scalar.MustRandom()
+		g, h := ristretto255.NewGeneratorElement(), randomElement()
+		points := make([]*ristretto255.Element, r.NumPoints())
+		points[G], points[H] = g, h
+		points[C] = ristretto255.NewIdentityElement().MultiScalarMult(
+			[]*ristretto255.Scalar{vv, bb}, []*ristretto255.Element{g, h})
+		points[V] = ristretto255.NewIdentityElement().ScalarMult(vv, g)
+		witness := make([]*ristretto255.Scalar, r.NumScalars())
+		witness[v], witness[b] = vv, bb
+
+		proof, err := r.ProveBatchable(transcript.New("test"), points, witness)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.VerifyBatchable(transcript.New("test"), points, proof); err != nil {
+			t.Errorf("valid proof failed to verify: %v", err)
+		}
+		decoded, err := r.ParseBatchableProof(proof.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts = append(ts, transcript.New("test"))
+		instances = append(instances, points)
+		proofs = append(proofs, decoded)
+	}
+
+	clone := func() []*transcript.Transcript {
+		out := make([]*transcript.Transcript, len(ts))
+		for i := range ts {
+			out[i] = ts[i].Clone()
+		}
+		return out
+	}
+	if err := r.VerifyBatch(clone(), instances, proofs); err != nil {
+		t.Errorf("valid batch failed to verify: %v", err)
+	}
+
+	instances[3][C] = randomElement()
+	if err := r.VerifyBatch(clone(), instances, proofs); err == nil {
+		t.Error("batch with an invalid proof verified")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	r, _, _ := dleqRelation()
+	if _, err := r.ParseCompactProof(make([]byte, 32)); err == nil {
+		t.Error("accepted a short compact proof")
+	}
+	if _, err := r.ParseBatchableProof(make([]byte, 64)); err == nil {
+		t.Error("accepted a short batchable proof")
+	}
+	bad := make([]byte, 64)
+	for i := range bad {
+		bad[i] = 0xff
+	}
+	if _, err := r.ParseCompactProof(bad); err == nil {
+		t.Error("accepted non-canonical scalars")
+	}
+	if _, err := r.ProveCompact(transcript.New("test"), make([]*ristretto255.Element, 4), nil); err == nil {
+		t.Error("accepted missing inputs")
+	}
+}
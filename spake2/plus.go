@@ -0,0 +1,204 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spake2
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"errors"
+	"io"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/element"
+	"github.com/gtank/ristretto255/internal/hkdf"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// SharedKeySize is the size in bytes of the SPAKE2+ shared key (K_shared).
+const SharedKeySize = sha512.Size
+
+// VerifierRecordSize is the size in bytes of an encoded VerifierRecord.
+const VerifierRecordSize = 64
+
+// PasswordScalars returns the SPAKE2+ password scalars w0 and w1 for the
+// 128-byte output of a memory-hard function applied to the password and the
+// identities.
+func PasswordScalars(mhfOutput []byte) (w0, w1 *ristretto255.Scalar, err error) {
+	if len(mhfOutput) != 128 {
+		return nil, nil, errors.New("spake2: invalid password hash length")
+	}
+	w0, _ = ristretto255.NewScalar().SetUniformBytes(mhfOutput[:64])
+	w1, _ = ristretto255.NewScalar().SetUniformBytes(mhfOutput[64:])
+	return w0, w1, nil
+}
+
+// VerifierRecord is the registration record that the Verifier stores for a
+// Prover. It does not allow impersonating the Prover, but it does allow an
+// offline dictionary attack on the password.
+type VerifierRecord struct {
+	W0 *ristretto255.Scalar
+	L  *ristretto255.Element
+}
+
+// NewVerifierRecord returns the VerifierRecord for the password scalars w0
+// and w1.
+func NewVerifierRecord(w0, w1 *ristretto255.Scalar) *VerifierRecord {
+	return &VerifierRecord{
+		W0: ristretto255.NewScalar().Set(w0),
+		L:  ristretto255.NewIdentityElement().ScalarBaseMult(w1),
+	}
+}
+
+// Bytes returns the VerifierRecordSize-byte encoding of r.
+func (r *VerifierRecord) Bytes() []byte {
+	return append(r.W0.Bytes(), r.L.Bytes()...)
+}
+
+// SetBytes sets r to the decoded encoding b, and returns r. If b is not a
+// valid encoding, SetBytes returns nil and an error, and the receiver is
+// unchanged.
+func (r *VerifierRecord) SetBytes(b []byte) (*VerifierRecord, error) {
+	if len(b) != VerifierRecordSize {
+		return nil, errors.New("spake2: invalid verifier record length")
+	}
+	w0, err := ristretto255.NewScalar().SetCanonicalBytes(b[:32])
+	if err != nil {
+		return nil, errors.New("spake2: invalid verifier record encoding")
+	}
+	L, err := element.DecodeNonIdentity(b[32:])
+	if err != nil {
+		return nil, errors.New("spake2: invalid verifier record encoding")
+	}
+	r.W0, r.L = w0, L
+	return r, nil
+}
+
+// plusTranscript returns the SPAKE2+ transcript TT.
+func plusTranscript(context, idProver, idVerifier []byte, shareP, shareV, Z, V *ristretto255.Element, w0 *ristretto255.Scalar) []byte {
+	var tt []byte
+	tt = appendLen(tt, context)
+	tt = appendLen(tt, idProver)
+	tt = appendLen(tt, idVerifier)
+	tt = appendLen(tt, elementM.Bytes())
+	tt = appendLen(tt, elementN.Bytes())
+	tt = appendLen(tt, shareP.Bytes())
+	tt = appendLen(tt, shareV.Bytes())
+	tt = appendLen(tt, Z.Bytes())
+	tt = appendLen(tt, V.Bytes())
+	return appendLen(tt, w0.Bytes())
+}
+
+// plusKeys returns the confirmation keys and the shared key for the
+// transcript tt.
+func plusKeys(tt []byte) (confirmP, confirmV, shared []byte) {
+	kMain := sha512.Sum512(tt)
+	prk := hkdf.Extract(sha512.New, nil, kMain[:])
+	kc := hkdf.Expand(sha512.New, prk, []byte("ConfirmationKeys"), 2*sha512.Size)
+	shared = hkdf.Expand(sha512.New, prk, []byte("SharedKey"), SharedKeySize)
+	return kc[:sha512.Size], kc[sha512.Size:], shared
+}
+
+// Prover holds the secret state of the client side of a SPAKE2+ exchange.
+type Prover struct {
+	context, idProver, idVerifier []byte
+	w0, w1, x                     *ristretto255.Scalar
+	share                         *ristretto255.Element
+}
+
+// NewProver starts an exchange as the Prover, with the password scalars w0
+// and w1, and returns the share to send to the Verifier. The context and the
+// identities are optional, but must match the ones used by the Verifier. If
+// rand is nil, crypto/rand.Reader is used.
+func NewProver(rand io.Reader, context, idProver, idVerifier []byte, w0, w1 *ristretto255.Scalar) (*Prover, *ristretto255.Element, error) {
+	x, err := scalar.RandomNonZero(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, share := newProver(x, context, idProver, idVerifier, w0, w1)
+	return p, share, nil
+}
+
+func newProver(x *ristretto255.Scalar, context, idProver, idVerifier []byte, w0, w1 *ristretto255.Scalar) (*Prover, *ristretto255.Element) {
+	share := ristretto255.NewIdentityElement().MultiScalarMult(
+		[]*ristretto255.Scalar{x, w0},
+		[]*ristretto255.Element{ristretto255.NewGeneratorElement(), elementM})
+	p := &Prover{
+		context:    append([]byte(nil), context...),
+		idProver:   append([]byte(nil), idProver...),
+		idVerifier: append([]byte(nil), idVerifier...),
+		w0:         w0,
+		w1:         w1,
+		x:          x,
+		share:      share,
+	}
+	return p, share
+}
+
+// Finish processes the Verifier share and key confirmation message. On
+// success, it returns the key confirmation message to send to the Verifier,
+// and the SharedKeySize-byte shared key.
+func (p *Prover) Finish(shareV *ristretto255.Element, confirmV []byte) (confirmP, sharedKey []byte, err error) {
+	if p.x == nil {
+		return nil, nil, errors.New("spake2: exchange already finished")
+	}
+	x := p.x
+	p.x = nil
+
+	Z, err := unblind(x, p.w0, shareV, elementN)
+	if err != nil {
+		return nil, nil, err
+	}
+	V, err := unblind(p.w1, p.w0, shareV, elementN)
+	if err != nil {
+		return nil, nil, err
+	}
+	tt := plusTranscript(p.context, p.idProver, p.idVerifier, p.share, shareV, Z, V, p.w0)
+	kcP, kcV, shared := plusKeys(tt)
+	if !hmac.Equal(confirmV, mac(kcV, p.share.Bytes())) {
+		return nil, nil, errConfirmation
+	}
+	return mac(kcP, shareV.Bytes()), shared, nil
+}
+
+// Verifier holds the secret state of the server side of a SPAKE2+ exchange.
+type Verifier struct {
+	expected []byte
+	shared   []byte
+}
+
+// NewVerifier processes the Prover share for the registration record rec,
+// and returns the share and the key confirmation message to send to the
+// Prover. The other arguments are as for NewProver.
+func NewVerifier(rand io.Reader, context, idProver, idVerifier []byte, rec *VerifierRecord, shareP *ristretto255.Element) (v *Verifier, shareV *ristretto255.Element, confirmV []byte, err error) {
+	y, err := scalar.RandomNonZero(rand)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return newVerifier(y, context, idProver, idVerifier, rec, shareP)
+}
+
+func newVerifier(y *ristretto255.Scalar, context, idProver, idVerifier []byte, rec *VerifierRecord, shareP *ristretto255.Element) (*Verifier, *ristretto255.Element, []byte, error) {
+	Z, err := unblind(y, rec.W0, shareP, elementM)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	V := ristretto255.NewIdentityElement().ScalarMult(y, rec.L)
+	shareV := ristretto255.NewIdentityElement().MultiScalarMult(
+		[]*ristretto255.Scalar{y, rec.W0},
+		[]*ristretto255.Element{ristretto255.NewGeneratorElement(), elementN})
+	tt := plusTranscript(context, idProver, idVerifier, shareP, shareV, Z, V, rec.W0)
+	kcP, kcV, shared := plusKeys(tt)
+	v := &Verifier{expected: mac(kcP, shareV.Bytes()), shared: shared}
+	return v, shareV, mac(kcV, shareP.Bytes()), nil
+}
+
+// Finish checks the Prover key confirmation message, and returns the
+// SharedKeySize-byte shared key.
+func (v *Verifier) Finish(confirmP []byte) (sharedKey []byte, err error) {
+	if !hmac.Equal(confirmP, v.expected) {
+		return nil, errConfirmation
+	}
+	return v.shared, nil
+}
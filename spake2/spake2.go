@@ -0,0 +1,193 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package spake2 implements the SPAKE2 balanced password-authenticated key
+// exchange from RFC 9382 and its augmented variant SPAKE2+ from RFC 9383,
+// instantiated over ristretto255 with SHA-512, HKDF-SHA512 and HMAC-SHA512.
+//
+// The M and N elements are derived with hash_to_ristretto255, so that their
+// discrete logarithms are unknown. Since ristretto255 has a prime order, the
+// cofactor h is 1.
+//
+// The password scalars must be derived from the password with a memory-hard
+// function such as Argon2id, keyed with the party identities as the RFCs
+// specify, and reduced with PasswordScalar or PasswordScalars.
+package spake2
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/element"
+	"github.com/gtank/ristretto255/internal/h2c"
+	"github.com/gtank/ristretto255/internal/hkdf"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// KeySize is the size in bytes of the SPAKE2 shared key (Ke).
+const KeySize = sha512.Size / 2
+
+const generatorDST = "ristretto255-SPAKE2-v1-generators"
+
+// The M and N elements, with unknown discrete logarithms with respect to the
+// generator and to each other.
+var (
+	elementM = h2c.HashToElement([]byte("M"), []byte(generatorDST))
+	elementN = h2c.HashToElement([]byte("N"), []byte(generatorDST))
+)
+
+var (
+	errInvalidShare = errors.New("spake2: invalid share")
+	errConfirmation = errors.New("spake2: key confirmation failed")
+)
+
+// PasswordScalar returns the SPAKE2 password scalar w for the 64-byte output
+// of a memory-hard function applied to the password.
+func PasswordScalar(mhfOutput []byte) (*ristretto255.Scalar, error) {
+	if len(mhfOutput) != 64 {
+		return nil, errors.New("spake2: invalid password hash length")
+	}
+	return ristretto255.NewScalar().SetUniformBytes(mhfOutput)
+}
+
+// appendLen appends b to tt, prefixed with its length as a little-endian
+// 8-byte integer, as in the RFC 9382 and RFC 9383 transcripts.
+func appendLen(tt, b []byte) []byte {
+	tt = binary.LittleEndian.AppendUint64(tt, uint64(len(b)))
+	return append(tt, b...)
+}
+
+func mac(key, msg []byte) []byte {
+	h := hmac.New(sha512.New, key)
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// unblind returns x * (S - w * B), or an error if S or the result is the
+// identity element.
+func unblind(x, w *ristretto255.Scalar, S, B *ristretto255.Element) (*ristretto255.Element, error) {
+	if S == nil || element.IsIdentity(S) {
+		return nil, errInvalidShare
+	}
+	T := ristretto255.NewIdentityElement().ScalarMult(w, B)
+	T.Subtract(S, T)
+	T.ScalarMult(x, T)
+	if element.IsIdentity(T) {
+		return nil, errInvalidShare
+	}
+	return T, nil
+}
+
+// Exchange holds the secret state of one party of a SPAKE2 exchange.
+type Exchange struct {
+	isA           bool
+	w, x          *ristretto255.Scalar
+	idA, idB, aad []byte
+	share         *ristretto255.Element
+
+	expected []byte
+	key      []byte
+}
+
+// NewA starts an exchange as party A, with the password scalar w, and
+// returns the share pA to send to B. The identities and the additional
+// authenticated data aad are optional, but must match the ones used by B. If
+// rand is nil, crypto/rand.Reader is used.
+func NewA(rand io.Reader, w *ristretto255.Scalar, idA, idB, aad []byte) (*Exchange, *ristretto255.Element, error) {
+	x, err := scalar.RandomNonZero(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	e, share := newExchange(true, x, w, idA, idB, aad)
+	return e, share, nil
+}
+
+// NewB starts an exchange as party B, with the password scalar w, and
+// returns the share pB to send to A. The other arguments are as for NewA.
+func NewB(rand io.Reader, w *ristretto255.Scalar, idA, idB, aad []byte) (*Exchange, *ristretto255.Element, error) {
+	y, err := scalar.RandomNonZero(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	e, share := newExchange(false, y, w, idA, idB, aad)
+	return e, share, nil
+}
+
+func newExchange(isA bool, x, w *ristretto255.Scalar, idA, idB, aad []byte) (*Exchange, *ristretto255.Element) {
+	blinding := elementN
+	if isA {
+		blinding = elementM
+	}
+	share := ristretto255.NewIdentityElement().MultiScalarMult(
+		[]*ristretto255.Scalar{x, w},
+		[]*ristretto255.Element{ristretto255.NewGeneratorElement(), blinding})
+	e := &Exchange{
+		isA:   isA,
+		w:     w,
+		x:     x,
+		idA:   append([]byte(nil), idA...),
+		idB:   append([]byte(nil), idB...),
+		aad:   append([]byte(nil), aad...),
+		share: share,
+	}
+	return e, share
+}
+
+// Finish processes the share of the other party, and returns the key
+// confirmation message to send to it. The shared key is only returned by
+// Confirm, after the other party's confirmation message is checked.
+func (e *Exchange) Finish(peerShare *ristretto255.Element) (confirmation []byte, err error) {
+	if e.x == nil {
+		return nil, errors.New("spake2: exchange already finished")
+	}
+	x := e.x
+	e.x = nil
+
+	pA, pB, unblinding := e.share, peerShare, elementN
+	if !e.isA {
+		pA, pB, unblinding = peerShare, e.share, elementM
+	}
+	K, err := unblind(x, e.w, peerShare, unblinding)
+	if err != nil {
+		return nil, err
+	}
+
+	var tt []byte
+	tt = appendLen(tt, e.idA)
+	tt = appendLen(tt, e.idB)
+	tt = appendLen(tt, pA.Bytes())
+	tt = appendLen(tt, pB.Bytes())
+	tt = appendLen(tt, K.Bytes())
+	tt = appendLen(tt, e.w.Bytes())
+
+	h := sha512.Sum512(tt)
+	ke, ka := h[:KeySize], h[KeySize:]
+	prk := hkdf.Extract(sha512.New, nil, ka)
+	kc := hkdf.Expand(sha512.New, prk, append([]byte("ConfirmationKeys"), e.aad...), len(ka)*2)
+	cA, cB := mac(kc[:len(ka)], tt), mac(kc[len(ka):], tt)
+
+	e.key = append([]byte(nil), ke...)
+	if e.isA {
+		e.expected = cB
+		return cA, nil
+	}
+	e.expected = cA
+	return cB, nil
+}
+
+// Confirm checks the key confirmation message of the other party, and
+// returns the KeySize-byte shared key.
+func (e *Exchange) Confirm(peerConfirmation []byte) (key []byte, err error) {
+	if e.expected == nil {
+		return nil, errors.New("spake2: exchange not finished")
+	}
+	if !hmac.Equal(peerConfirmation, e.expected) {
+		return nil, errConfirmation
+	}
+	return e.key, nil
+}
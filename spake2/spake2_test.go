@@ -0,0 +1,208 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spake2
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+	"github.com/gtank/ristretto255/internal/scalar"
+)
+
+// testScalar stands in for a memory-hard function in the tests.
+func testScalar(t *testing.T, password string) *ristretto255.Scalar {
+	h := sha512.Sum512([]byte(password))
+	w, err := PasswordScalar(h[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func testScalars(t *testing.T, password string) (w0, w1 *ristretto255.Scalar) {
+	h0 := sha512.Sum512([]byte("w0" + password))
+	h1 := sha512.Sum512([]byte("w1" + password))
+	w0, w1, err := PasswordScalars(append(h0[:], h1[:]...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w0, w1
+}
+
+func TestGenerators(t *testing.T) {
+	identity := ristretto255.NewIdentityElement()
+	G := ristretto255.NewGeneratorElement()
+	if elementM.Equal(identity) == 1 || elementN.Equal(identity) == 1 ||
+		elementM.Equal(elementN) == 1 || elementM.Equal(G) == 1 || elementN.Equal(G) == 1 {
+		t.Error("M and N are not independent generators")
+	}
+}
+
+func spake2(t *testing.T, wA, wB *ristretto255.Scalar, aadA, aadB []byte) (keyA, keyB []byte, errA, errB error) {
+	t.Helper()
+	a, pA, err := NewA(nil, wA, []byte("client"), []byte("server"), aadA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, pB, err := NewB(nil, wB, []byte("client"), []byte("server"), aadB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cA, err := a.Finish(pB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cB, err := b.Finish(pA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyA, errA = a.Confirm(cB)
+	keyB, errB = b.Confirm(cA)
+	return
+}
+
+func TestSPAKE2(t *testing.T) {
+	w := testScalar(t, "password")
+	keyA, keyB, errA, errB := spake2(t, w, w, []byte("aad"), []byte("aad"))
+	if errA != nil || errB != nil {
+		t.Fatalf("exchange failed: %v, %v", errA, errB)
+	}
+	if !bytes.Equal(keyA, keyB) || len(keyA) != KeySize {
+		t.Error("keys do not match")
+	}
+
+	_, _, errA, errB = spake2(t, w, testScalar(t, "passw0rd"), nil, nil)
+	if errA == nil || errB == nil {
+		t.Error("exchange with different passwords succeeded")
+	}
+	_, _, errA, errB = spake2(t, w, w, []byte("aad"), []byte("other"))
+	if errA == nil || errB == nil {
+		t.Error("exchange with different AAD succeeded")
+	}
+}
+
+func TestSPAKE2FixedScalars(t *testing.T) {
+	// With x = 2 and y = 3, both parties compute K = 6 * G.
+	w := testScalar(t, "password")
+	a, pA := newExchange(true, scalar.FromUint64(2), w, nil, nil, nil)
+	b, pB := newExchange(false, scalar.FromUint64(3), w, nil, nil, nil)
+	K, err := unblind(a.x, w, pB, elementN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ristretto255.NewIdentityElement().ScalarBaseMult(scalar.FromUint64(6))
+	if K.Equal(want) != 1 {
+		t.Error("unexpected shared element for A")
+	}
+	if K, err = unblind(b.x, w, pA, elementM); err != nil || K.Equal(want) != 1 {
+		t.Error("unexpected shared element for B")
+	}
+
+	cA, err := a.Finish(pB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Finish(pB); err == nil {
+		t.Error("exchange was finished twice")
+	}
+	if _, err := b.Confirm(cA); err == nil {
+		t.Error("confirmed an unfinished exchange")
+	}
+}
+
+func TestSPAKE2InvalidShare(t *testing.T) {
+	w := testScalar(t, "password")
+	a, _, err := NewA(nil, w, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Finish(ristretto255.NewIdentityElement()); err == nil {
+		t.Error("accepted the identity element")
+	}
+
+	// w * N unblinds to the identity element.
+	a, _, err = NewA(nil, w, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Finish(ristretto255.NewIdentityElement().ScalarMult(w, elementN)); err == nil {
+		t.Error("accepted a share that unblinds to the identity element")
+	}
+}
+
+func spake2Plus(t *testing.T, proverPassword string, rec *VerifierRecord) (keyP, keyV []byte, errP, errV error) {
+	t.Helper()
+	w0, w1 := testScalars(t, proverPassword)
+	p, shareP, err := NewProver(nil, []byte("context"), []byte("client"), []byte("server"), w0, w1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, shareV, confirmV, err := NewVerifier(nil, []byte("context"), []byte("client"), []byte("server"), rec, shareP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirmP, keyP, errP := p.Finish(shareV, confirmV)
+	if errP != nil {
+		return nil, nil, errP, nil
+	}
+	keyV, errV = v.Finish(confirmP)
+	return keyP, keyV, nil, errV
+}
+
+func TestSPAKE2Plus(t *testing.T) {
+	rec, err := new(VerifierRecord).SetBytes(NewVerifierRecord(testScalars(t, "password")).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyP, keyV, errP, errV := spake2Plus(t, "password", rec)
+	if errP != nil || errV != nil {
+		t.Fatalf("exchange failed: %v, %v", errP, errV)
+	}
+	if !bytes.Equal(keyP, keyV) || len(keyP) != SharedKeySize {
+		t.Error("keys do not match")
+	}
+
+	if _, _, errP, _ := spake2Plus(t, "passw0rd", rec); errP == nil {
+		t.Error("exchange with a different password succeeded")
+	}
+}
+
+func TestSPAKE2PlusWrongW1(t *testing.T) {
+	// A party that knows w0 but not w1, such as an attacker holding a stolen
+	// record, cannot authenticate as the Prover.
+	w0, w1 := testScalars(t, "password")
+	rec := NewVerifierRecord(w0, w1)
+	p, shareP := newProver(scalar.MustRandom(), nil, nil, nil, w0, scalar.MustRandom())
+	v, shareV, confirmV, err := NewVerifier(nil, nil, nil, nil, rec, shareP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := p.Finish(shareV, confirmV); err == nil {
+		t.Error("Prover without w1 accepted the Verifier confirmation")
+	}
+	if _, err := v.Finish(make([]byte, sha512.Size)); err == nil {
+		t.Error("Verifier accepted a forged confirmation")
+	}
+}
+
+func TestSPAKE2PlusInvalidShare(t *testing.T) {
+	w0, w1 := testScalars(t, "password")
+	rec := NewVerifierRecord(w0, w1)
+	if _, _, _, err := NewVerifier(nil, nil, nil, nil, rec, ristretto255.NewIdentityElement()); err == nil {
+		t.Error("Verifier accepted the identity element")
+	}
+	p, _, err := NewProver(nil, nil, nil, nil, w0, w1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := p.Finish(ristretto255.NewIdentityElement(), nil); err == nil {
+		t.Error("Prover accepted the identity element")
+	}
+	if _, err := new(VerifierRecord).SetBytes(make([]byte, VerifierRecordSize)); err == nil {
+		t.Error("decoded a record with the identity element")
+	}
+}
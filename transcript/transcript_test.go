@@ -0,0 +1,60 @@
+// Copyright 2026 George Tankersley. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transcript
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gtank/ristretto255"
+)
+
+func TestTranscriptDeterminism(t *testing.T) {
+	build := func(msg string) *Transcript {
+		tr := New("test protocol")
+		tr.AppendMessage("some label", []byte(msg))
+		tr.AppendUint64("n", 42)
+		tr.AppendElement("G", ristretto255.NewGeneratorElement())
+		return tr
+	}
+
+	c1 := build("some data").ChallengeBytes("challenge", 100)
+	c2 := build("some data").ChallengeBytes("challenge", 100)
+	if !bytes.Equal(c1, c2) {
+		t.Error("identical transcripts produced different challenges")
+	}
+	c3 := build("other data").ChallengeBytes("challenge", 100)
+	if bytes.Equal(c1, c3) {
+		t.Error("different transcripts produced the same challenge")
+	}
+	if bytes.Equal(c1[:64], build("some data").ChallengeBytes("challenge", 64)) {
+		t.Error("challenge length is not bound to the output")
+	}
+
+	tr := build("some data")
+	clone := tr.Clone()
+	a := tr.ChallengeScalar("x")
+	b := tr.ChallengeScalar("x")
+	if a.Equal(b) == 1 {
+		t.Error("consecutive challenges should differ")
+	}
+	if clone.ChallengeScalar("x").Equal(a) != 1 {
+		t.Error("clone diverged from the original")
+	}
+}
+
+func TestValidateAndAppendElement(t *testing.T) {
+	tr := New("test protocol")
+	before := tr.Clone()
+	if err := tr.ValidateAndAppendElement("P", ristretto255.NewIdentityElement()); err == nil {
+		t.Fatal("identity element was accepted")
+	}
+	if *tr != *before {
+		t.Error("failed validation modified the transcript")
+	}
+	if err := tr.ValidateAndAppendElement("P", ristretto255.NewGeneratorElement()); err != nil {
+		t.Fatal(err)
+	}
+}